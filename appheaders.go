@@ -0,0 +1,132 @@
+package fingerprint
+
+import (
+	"fmt"
+
+	"github.com/vistone/fingerprint/profiles"
+)
+
+// appHeaderDefaults 应用 profile（Metadata.App 不为空）的默认 headers，由应用使用的网络库决定，
+// 与 User-Agent 中的浏览器版本无关：应用不发送 client hints、Sec-Fetch-*、Upgrade-Insecure-Requests 和 Priority
+type appHeaderDefaults struct {
+	accept         string
+	acceptEncoding string
+	languageFormat languageFormat
+	order          []string // header 顺序（小写名称）
+}
+
+var (
+	// okHTTPHeaders Android 应用的 OkHttp：BridgeInterceptor 在应用设置的 headers 之后补充 Accept-Encoding、Cookie 和 User-Agent
+	okHTTPHeaders = appHeaderDefaults{
+		accept:         "*/*",
+		acceptEncoding: "gzip",
+		languageFormat: languageChromium,
+		order: []string{
+			"accept",
+			"accept-language",
+			"content-type",
+			"origin",
+			"referer",
+			"accept-encoding",
+			"cookie",
+			"user-agent",
+		},
+	}
+
+	// cfNetworkHeaders iOS 应用的 NSURLSession（CFNetwork），Accept-Language 与系统的首选语言一致
+	cfNetworkHeaders = appHeaderDefaults{
+		accept:         "*/*",
+		acceptEncoding: "gzip, deflate, br",
+		languageFormat: languageSafari,
+		order: []string{
+			"accept",
+			"content-type",
+			"origin",
+			"referer",
+			"cookie",
+			"user-agent",
+			"accept-language",
+			"accept-encoding",
+		},
+	}
+
+	// httpLibraryHeaders 桌面平台上的 HTTP 库（如 Python requests）
+	httpLibraryHeaders = appHeaderDefaults{
+		accept:         "*/*",
+		acceptEncoding: "gzip, deflate",
+		languageFormat: languageChromium,
+		order: []string{
+			"user-agent",
+			"accept-encoding",
+			"accept",
+			"cookie",
+			"content-type",
+			"origin",
+			"referer",
+			"accept-language",
+		},
+	}
+)
+
+// 应用网络库的版本，用于生成应用 profile 的 User-Agent
+const (
+	okHTTPVersion         = "4.12.0"
+	cfNetworkVersion      = "1490.0.4" // iOS 17.0
+	darwinVersion         = "23.0.0"   // iOS 17.0
+	pythonRequestsVersion = "2.31.0"
+)
+
+// okHTTPApp 直接使用 OkHttp 默认 User-Agent 的应用 profile（Metadata.App）
+const okHTTPApp = "okhttp4"
+
+// appIdentities 应用 User-Agent 中的产品名称和版本，未列出的应用使用 Metadata.App 和版本 1.0
+var appIdentities = map[string]struct{ name, version string }{
+	"zalando":   {"Zalando", "24.30.0"},
+	"nike":      {"Nike", "24.32.1"},
+	"mms":       {"MMS", "3.2.1"},
+	"mesh":      {"Mesh", "2.14.0"},
+	"confirmed": {"Confirmed", "5.6.2"},
+}
+
+// appUserAgent 根据应用 profile 的 metadata 生成其网络库的 User-Agent，与 generateAppHeaders 生成的 headers 对应：
+// Android 为 OkHttp（如 "okhttp/4.12.0"、"Nike/24.32.1 okhttp/4.12.0"），
+// iOS 为 CFNetwork（如 "Nike/24.32.1 CFNetwork/1490.0.4 Darwin/23.0.0"），桌面平台为 Python requests
+func appUserAgent(metadata profiles.Metadata) string {
+	app, ok := appIdentities[metadata.App]
+	if !ok {
+		app.name, app.version = metadata.App, "1.0"
+	}
+	switch metadata.Platform {
+	case profiles.PlatformIOS, profiles.PlatformIPadOS:
+		return fmt.Sprintf("%s/%s CFNetwork/%s Darwin/%s", app.name, app.version, cfNetworkVersion, darwinVersion)
+	case profiles.PlatformAndroid:
+		if metadata.App == okHTTPApp {
+			return "okhttp/" + okHTTPVersion
+		}
+		return fmt.Sprintf("%s/%s okhttp/%s", app.name, app.version, okHTTPVersion)
+	}
+	return "python-requests/" + pythonRequestsVersion
+}
+
+// appHeaderDefaultsFor 返回平台上应用网络库的默认 headers
+func appHeaderDefaultsFor(platform profiles.Platform) appHeaderDefaults {
+	switch platform {
+	case profiles.PlatformIOS, profiles.PlatformIPadOS:
+		return cfNetworkHeaders
+	case profiles.PlatformAndroid:
+		return okHTTPHeaders
+	}
+	return httpLibraryHeaders
+}
+
+// generateAppHeaders 生成应用 profile 的 headers，Accept-Language 由 locales 生成
+func generateAppHeaders(userAgent string, platform profiles.Platform, locales []string) *HTTPHeaders {
+	defaults := appHeaderDefaultsFor(platform)
+	return &HTTPHeaders{
+		UserAgent:      userAgent,
+		Accept:         defaults.accept,
+		AcceptEncoding: defaults.acceptEncoding,
+		AcceptLanguage: formatAcceptLanguage(defaults.languageFormat, locales),
+		HeaderOrder:    append([]string(nil), defaults.order...),
+	}
+}
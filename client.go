@@ -0,0 +1,433 @@
+package fingerprint
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	http "github.com/bogdanfinn/fhttp"
+	"github.com/bogdanfinn/fhttp/http2"
+	tls "github.com/bogdanfinn/utls"
+)
+
+// ClientOption NewHTTPClient 的可选配置
+type ClientOption func(*clientOptions)
+
+// clientOptions HTTP 客户端配置
+type clientOptions struct {
+	timeout            time.Duration
+	insecureSkipVerify bool
+	rootCAs            *x509.CertPool
+	forceHTTP1         bool
+	followRedirects    bool
+	cookieJar          http.CookieJar
+	dialContext        func(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+// WithTimeout 设置请求总超时时间（0 表示不限制）
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithInsecureSkipVerify 跳过服务端证书校验（仅用于测试环境）
+func WithInsecureSkipVerify() ClientOption {
+	return func(o *clientOptions) {
+		o.insecureSkipVerify = true
+	}
+}
+
+// WithRootCAs 设置用于校验服务端证书的根证书池
+func WithRootCAs(pool *x509.CertPool) ClientOption {
+	return func(o *clientOptions) {
+		o.rootCAs = pool
+	}
+}
+
+// WithForceHTTP1 强制使用 HTTP/1.1（ALPN 中只保留 http/1.1）
+func WithForceHTTP1() ClientOption {
+	return func(o *clientOptions) {
+		o.forceHTTP1 = true
+	}
+}
+
+// WithNotFollowRedirects 不自动跟随重定向，直接返回 3xx 响应
+func WithNotFollowRedirects() ClientOption {
+	return func(o *clientOptions) {
+		o.followRedirects = false
+	}
+}
+
+// WithCookieJar 设置 Cookie 容器
+func WithCookieJar(jar http.CookieJar) ClientOption {
+	return func(o *clientOptions) {
+		o.cookieJar = jar
+	}
+}
+
+// WithDialContext 自定义 TCP 拨号函数（例如通过代理或指定本地 IP 出口）
+func WithDialContext(dial func(ctx context.Context, network, addr string) (net.Conn, error)) ClientOption {
+	return func(o *clientOptions) {
+		o.dialContext = dial
+	}
+}
+
+// NewHTTPClient 根据 ClientProfile 创建一个可直接使用的 fhttp 客户端
+// TLS 层使用 profile 的 ClientHello，HTTP/2 层使用 profile 的 SETTINGS（含顺序）、
+// WINDOW_UPDATE、PRIORITY 帧以及伪头部顺序，协议由 ALPN 协商结果决定
+func NewHTTPClient(profile ClientProfile, opts ...ClientOption) (*http.Client, error) {
+	if profile.GetClientHelloStr() == "" {
+		return nil, fmt.Errorf("profile is invalid (empty ClientHelloStr)")
+	}
+
+	options := &clientOptions{
+		timeout:         30 * time.Second,
+		followRedirects: true,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.dialContext == nil {
+		dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+		options.dialContext = dialer.DialContext
+	}
+
+	client := &http.Client{
+		Transport: newProfileRoundTripper(profile, options),
+		Timeout:   options.timeout,
+		Jar:       options.cookieJar,
+	}
+	if !options.followRedirects {
+		client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+
+	return client, nil
+}
+
+// profileRoundTripper 按目标地址缓存 Transport，首次连接时根据 ALPN 选择 HTTP/2 或 HTTP/1.1
+type profileRoundTripper struct {
+	profile ClientProfile
+	options *clientOptions
+
+	mu          sync.Mutex
+	transports  map[string]http.RoundTripper
+	probes      map[string]*dialCall // 正在进行的协议探测，同一地址只探测一次
+	cachedConns map[string]net.Conn  // ALPN 探测时建立的连接，交给对应 Transport 复用
+	h2Pools     []*h2ConnPool
+	plain       *http.Transport // 明文 http:// 请求使用
+}
+
+// dialCall 一次正在进行的拨号，done 关闭后 err 可读
+type dialCall struct {
+	done chan struct{}
+	err  error
+}
+
+func newProfileRoundTripper(profile ClientProfile, options *clientOptions) *profileRoundTripper {
+	return &profileRoundTripper{
+		profile:     profile,
+		options:     options,
+		transports:  make(map[string]http.RoundTripper),
+		probes:      make(map[string]*dialCall),
+		cachedConns: make(map[string]net.Conn),
+		plain: &http.Transport{
+			DialContext: options.dialContext,
+		},
+	}
+}
+
+// RoundTrip 实现 http.RoundTripper
+func (rt *profileRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme == "http" {
+		return rt.plain.RoundTrip(req)
+	}
+
+	addr := requestAddr(req)
+	transport, err := rt.getTransport(req.Context(), addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return transport.RoundTrip(req)
+}
+
+// CloseIdleConnections 关闭所有空闲连接
+func (rt *profileRoundTripper) CloseIdleConnections() {
+	rt.mu.Lock()
+	conns := rt.cachedConns
+	rt.cachedConns = make(map[string]net.Conn)
+	transports := make([]http.RoundTripper, 0, len(rt.transports))
+	for _, transport := range rt.transports {
+		transports = append(transports, transport)
+	}
+	pools := append([]*h2ConnPool(nil), rt.h2Pools...)
+	rt.mu.Unlock()
+
+	for _, conn := range conns {
+		_ = conn.Close()
+	}
+	for _, transport := range transports {
+		if closer, ok := transport.(interface{ CloseIdleConnections() }); ok {
+			closer.CloseIdleConnections()
+		}
+	}
+	for _, pool := range pools {
+		pool.closeIdleConnections()
+	}
+	rt.plain.CloseIdleConnections()
+}
+
+// getTransport 获取目标地址的 Transport，不存在时通过一次 TLS 握手探测协议
+// 拨号和握手在锁外进行，同一地址的并发请求等待同一次探测，不会阻塞其他地址的请求
+func (rt *profileRoundTripper) getTransport(ctx context.Context, addr string) (http.RoundTripper, error) {
+	for {
+		rt.mu.Lock()
+		if transport, ok := rt.transports[addr]; ok {
+			rt.mu.Unlock()
+			return transport, nil
+		}
+		call, probing := rt.probes[addr]
+		if !probing {
+			call = &dialCall{done: make(chan struct{})}
+			rt.probes[addr] = call
+		}
+		rt.mu.Unlock()
+
+		if !probing {
+			call.err = rt.probe(ctx, addr)
+			rt.mu.Lock()
+			delete(rt.probes, addr)
+			rt.mu.Unlock()
+			close(call.done)
+			if call.err != nil {
+				return nil, call.err
+			}
+			continue
+		}
+
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		// 探测因发起请求的 context 取消而失败时，由当前请求重新探测
+		if call.err != nil && !errors.Is(call.err, context.Canceled) && !errors.Is(call.err, context.DeadlineExceeded) {
+			return nil, call.err
+		}
+	}
+}
+
+// probe 建立一次 TLS 连接，按 ALPN 协商结果创建并发布地址对应的 Transport
+func (rt *profileRoundTripper) probe(ctx context.Context, addr string) error {
+	conn, err := rt.dialTLS(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	var transport http.RoundTripper
+	if conn.ConnectionState().NegotiatedProtocol == http2.NextProtoTLS {
+		transport = rt.newHTTP2Transport()
+	} else {
+		transport = rt.newHTTP1Transport()
+	}
+
+	rt.mu.Lock()
+	rt.cachedConns[addr] = conn
+	rt.transports[addr] = transport
+	rt.mu.Unlock()
+	return nil
+}
+
+// newHTTP2Transport 创建应用 profile HTTP/2 参数的 Transport
+// 连接由 h2ConnPool 使用请求的 context 建立，请求的超时和取消对重新拨号同样有效
+func (rt *profileRoundTripper) newHTTP2Transport() *http2.Transport {
+	transport := &http2.Transport{
+		Settings:          rt.profile.GetSettings(),
+		SettingsOrder:     rt.profile.GetSettingsOrder(),
+		ConnectionFlow:    rt.profile.GetConnectionFlow(),
+		Priorities:        rt.profile.GetPriorities(),
+		HeaderPriority:    rt.profile.GetHeaderPriority(),
+		PseudoHeaderOrder: rt.profile.GetPseudoHeaderOrder(),
+		IdleConnTimeout:   90 * time.Second,
+	}
+	pool := &h2ConnPool{rt: rt, transport: transport, conns: make(map[string][]*http2.ClientConn), dialing: make(map[string]*dialCall)}
+	transport.ConnPool = pool
+
+	rt.mu.Lock()
+	rt.h2Pools = append(rt.h2Pools, pool)
+	rt.mu.Unlock()
+	return transport
+}
+
+// newHTTP1Transport 创建通过 uTLS 拨号的 HTTP/1.1 Transport
+func (rt *profileRoundTripper) newHTTP1Transport() *http.Transport {
+	return &http.Transport{
+		DialContext: rt.options.dialContext,
+		DialTLSContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return rt.takeOrDialTLS(ctx, network, addr)
+		},
+		IdleConnTimeout: 90 * time.Second,
+	}
+}
+
+// takeOrDialTLS 优先复用协议探测时建立的连接，否则重新拨号
+func (rt *profileRoundTripper) takeOrDialTLS(ctx context.Context, network, addr string) (net.Conn, error) {
+	rt.mu.Lock()
+	conn, ok := rt.cachedConns[addr]
+	if ok {
+		delete(rt.cachedConns, addr)
+	}
+	rt.mu.Unlock()
+
+	if ok {
+		return conn, nil
+	}
+	return rt.dialTLS(ctx, network, addr)
+}
+
+// h2ConnPool HTTP/2 连接池，与 http2 包的默认连接池相同，但使用请求的 context 拨号
+// （fhttp 的 http2.Transport 只有不带 context 的 DialTLS）
+type h2ConnPool struct {
+	rt        *profileRoundTripper
+	transport *http2.Transport
+
+	mu      sync.Mutex
+	conns   map[string][]*http2.ClientConn
+	dialing map[string]*dialCall
+}
+
+// GetClientConn 实现 http2.ClientConnPool，返回地址上可以发起新请求的连接，没有时使用 req 的 context 拨号
+func (p *h2ConnPool) GetClientConn(req *http.Request, addr string) (*http2.ClientConn, error) {
+	ctx := req.Context()
+	for {
+		p.mu.Lock()
+		for _, cc := range p.conns[addr] {
+			if cc.CanTakeNewRequest() {
+				p.mu.Unlock()
+				return cc, nil
+			}
+		}
+		call, dialing := p.dialing[addr]
+		if !dialing {
+			call = &dialCall{done: make(chan struct{})}
+			p.dialing[addr] = call
+		}
+		p.mu.Unlock()
+
+		if !dialing {
+			cc, err := p.dial(ctx, addr)
+			p.mu.Lock()
+			delete(p.dialing, addr)
+			if err == nil {
+				p.conns[addr] = append(p.conns[addr], cc)
+			}
+			p.mu.Unlock()
+			call.err = err
+			close(call.done)
+			if err != nil {
+				return nil, err
+			}
+			return cc, nil
+		}
+
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if call.err != nil && !errors.Is(call.err, context.Canceled) && !errors.Is(call.err, context.DeadlineExceeded) {
+			return nil, call.err
+		}
+	}
+}
+
+// dial 建立（或取出协议探测时建立的）TLS 连接并完成 HTTP/2 连接前言
+func (p *h2ConnPool) dial(ctx context.Context, addr string) (*http2.ClientConn, error) {
+	conn, err := p.rt.takeOrDialTLS(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	cc, err := p.transport.NewClientConn(conn)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return cc, nil
+}
+
+// MarkDead 实现 http2.ClientConnPool，从连接池中移除连接
+func (p *h2ConnPool) MarkDead(cc *http2.ClientConn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for addr, conns := range p.conns {
+		for i, c := range conns {
+			if c == cc {
+				p.conns[addr] = append(conns[:i:i], conns[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// closeIdleConnections 从连接池中移除所有连接并优雅关闭：空闲连接立即关闭，进行中的请求完成后关闭
+func (p *h2ConnPool) closeIdleConnections() {
+	p.mu.Lock()
+	conns := p.conns
+	p.conns = make(map[string][]*http2.ClientConn)
+	p.mu.Unlock()
+
+	for _, list := range conns {
+		for _, cc := range list {
+			go func(cc *http2.ClientConn) { _ = cc.Shutdown(context.Background()) }(cc)
+		}
+	}
+}
+
+// dialTLS 建立 TCP 连接并使用 profile 的 ClientHello 完成 TLS 握手
+func (rt *profileRoundTripper) dialTLS(ctx context.Context, network, addr string) (*tls.UConn, error) {
+	rawConn, err := rt.options.dialContext(ctx, network, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		_ = rawConn.Close()
+		return nil, err
+	}
+
+	config := &tls.Config{
+		ServerName:         host,
+		InsecureSkipVerify: rt.options.insecureSkipVerify,
+		RootCAs:            rt.options.rootCAs,
+		OmitEmptyPsk:       true,
+	}
+
+	conn := tls.UClient(rawConn, config, rt.profile.GetClientHelloId(), false, rt.options.forceHTTP1, true)
+	if err := conn.HandshakeContext(ctx); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("tls handshake with %s failed: %w", addr, err)
+	}
+
+	return conn, nil
+}
+
+// requestAddr 返回请求的 host:port，未指定端口时按 scheme 补全
+func requestAddr(req *http.Request) string {
+	host := req.URL.Hostname()
+	port := req.URL.Port()
+	if port == "" {
+		port = "443"
+		if req.URL.Scheme == "http" {
+			port = "80"
+		}
+	}
+	return net.JoinHostPort(host, port)
+}
@@ -0,0 +1,295 @@
+package fingerprint
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/vistone/fingerprint/internal/utils"
+)
+
+// Client hints 的 header 名称（小写）
+const (
+	hintUA              = "sec-ch-ua"
+	hintMobile          = "sec-ch-ua-mobile"
+	hintPlatform        = "sec-ch-ua-platform"
+	hintFullVersionList = "sec-ch-ua-full-version-list"
+	hintPlatformVersion = "sec-ch-ua-platform-version"
+	hintArch            = "sec-ch-ua-arch"
+	hintBitness         = "sec-ch-ua-bitness"
+	hintModel           = "sec-ch-ua-model"
+	hintWoW64           = "sec-ch-ua-wow64"
+	hintFormFactors     = "sec-ch-ua-form-factors"
+)
+
+// chromeFullVersions Chrome 各主版本的稳定版完整版本号，用于 Sec-CH-UA-Full-Version-List
+var chromeFullVersions = map[int]string{
+	103: "103.0.5060.134",
+	104: "104.0.5112.102",
+	105: "105.0.5195.127",
+	106: "106.0.5249.119",
+	107: "107.0.5304.122",
+	108: "108.0.5359.125",
+	109: "109.0.5414.120",
+	110: "110.0.5481.178",
+	111: "111.0.5563.147",
+	112: "112.0.5615.138",
+	116: "116.0.5845.188",
+	117: "117.0.5938.150",
+	120: "120.0.6099.225",
+	124: "124.0.6367.208",
+	130: "130.0.6723.117",
+	131: "131.0.6778.205",
+	133: "133.0.6943.142",
+}
+
+// operaFullVersions Opera 各主版本的稳定版完整版本号
+var operaFullVersions = map[int]string{
+	89: "89.0.4447.51",
+	90: "90.0.4480.84",
+	91: "91.0.4516.20",
+}
+
+// edgeFullVersions Edge 各主版本的稳定版完整版本号
+var edgeFullVersions = map[int]string{
+	131: "131.0.2903.112",
+	133: "133.0.3065.92",
+}
+
+// androidModel 精简 User-Agent（"Android 10; K"）不包含设备型号时 Sec-CH-UA-Model 使用的型号，与冻结的 Android 10 一致
+const androidModel = "SM-G973F"
+
+// reducedAndroidModel 精简 User-Agent 中代替设备型号的占位符
+const reducedAndroidModel = "K"
+
+// clientHintBrand Sec-CH-UA 中的一个品牌
+type clientHintBrand struct {
+	name    string
+	major   int
+	version string // 完整版本号
+}
+
+// fullVersion 返回主版本的完整版本号，未知版本使用 "major.0.0.0"
+func fullVersion(versions map[int]string, major int) string {
+	if v, ok := versions[major]; ok {
+		return v
+	}
+	return fmt.Sprintf("%d.0.0.0", major)
+}
+
+// majorVersion 解析 "133.0.0.0" 形式版本号的主版本，失败时返回 0
+func majorVersion(version string) int {
+	major, _ := strconv.Atoi(strings.SplitN(version, ".", 2)[0])
+	return major
+}
+
+// greasyChars 和 greasedVersions Chromium 生成 GREASE 品牌时使用的字符和版本
+// 见 https://wicg.github.io/ua-client-hints/#create-arbitrary-brands-section
+var (
+	greasyChars     = []string{" ", "(", ":", "-", ".", "/", ")", ";", "=", "?", "_"}
+	greasedVersions = []int{8, 99, 24}
+)
+
+// brandPermutations Chromium 按 seed % 6 选择的品牌顺序，依次为 GREASE 品牌、Chromium 和浏览器品牌在列表中的位置
+var brandPermutations = [6][3]int{{0, 1, 2}, {0, 2, 1}, {1, 0, 2}, {1, 2, 0}, {2, 0, 1}, {2, 1, 0}}
+
+// greaseBrand 返回 Chromium 主版本 seed 使用的 GREASE 品牌
+// Chromium 105 之前固定为 " Not A;Brand";v="99"，之后品牌中的字符和版本都随主版本变化；
+// Chromium 103 使用过渡版本的算法：品牌前也插入字符，版本固定为 99（".Not/A)Brand";v="99"）
+func greaseBrand(seed int) clientHintBrand {
+	if seed == 103 {
+		name := greasyChars[seed%len(greasyChars)] + "Not" + greasyChars[(seed+1)%len(greasyChars)] + "A" + greasyChars[(seed+2)%len(greasyChars)] + "Brand"
+		return clientHintBrand{name: name, major: 99, version: "99.0.0.0"}
+	}
+	if seed < 105 {
+		return clientHintBrand{name: " Not A;Brand", major: 99, version: "99.0.0.0"}
+	}
+	name := "Not" + greasyChars[seed%len(greasyChars)] + "A" + greasyChars[(seed+1)%len(greasyChars)] + "Brand"
+	major := greasedVersions[seed%len(greasedVersions)]
+	return clientHintBrand{name: name, major: major, version: fmt.Sprintf("%d.0.0.0", major)}
+}
+
+// shuffleBrands 按 Chromium 的算法排列 GREASE 品牌、Chromium 和浏览器品牌，seed 为 Chromium 主版本号
+// Chromium 103 之前的顺序固定为 GREASE 品牌、Chromium、浏览器品牌
+func shuffleBrands(seed int, chromium, brand clientHintBrand) []clientHintBrand {
+	grease := greaseBrand(seed)
+	if seed < 103 {
+		return []clientHintBrand{grease, chromium, brand}
+	}
+	order := brandPermutations[seed%len(brandPermutations)]
+	brands := make([]clientHintBrand, 3)
+	brands[order[0]] = grease
+	brands[order[1]] = chromium
+	brands[order[2]] = brand
+	return brands
+}
+
+// clientHintBrands 根据 User-Agent 返回 Chromium 内核浏览器的品牌列表，非 Chromium 浏览器返回 nil
+// 品牌顺序和 GREASE 品牌由 Chromium 主版本决定，与对应版本的浏览器实际发送的一致
+func clientHintBrands(browserType BrowserType, userAgent string) []clientHintBrand {
+	chromium := majorVersion(utils.ExtractChromeVersion(userAgent))
+	chromiumBrand := clientHintBrand{name: "Chromium", major: chromium, version: fullVersion(chromeFullVersions, chromium)}
+
+	switch browserType {
+	case BrowserChrome:
+		return shuffleBrands(chromium, chromiumBrand, clientHintBrand{name: "Google Chrome", major: chromium, version: chromiumBrand.version})
+	case BrowserEdge:
+		// 桌面版 Edge 使用 Edg/，Android 版使用 EdgA/
+		version := utils.ExtractProductVersion(userAgent, "Edg/")
+		if version == "" {
+			version = utils.ExtractProductVersion(userAgent, "EdgA/")
+		}
+		edge := majorVersion(version)
+		return shuffleBrands(chromium, chromiumBrand, clientHintBrand{name: "Microsoft Edge", major: edge, version: fullVersion(edgeFullVersions, edge)})
+	case BrowserOpera:
+		opera := majorVersion(utils.ExtractProductVersion(userAgent, "OPR/"))
+		return shuffleBrands(chromium, chromiumBrand, clientHintBrand{name: "Opera", major: opera, version: fullVersion(operaFullVersions, opera)})
+	}
+	return nil
+}
+
+// formatBrands 格式化 Sec-CH-UA（主版本）或 Sec-CH-UA-Full-Version-List（完整版本）
+func formatBrands(brands []clientHintBrand, full bool) string {
+	parts := make([]string, len(brands))
+	for i, brand := range brands {
+		version := strconv.Itoa(brand.major)
+		if full {
+			version = brand.version
+		}
+		parts[i] = fmt.Sprintf(`"%s";v="%s"`, brand.name, version)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// clientHintsFor 根据浏览器类型和 User-Agent 生成完整的 client hints（小写名称 → 值）
+// 所有值都从 User-Agent 推导，与其中的浏览器版本、平台和设备类型一致；非 Chromium 浏览器返回 nil
+func clientHintsFor(browserType BrowserType, userAgent string, isMobile bool) map[string]string {
+	brands := clientHintBrands(browserType, userAgent)
+	if brands == nil {
+		return nil
+	}
+
+	hints := map[string]string{
+		hintUA:              formatBrands(brands, false),
+		hintFullVersionList: formatBrands(brands, true),
+		hintWoW64:           "?0",
+	}
+
+	if isMobile {
+		androidVersion := utils.ExtractProductVersion(userAgent, "Android ")
+		if androidVersion == "" {
+			androidVersion = "10"
+		}
+		// 未精简的 User-Agent（如应用的 WebView）包含真实的设备型号
+		model := utils.ExtractAndroidModel(userAgent)
+		if model == "" || model == reducedAndroidModel {
+			model = androidModel
+		}
+		hints[hintMobile] = "?1"
+		hints[hintPlatform] = `"Android"`
+		hints[hintPlatformVersion] = quote(normalizePlatformVersion(androidVersion))
+		hints[hintArch] = `""`
+		hints[hintBitness] = `""`
+		hints[hintModel] = quote(model)
+		hints[hintFormFactors] = `"Mobile"`
+	} else {
+		platform := utils.ExtractPlatform(userAgent)
+		hints[hintMobile] = "?0"
+		hints[hintPlatform] = platform
+		hints[hintArch] = `"x86"`
+		hints[hintBitness] = `"64"`
+		hints[hintModel] = `""`
+		hints[hintFormFactors] = `"Desktop"`
+		switch platform {
+		case `"Windows"`:
+			hints[hintPlatformVersion] = `"10.0.0"`
+		case `"macOS"`:
+			version := normalizePlatformVersion(strings.ReplaceAll(utils.ExtractProductVersion(userAgent, "Mac OS X "), "_", "."))
+			hints[hintPlatformVersion] = quote(version)
+			// macOS 11 及以上的 Chrome 在 User-Agent 中冻结为 Intel，实际大多运行在 Apple Silicon 上
+			if majorVersion(version) >= 11 {
+				hints[hintArch] = `"arm"`
+			}
+		default:
+			hints[hintPlatformVersion] = `"6.8.0"`
+		}
+	}
+
+	// Sec-CH-UA-Form-Factors 从 Chromium 124 开始支持
+	if majorVersion(utils.ExtractChromeVersion(userAgent)) < 124 {
+		delete(hints, hintFormFactors)
+	}
+	return hints
+}
+
+// applyOS 使用操作系统的实际版本和架构替换桌面 Chromium 浏览器的高熵 client hints
+// User-Agent 中的操作系统版本是冻结的（如 Windows 11 仍为 Windows NT 10.0），只能在这里区分；os 为零值或非桌面时不修改
+func (h *HTTPHeaders) applyOS(os OperatingSystem) {
+	if os.IsZero() || h.clientHints == nil || h.clientHints[hintMobile] != "?0" {
+		return
+	}
+	hints := make(map[string]string, len(h.clientHints))
+	for name, value := range h.clientHints {
+		hints[name] = value
+	}
+	if os.PlatformVersion != "" {
+		hints[hintPlatformVersion] = quote(os.PlatformVersion)
+	}
+	if os.Arch != "" {
+		hints[hintArch] = quote(os.Arch)
+	}
+	if os.Bitness != 0 {
+		hints[hintBitness] = quote(strconv.Itoa(os.Bitness))
+	}
+	h.setClientHints(hints)
+}
+
+// normalizePlatformVersion 将 "10"、"14.0" 等版本号补齐为 "major.minor.patch" 形式
+func normalizePlatformVersion(version string) string {
+	if version == "" {
+		return ""
+	}
+	parts := strings.Split(version, ".")
+	for len(parts) < 3 {
+		parts = append(parts, "0")
+	}
+	return strings.Join(parts[:3], ".")
+}
+
+// quote 返回带双引号的 structured header 字符串
+func quote(s string) string {
+	return `"` + s + `"`
+}
+
+// ApplyAcceptCH 根据服务器 Accept-CH 响应头（如 "Sec-CH-UA-Full-Version-List, Sec-CH-UA-Model"）
+// 设置请求的高熵 client hints，值与 User-Agent 中的浏览器版本、平台和设备类型一致
+// Sec-CH-UA、Sec-CH-UA-Mobile 和 Sec-CH-UA-Platform 总是发送，不需要请求；
+// 不支持 client hints 的浏览器（Firefox、Safari）或当前版本不支持的 hint 会被忽略
+func (h *HTTPHeaders) ApplyAcceptCH(acceptCH string) {
+	if h == nil || len(h.clientHints) == 0 {
+		return
+	}
+	for _, token := range strings.Split(acceptCH, ",") {
+		name := strings.ToLower(strings.TrimSpace(token))
+		value, ok := h.clientHints[name]
+		if !ok {
+			continue
+		}
+		switch name {
+		case hintFullVersionList:
+			h.SecCHUAFullVersionList = value
+		case hintPlatformVersion:
+			h.SecCHUAPlatformVersion = value
+		case hintArch:
+			h.SecCHUAArch = value
+		case hintBitness:
+			h.SecCHUABitness = value
+		case hintModel:
+			h.SecCHUAModel = value
+		case hintWoW64:
+			h.SecCHUAWoW64 = value
+		case hintFormFactors:
+			h.SecCHUAFormFactors = value
+		}
+	}
+}
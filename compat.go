@@ -0,0 +1,171 @@
+package fingerprint
+
+import (
+	"fmt"
+
+	"github.com/vistone/fingerprint/profiles"
+)
+
+// ErrIncompatibleOS 浏览器 profile 不能运行在指定的操作系统上（如桌面 Safari 搭配 Windows）
+type ErrIncompatibleOS struct {
+	Profile string // profile 名称，按浏览器类型选择时为空
+	Browser string // 浏览器类型，按 profile 名称选择时为空
+	OS      OperatingSystem
+}
+
+func (e *ErrIncompatibleOS) Error() string {
+	switch {
+	case e.Profile != "" && e.OS.IsZero():
+		return fmt.Sprintf("profile %s has no compatible operating system", e.Profile)
+	case e.Profile != "":
+		return fmt.Sprintf("profile %s is not available on %q", e.Profile, e.OS)
+	case e.Browser != "":
+		return fmt.Sprintf("browser %s is not available on %q", e.Browser, e.OS)
+	}
+	return fmt.Sprintf("no profile is available on %q", e.OS)
+}
+
+// osSupport 浏览器从 since 主版本开始在某个桌面平台上支持的系统版本和架构，直到同一平台的下一个 osSupport
+type osSupport struct {
+	platform profiles.Platform
+	since    int
+	minOS    browserVersion // 最低系统版本（Windows 为 7、10 等，macOS 为 10.15 等），零值表示不限
+	maxOS    int            // 最高系统主版本（包含），0 表示不限
+	arches   []string       // 有官方构建的架构，nil 表示不限
+}
+
+// browserSupport 各浏览器支持的桌面平台，未列出的平台不支持（如桌面 Safari 只有 macOS 版本）
+// 移动端 profile 的平台由 metadata 决定，不在此列出
+var browserSupport = map[profiles.BrowserFamily][]osSupport{
+	profiles.BrowserChrome: {
+		{platform: profiles.PlatformWindows, minOS: browserVersion{major: 7}},
+		// Chrome 110 停止支持 Windows 7/8.1
+		{platform: profiles.PlatformWindows, since: 110, minOS: browserVersion{major: 10}},
+		{platform: profiles.PlatformMacOS, minOS: browserVersion{major: 10, minor: 11}},
+		{platform: profiles.PlatformMacOS, since: 104, minOS: browserVersion{major: 10, minor: 13}},
+		{platform: profiles.PlatformMacOS, since: 117, minOS: browserVersion{major: 10, minor: 15}},
+		{platform: profiles.PlatformMacOS, since: 129, minOS: browserVersion{major: 11, minor: 0}},
+		// Linux 只有 x86_64 的官方构建
+		{platform: profiles.PlatformLinux, arches: []string{"x86_64"}},
+	},
+	profiles.BrowserEdge: {
+		{platform: profiles.PlatformWindows, minOS: browserVersion{major: 7}},
+		{platform: profiles.PlatformWindows, since: 110, minOS: browserVersion{major: 10}},
+		{platform: profiles.PlatformMacOS, minOS: browserVersion{major: 10, minor: 13}},
+		{platform: profiles.PlatformMacOS, since: 117, minOS: browserVersion{major: 10, minor: 15}},
+		{platform: profiles.PlatformMacOS, since: 129, minOS: browserVersion{major: 11, minor: 0}},
+		{platform: profiles.PlatformLinux, arches: []string{"x86_64"}},
+	},
+	profiles.BrowserOpera: {
+		{platform: profiles.PlatformWindows, minOS: browserVersion{major: 7}},
+		// Opera 95 基于 Chromium 109，是最后支持 Windows 7/8.1 的版本
+		{platform: profiles.PlatformWindows, since: 96, minOS: browserVersion{major: 10}},
+		{platform: profiles.PlatformMacOS, minOS: browserVersion{major: 10, minor: 13}},
+		{platform: profiles.PlatformLinux, arches: []string{"x86_64"}},
+	},
+	profiles.BrowserFirefox: {
+		{platform: profiles.PlatformWindows, minOS: browserVersion{major: 7}},
+		// Firefox 115 ESR 是最后支持 Windows 7/8.1 的版本
+		{platform: profiles.PlatformWindows, since: 116, minOS: browserVersion{major: 10}},
+		{platform: profiles.PlatformMacOS, minOS: browserVersion{major: 10, minor: 12}},
+		{platform: profiles.PlatformMacOS, since: 116, minOS: browserVersion{major: 10, minor: 15}},
+		{platform: profiles.PlatformLinux, arches: []string{"x86_64", "x86", "arm64"}},
+	},
+	profiles.BrowserSafari: {
+		// 每个 Safari 大版本支持当时的 macOS 及之前的两个版本
+		{platform: profiles.PlatformMacOS, minOS: browserVersion{major: 10, minor: 15}, maxOS: 12},
+		{platform: profiles.PlatformMacOS, since: 16, minOS: browserVersion{major: 11, minor: 0}, maxOS: 13},
+		{platform: profiles.PlatformMacOS, since: 17, minOS: browserVersion{major: 12, minor: 0}, maxOS: 14},
+		{platform: profiles.PlatformMacOS, since: 18, minOS: browserVersion{major: 13, minor: 0}, maxOS: 15},
+	},
+}
+
+// supportFor 返回浏览器主版本 major 在 platform 上适用的 osSupport，不支持该平台时返回 false
+func supportFor(browser profiles.BrowserFamily, platform profiles.Platform, major int) (osSupport, bool) {
+	var support osSupport
+	found := false
+	for _, s := range browserSupport[browser] {
+		if s.platform == platform && major >= s.since {
+			support, found = s, true
+		}
+	}
+	return support, found
+}
+
+// compatibleOS 判断 metadata 描述的浏览器能否运行在 os 上
+// 未指定平台的操作系统和没有浏览器类型的 profile 不做限制
+func compatibleOS(metadata Metadata, os OperatingSystem) bool {
+	if os.Family == "" {
+		return true
+	}
+	if !matchPlatform(metadata, os.Family) {
+		return false
+	}
+	if _, known := browserSupport[metadata.Browser]; !known || metadata.Mobile() {
+		return true
+	}
+
+	support, ok := supportFor(metadata.Browser, os.Family, metadata.MajorVersion)
+	if !ok {
+		return false
+	}
+	if version := parseBrowserVersion(os.Version); version.major > 0 {
+		if support.minOS.major > 0 && !version.atLeast(support.minOS) {
+			return false
+		}
+		if support.maxOS > 0 && version.major > support.maxOS {
+			return false
+		}
+	}
+	if arch := os.archName(); arch != "" && support.arches != nil {
+		for _, supported := range support.arches {
+			if supported == arch {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// CompatibleOS 判断 profile 能否运行在 os 上，如桌面 Safari 只能搭配 macOS，Safari 16 只能搭配 macOS 11-13
+// 移动端 profile 只兼容其自身平台的操作系统（如 OperatingSystem{Family: profiles.PlatformAndroid}）；未指定平台的操作系统总是兼容
+func CompatibleOS(profile ClientProfile, os OperatingSystem) bool {
+	return compatibleOS(profile.Metadata(), os)
+}
+
+// CompatibleOperatingSystems 返回 OperatingSystems 中与 profile 兼容的操作系统，移动端 profile 返回空列表
+func CompatibleOperatingSystems(profile ClientProfile) []OperatingSystem {
+	systems := make([]OperatingSystem, 0, len(OperatingSystems))
+	if profile.Metadata().Mobile() {
+		return systems
+	}
+	for _, os := range OperatingSystems {
+		if CompatibleOS(profile, os) {
+			systems = append(systems, os)
+		}
+	}
+	return systems
+}
+
+// checkOS 检查显式指定的操作系统是否与 profile 兼容，os 为零值时不检查
+func checkOS(name string, metadata Metadata, os OperatingSystem) error {
+	if !os.IsZero() && !compatibleOS(metadata, os) {
+		return &ErrIncompatibleOS{Profile: name, OS: os}
+	}
+	return nil
+}
+
+// filterCompatible 返回 names 中与 os 兼容的 profile 名称（保持顺序），os 为零值时返回 names
+func filterCompatible(snapshot map[string]ClientProfile, names []string, os OperatingSystem) []string {
+	if os.IsZero() {
+		return names
+	}
+	compatible := make([]string, 0, len(names))
+	for _, name := range names {
+		if compatibleOS(snapshot[name].Metadata(), os) {
+			compatible = append(compatible, name)
+		}
+	}
+	return compatible
+}
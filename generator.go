@@ -0,0 +1,127 @@
+package fingerprint
+
+import (
+	"math/rand"
+	"sync"
+
+	"github.com/vistone/fingerprint/profiles"
+)
+
+// Generator 指纹生成器，所有随机选择（profile、操作系统、Accept-Language）都使用同一个随机数生成器
+// 使用相同的种子、注册表和权重创建的 Generator，按相同顺序调用总是得到相同的 FingerprintResult，
+// 可用于测试和复现会话。Generator 可以被多个 goroutine 同时使用，但并发调用的先后顺序会影响结果
+//
+// 包级函数（GetRandomFingerprint、RandomOS 等）使用一个基于全局随机数生成器的默认 Generator
+type Generator struct {
+	mu       sync.Mutex
+	rng      *rand.Rand // 为 nil 时使用全局随机数生成器
+	registry *profiles.Registry
+	weights  *Weights // 为 nil 时使用当前权重（见 SetWeights）
+	persona  *Persona // 不为 nil 时按 Persona 选择 locale 列表、时区和平台（见 WithPersona）
+}
+
+// GeneratorOption NewGenerator 的可选配置
+type GeneratorOption func(*Generator)
+
+// WithRegistry 从指定注册表中选择 profile，默认使用 DefaultRegistry
+// 需要完全可复现时应使用不会被修改的注册表（如 DefaultRegistry.Clone()）
+func WithRegistry(registry *Registry) GeneratorOption {
+	return func(g *Generator) {
+		if registry != nil {
+			g.registry = registry
+		}
+	}
+}
+
+// WithWeights 使用指定权重选择 profile、操作系统和语言，默认使用当前权重（见 SetWeights）
+func WithWeights(weights *Weights) GeneratorOption {
+	return func(g *Generator) {
+		g.weights = weights
+	}
+}
+
+// defaultFingerprintGenerator 包级函数使用的默认 Generator
+var defaultFingerprintGenerator = &Generator{registry: profiles.DefaultRegistry}
+
+// NewGenerator 创建使用指定种子的 Generator
+func NewGenerator(seed int64, opts ...GeneratorOption) *Generator {
+	return NewGeneratorFromSource(rand.NewSource(seed), opts...)
+}
+
+// NewGeneratorFromSource 创建使用指定随机源的 Generator，src 不需要是线程安全的
+func NewGeneratorFromSource(src rand.Source, opts ...GeneratorOption) *Generator {
+	g := &Generator{
+		rng:      rand.New(src),
+		registry: profiles.DefaultRegistry,
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// lock 在使用私有随机数生成器时加锁，使一次调用中的所有随机选择连续进行，返回解锁函数
+// 默认 Generator 使用线程安全的全局随机数生成器，不需要加锁
+func (g *Generator) lock() func() {
+	if g.rng == nil {
+		return func() {}
+	}
+	g.mu.Lock()
+	return g.mu.Unlock
+}
+
+// activeWeights 返回 Generator 使用的权重
+func (g *Generator) activeWeights() *Weights {
+	if g.weights != nil {
+		return g.weights
+	}
+	return GetWeights()
+}
+
+// intn 返回 [0, n) 范围内的随机下标；调用方需持有 g.lock()
+func (g *Generator) intn(n int) int {
+	return randomIndex(g.rng, n)
+}
+
+// randomOS 按权重随机选择操作系统；调用方需持有 g.lock()
+func (g *Generator) randomOS() OperatingSystem {
+	return g.activeWeights().ChooseOS(g.rng)
+}
+
+// randomOSFor 按权重随机选择与 profile 兼容的操作系统，移动端 profile 返回零值；调用方需持有 g.lock()
+func (g *Generator) randomOSFor(profile ClientProfile) OperatingSystem {
+	return g.activeWeights().ChooseOSFor(g.rng, profile.Metadata())
+}
+
+// randomLanguage 按权重随机选择 locale 列表，设置了 Persona 时从 Persona 选中的国家/地区中选择；调用方需持有 g.lock()
+func (g *Generator) randomLanguage() []string {
+	if g.persona != nil {
+		_, m := g.persona.choose(g.rng)
+		return m.chooseLocales(g.rng)
+	}
+	return g.activeWeights().ChooseLanguage(g.rng)
+}
+
+// RandomOS 按权重随机选择一个操作系统
+func (g *Generator) RandomOS() OperatingSystem {
+	defer g.lock()()
+	return g.randomOS()
+}
+
+// RandomLanguage 按权重随机选择一个按偏好排序的 locale 列表
+func (g *Generator) RandomLanguage() []string {
+	defer g.lock()()
+	return g.randomLanguage()
+}
+
+// GenerateHeaders 根据浏览器类型和 User-Agent 生成标准 HTTP headers，Accept-Language 按权重随机选择
+func (g *Generator) GenerateHeaders(browserType BrowserType, userAgent string, isMobile bool) *HTTPHeaders {
+	defer g.lock()()
+	return generateHeaders(browserType, userAgent, isMobile, g.randomLanguage())
+}
+
+// GenerateHeadersFor 生成浏览器发送 kind 类型请求时的 headers，Accept-Language 按权重随机选择
+func (g *Generator) GenerateHeadersFor(browserType BrowserType, userAgent string, isMobile bool, kind RequestKind, ctx Context) *HTTPHeaders {
+	defer g.lock()()
+	return generateHeadersFor(browserType, userAgent, isMobile, g.randomLanguage(), kind, ctx)
+}
@@ -10,6 +10,7 @@ require (
 	github.com/vistone/logs v1.0.0
 	github.com/vistone/netconnpool v1.0.1
 	github.com/vistone/quic v1.0.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 replace (
@@ -36,5 +37,4 @@ require (
 	golang.org/x/sys v0.39.0 // indirect
 	golang.org/x/text v0.32.0 // indirect
 	golang.org/x/tools v0.40.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
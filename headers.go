@@ -1,134 +1,181 @@
 package fingerprint
 
 import (
-	"fmt"
+	"sort"
+	"strings"
 
-	"github.com/vistone/fingerprint/internal/utils"
+	http "github.com/bogdanfinn/fhttp"
 )
 
-// 全球语言列表（按使用频率排序）
-var Languages = []string{
-	"en-US,en;q=0.9",          // 英语（美国）
-	"zh-CN,zh;q=0.9,en;q=0.8", // 中文（简体）
-	"es-ES,es;q=0.9,en;q=0.8", // 西班牙语
-	"fr-FR,fr;q=0.9,en;q=0.8", // 法语
-	"de-DE,de;q=0.9,en;q=0.8", // 德语
-	"ja-JP,ja;q=0.9,en;q=0.8", // 日语
-	"pt-BR,pt;q=0.9,en;q=0.8", // 葡萄牙语（巴西）
-	"ru-RU,ru;q=0.9,en;q=0.8", // 俄语
-	"ar-SA,ar;q=0.9,en;q=0.8", // 阿拉伯语
-	"ko-KR,ko;q=0.9,en;q=0.8", // 韩语
-	"it-IT,it;q=0.9,en;q=0.8", // 意大利语
-	"tr-TR,tr;q=0.9,en;q=0.8", // 土耳其语
-	"pl-PL,pl;q=0.9,en;q=0.8", // 波兰语
-	"nl-NL,nl;q=0.9,en;q=0.8", // 荷兰语
-	"sv-SE,sv;q=0.9,en;q=0.8", // 瑞典语
-	"vi-VN,vi;q=0.9,en;q=0.8", // 越南语
-	"th-TH,th;q=0.9,en;q=0.8", // 泰语
-	"id-ID,id;q=0.9,en;q=0.8", // 印尼语
-	"hi-IN,hi;q=0.9,en;q=0.8", // 印地语
-	"cs-CZ,cs;q=0.9,en;q=0.8", // 捷克语
-	"ro-RO,ro;q=0.9,en;q=0.8", // 罗马尼亚语
-	"hu-HU,hu;q=0.9,en;q=0.8", // 匈牙利语
-	"el-GR,el;q=0.9,en;q=0.8", // 希腊语
-	"da-DK,da;q=0.9,en;q=0.8", // 丹麦语
-	"fi-FI,fi;q=0.9,en;q=0.8", // 芬兰语
-	"no-NO,no;q=0.9,en;q=0.8", // 挪威语
-	"he-IL,he;q=0.9,en;q=0.8", // 希伯来语
-	"uk-UA,uk;q=0.9,en;q=0.8", // 乌克兰语
-	"pt-PT,pt;q=0.9,en;q=0.8", // 葡萄牙语（葡萄牙）
-	"zh-TW,zh;q=0.9,en;q=0.8", // 中文（繁体）
+// Languages 按偏好排序的 locale 列表（按使用频率排序），权重不含语言时按内置的市场份额从中选择
+// Accept-Language 由 FormatAcceptLanguage 按浏览器的规则生成，如 Chrome 中 {"zh-CN", "en"} 为 "zh-CN,zh;q=0.9,en;q=0.8"
+var Languages = [][]string{
+	{"en-US"},                // 英语（美国）
+	{"zh-CN", "en"},          // 中文（简体）
+	{"es-ES", "en"},          // 西班牙语
+	{"fr-FR", "en"},          // 法语
+	{"de-DE", "en"},          // 德语
+	{"ja-JP", "en"},          // 日语
+	{"pt-BR", "en"},          // 葡萄牙语（巴西）
+	{"ru-RU", "en"},          // 俄语
+	{"ar-SA", "en"},          // 阿拉伯语
+	{"ko-KR", "en"},          // 韩语
+	{"it-IT", "en"},          // 意大利语
+	{"tr-TR", "en"},          // 土耳其语
+	{"pl-PL", "en"},          // 波兰语
+	{"nl-NL", "en"},          // 荷兰语
+	{"sv-SE", "en"},          // 瑞典语
+	{"vi-VN", "en"},          // 越南语
+	{"th-TH", "en"},          // 泰语
+	{"id-ID", "en"},          // 印尼语
+	{"hi-IN", "en"},          // 印地语
+	{"cs-CZ", "en"},          // 捷克语
+	{"ro-RO", "en"},          // 罗马尼亚语
+	{"hu-HU", "en"},          // 匈牙利语
+	{"el-GR", "en"},          // 希腊语
+	{"da-DK", "en"},          // 丹麦语
+	{"fi-FI", "en"},          // 芬兰语
+	{"no-NO", "en"},          // 挪威语
+	{"he-IL", "en"},          // 希伯来语
+	{"uk-UA", "en"},          // 乌克兰语
+	{"pt-PT", "en"},          // 葡萄牙语（葡萄牙）
+	{"zh-TW", "en"},          // 中文（繁体）
+	{"en-GB", "en-US"},       // 英语（英国）
+	{"es-MX", "en"},          // 西班牙语（墨西哥）
+	{"de-CH", "fr", "en"},    // 瑞士（德语、法语）
+	{"fr-CA", "en-CA"},       // 加拿大（法语、英语）
+	{"uk-UA", "ru", "en-US"}, // 乌克兰（乌克兰语、俄语）
 }
 
-// RandomLanguage 随机选择一个语言
-func RandomLanguage() string {
-	if len(Languages) == 0 {
-		return "en-US,en;q=0.9" // 默认返回英语
+// 各浏览器发送 header 的顺序（小写名称）
+// 除 GenerateHeaders 生成的标准 header 外，还包含 Origin、Referer、Cookie 等常见 header 的位置，
+// 通过 Set 设置这些 header 时会被放到浏览器实际使用的位置
+var headerOrders = map[BrowserType][]string{
+	BrowserChrome: {
+		"cache-control",
+		"sec-ch-ua",
+		"sec-ch-ua-arch",
+		"sec-ch-ua-bitness",
+		"sec-ch-ua-form-factors",
+		"sec-ch-ua-full-version-list",
+		"sec-ch-ua-mobile",
+		"sec-ch-ua-model",
+		"sec-ch-ua-platform",
+		"sec-ch-ua-platform-version",
+		"sec-ch-ua-wow64",
+		"upgrade-insecure-requests",
+		"origin",
+		"content-type",
+		"user-agent",
+		"accept",
+		"sec-fetch-site",
+		"sec-fetch-mode",
+		"sec-fetch-user",
+		"sec-fetch-dest",
+		"referer",
+		"accept-encoding",
+		"accept-language",
+		"cookie",
+		"priority",
+	},
+	BrowserFirefox: {
+		"user-agent",
+		"accept",
+		"accept-language",
+		"accept-encoding",
+		"content-type",
+		"origin",
+		"referer",
+		"cookie",
+		"upgrade-insecure-requests",
+		"sec-fetch-dest",
+		"sec-fetch-mode",
+		"sec-fetch-site",
+		"sec-fetch-user",
+		"priority",
+		"te",
+	},
+	BrowserSafari: {
+		"content-type",
+		"accept",
+		"sec-fetch-site",
+		"origin",
+		"cookie",
+		"sec-fetch-dest",
+		"accept-language",
+		"sec-fetch-mode",
+		"user-agent",
+		"referer",
+		"accept-encoding",
+		"priority",
+	},
+}
+
+// HeaderOrderFor 返回指定浏览器的 header 顺序（小写名称），未知浏览器使用 Chrome 的顺序
+func HeaderOrderFor(browserType BrowserType) []string {
+	order, ok := headerOrders[browserType]
+	if !ok {
+		// Opera 等 Chromium 内核浏览器与 Chrome 顺序一致
+		order = headerOrders[BrowserChrome]
 	}
-	return utils.RandomChoiceString(Languages)
+	return append([]string(nil), order...)
+}
+
+// RandomLanguage 按当前权重（见 SetWeights）随机选择一个按偏好排序的 locale 列表
+func RandomLanguage() []string {
+	return defaultFingerprintGenerator.RandomLanguage()
 }
 
 // GenerateHeaders 根据浏览器类型和 User-Agent 生成标准 HTTP headers
+// Accept-Language 按当前权重随机选择
 func GenerateHeaders(browserType BrowserType, userAgent string, isMobile bool) *HTTPHeaders {
+	return defaultFingerprintGenerator.GenerateHeaders(browserType, userAgent, isMobile)
+}
+
+// generateHeaders 根据浏览器类型和 User-Agent 生成标准 HTTP headers，Accept-Language 按浏览器的规则由 locales 生成
+func generateHeaders(browserType BrowserType, userAgent string, isMobile bool, locales []string) *HTTPHeaders {
 	if userAgent == "" {
 		userAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36"
 	}
 	headers := &HTTPHeaders{
-		UserAgent: userAgent,
+		UserAgent:   userAgent,
+		HeaderOrder: HeaderOrderFor(browserType),
 	}
 
-	switch browserType {
-	case BrowserChrome:
-		headers.Accept = "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,image/apng,*/*;q=0.8,application/signed-exchange;v=b3;q=0.7"
-		headers.AcceptEncoding = "gzip, deflate, br, zstd"
+	// Accept、Accept-Encoding 等随浏览器版本变化，见 headerTables
+	release := headerReleaseFor(browserType, userAgent)
+	headers.Accept = release.accept
+	headers.AcceptEncoding = release.acceptEncoding
+	if release.fetchMetadata {
 		headers.SecFetchSite = "none"
 		headers.SecFetchMode = "navigate"
-		headers.SecFetchUser = "?1"
 		headers.SecFetchDest = "document"
-		headers.UpgradeInsecureRequests = "1"
-
-		if isMobile {
-			headers.SecCHUA = `"Not A(Brand";v="8", "Chromium";v="120", "Google Chrome";v="120"`
-			headers.SecCHUAMobile = "?1"
-			headers.SecCHUAPlatform = `"Android"`
-		} else {
-			// 从 User-Agent 提取 Chrome 版本
-			chromeVersion := utils.ExtractChromeVersion(userAgent)
-			headers.SecCHUA = fmt.Sprintf(`"Not A(Brand";v="8", "Chromium";v="%s", "Google Chrome";v="%s"`, chromeVersion, chromeVersion)
-			headers.SecCHUAMobile = "?0"
-			// 从 User-Agent 提取平台
-			headers.SecCHUAPlatform = utils.ExtractPlatform(userAgent)
-		}
-
-	case BrowserFirefox:
-		headers.Accept = "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,*/*;q=0.8"
-		headers.AcceptEncoding = "gzip, deflate, br"
-		// Firefox 不使用 Sec-Fetch-* headers（旧版本）
-		// 新版本 Firefox 使用，但格式不同
-		if isMobile {
-			headers.SecFetchSite = "none"
-			headers.SecFetchMode = "navigate"
-			headers.SecFetchUser = "?1"
-			headers.SecFetchDest = "document"
-		}
-
-	case BrowserSafari:
-		headers.Accept = "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"
-		headers.AcceptEncoding = "gzip, deflate, br"
-		if !isMobile {
-			headers.SecFetchSite = "none"
-			headers.SecFetchMode = "navigate"
+		if release.fetchUser {
 			headers.SecFetchUser = "?1"
-			headers.SecFetchDest = "document"
 		}
-
-	case BrowserOpera:
-		// Opera 使用 Chrome 内核，headers 类似 Chrome
-		headers.Accept = "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,image/apng,*/*;q=0.8,application/signed-exchange;v=b3;q=0.7"
-		headers.AcceptEncoding = "gzip, deflate, br, zstd"
-		headers.SecFetchSite = "none"
-		headers.SecFetchMode = "navigate"
-		headers.SecFetchUser = "?1"
-		headers.SecFetchDest = "document"
+	}
+	if release.upgradeInsecureRequests {
 		headers.UpgradeInsecureRequests = "1"
-
-		if isMobile {
-			headers.SecCHUA = `"Opera";v="91", "Chromium";v="105", "Not A(Brand";v="8"`
-			headers.SecCHUAMobile = "?1"
-			headers.SecCHUAPlatform = `"Android"`
-		} else {
-			headers.SecCHUA = `"Opera";v="91", "Chromium";v="105", "Not A(Brand";v="8"`
-			headers.SecCHUAMobile = "?0"
-			headers.SecCHUAPlatform = utils.ExtractPlatform(userAgent)
-		}
 	}
+	headers.TE = release.te
 
-	// Accept-Language 使用随机语言
-	headers.AcceptLanguage = RandomLanguage()
+	// 低熵 client hints 总是发送，版本和平台从 User-Agent 提取；非 Chromium 浏览器没有 client hints
+	headers.setClientHints(clientHintsFor(browserType, userAgent, isMobile))
+
+	headers.AcceptLanguage = formatAcceptLanguage(release.languageFormat, locales)
+	headers.Priority = release.priorities[RequestNavigate]
 
 	return headers
 }
 
+// setClientHints 保存浏览器可以发送的 client hints，并设置总是发送的低熵 hints
+func (h *HTTPHeaders) setClientHints(hints map[string]string) {
+	h.clientHints = hints
+	h.SecCHUA = hints[hintUA]
+	h.SecCHUAMobile = hints[hintMobile]
+	h.SecCHUAPlatform = hints[hintPlatform]
+}
+
 // Clone 克隆 HTTPHeaders 对象，返回一个新的副本
 func (h *HTTPHeaders) Clone() *HTTPHeaders {
 	if h == nil {
@@ -146,9 +193,25 @@ func (h *HTTPHeaders) Clone() *HTTPHeaders {
 		SecCHUA:                 h.SecCHUA,
 		SecCHUAMobile:           h.SecCHUAMobile,
 		SecCHUAPlatform:         h.SecCHUAPlatform,
+		SecCHUAFullVersionList:  h.SecCHUAFullVersionList,
+		SecCHUAPlatformVersion:  h.SecCHUAPlatformVersion,
+		SecCHUAArch:             h.SecCHUAArch,
+		SecCHUABitness:          h.SecCHUABitness,
+		SecCHUAModel:            h.SecCHUAModel,
+		SecCHUAWoW64:            h.SecCHUAWoW64,
+		SecCHUAFormFactors:      h.SecCHUAFormFactors,
 		UpgradeInsecureRequests: h.UpgradeInsecureRequests,
+		Priority:                h.Priority,
+		TE:                      h.TE,
+		HeaderOrder:             append([]string(nil), h.HeaderOrder...),
+		PseudoHeaderOrder:       append([]string(nil), h.PseudoHeaderOrder...),
+		CustomPosition:          h.CustomPosition,
+		customOrder:             append([]string(nil), h.customOrder...),
 	}
 
+	// client hints 生成后不会被修改，可以共享
+	cloned.clientHints = h.clientHints
+
 	// 克隆 Custom map
 	if h.Custom != nil {
 		cloned.Custom = make(map[string]string)
@@ -170,12 +233,7 @@ func (h *HTTPHeaders) Set(key, value string) {
 	if h.Custom == nil {
 		h.Custom = make(map[string]string)
 	}
-	if value != "" {
-		h.Custom[key] = value
-	} else {
-		// 如果值为空，删除该 header
-		delete(h.Custom, key)
-	}
+	h.setCustom(key, value)
 }
 
 // SetHeaders 批量设置用户自定义的 headers（系统会自动合并到 ToMap() 中）
@@ -187,13 +245,33 @@ func (h *HTTPHeaders) SetHeaders(customHeaders map[string]string) {
 	if h.Custom == nil {
 		h.Custom = make(map[string]string)
 	}
-	for key, value := range customHeaders {
-		if value != "" {
-			h.Custom[key] = value
-		} else {
-			delete(h.Custom, key)
+	// 按 key 排序后设置，保证自定义 header 的顺序稳定
+	keys := make([]string, 0, len(customHeaders))
+	for key := range customHeaders {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		h.setCustom(key, customHeaders[key])
+	}
+}
+
+// setCustom 设置或删除（值为空时）自定义 header，并记录设置顺序
+func (h *HTTPHeaders) setCustom(key, value string) {
+	if value == "" {
+		delete(h.Custom, key)
+		for i, k := range h.customOrder {
+			if k == key {
+				h.customOrder = append(h.customOrder[:i], h.customOrder[i+1:]...)
+				break
+			}
 		}
+		return
+	}
+	if _, exists := h.Custom[key]; !exists {
+		h.customOrder = append(h.customOrder, key)
 	}
+	h.Custom[key] = value
 }
 
 // Merge 合并用户自定义的 headers，用户自定义的优先级更高
@@ -248,11 +326,29 @@ func (h *HTTPHeaders) Merge(customHeaders map[string]string) *HTTPHeaders {
 			merged.SecCHUAMobile = value
 		case "Sec-CH-UA-Platform":
 			merged.SecCHUAPlatform = value
+		case "Sec-CH-UA-Full-Version-List":
+			merged.SecCHUAFullVersionList = value
+		case "Sec-CH-UA-Platform-Version":
+			merged.SecCHUAPlatformVersion = value
+		case "Sec-CH-UA-Arch":
+			merged.SecCHUAArch = value
+		case "Sec-CH-UA-Bitness":
+			merged.SecCHUABitness = value
+		case "Sec-CH-UA-Model":
+			merged.SecCHUAModel = value
+		case "Sec-CH-UA-WoW64":
+			merged.SecCHUAWoW64 = value
+		case "Sec-CH-UA-Form-Factors":
+			merged.SecCHUAFormFactors = value
 		case "Upgrade-Insecure-Requests":
 			merged.UpgradeInsecureRequests = value
+		case "Priority":
+			merged.Priority = value
+		case "TE":
+			merged.TE = value
 		default:
 			// 其他自定义 headers（如 Cookie、Authorization、X-API-Key 等）存储在 Custom map 中
-			merged.Custom[key] = value
+			merged.setCustom(key, value)
 		}
 	}
 
@@ -308,9 +404,36 @@ func (h *HTTPHeaders) ToMapWithCustom(customHeaders map[string]string) map[strin
 	if h.SecCHUAPlatform != "" {
 		headers["Sec-CH-UA-Platform"] = h.SecCHUAPlatform
 	}
+	if h.SecCHUAFullVersionList != "" {
+		headers["Sec-CH-UA-Full-Version-List"] = h.SecCHUAFullVersionList
+	}
+	if h.SecCHUAPlatformVersion != "" {
+		headers["Sec-CH-UA-Platform-Version"] = h.SecCHUAPlatformVersion
+	}
+	if h.SecCHUAArch != "" {
+		headers["Sec-CH-UA-Arch"] = h.SecCHUAArch
+	}
+	if h.SecCHUABitness != "" {
+		headers["Sec-CH-UA-Bitness"] = h.SecCHUABitness
+	}
+	if h.SecCHUAModel != "" {
+		headers["Sec-CH-UA-Model"] = h.SecCHUAModel
+	}
+	if h.SecCHUAWoW64 != "" {
+		headers["Sec-CH-UA-WoW64"] = h.SecCHUAWoW64
+	}
+	if h.SecCHUAFormFactors != "" {
+		headers["Sec-CH-UA-Form-Factors"] = h.SecCHUAFormFactors
+	}
 	if h.UpgradeInsecureRequests != "" {
 		headers["Upgrade-Insecure-Requests"] = h.UpgradeInsecureRequests
 	}
+	if h.Priority != "" {
+		headers["Priority"] = h.Priority
+	}
+	if h.TE != "" {
+		headers["TE"] = h.TE
+	}
 
 	// 合并 HTTPHeaders 中的 Custom headers
 	if h.Custom != nil {
@@ -332,3 +455,124 @@ func (h *HTTPHeaders) ToMapWithCustom(customHeaders map[string]string) map[strin
 
 	return headers
 }
+
+// ToOrderedSlice 按浏览器实际顺序返回 header 列表，每个元素为 [名称, 值]
+// 顺序规则：
+//   - 出现在 HeaderOrder 中的 header（包括通过 Set 覆盖或设置的 Cookie、Referer 等）按 HeaderOrder 排列
+//   - 其余自定义 header 按设置顺序插入到 CustomPosition 指定的 header 之后，CustomPosition 为空时追加到末尾
+//
+// 自定义 header 与标准 header 名称相同（忽略大小写）时，自定义的值优先
+func (h *HTTPHeaders) ToOrderedSlice() [][2]string {
+	if h == nil {
+		return nil
+	}
+
+	entries := h.entries()
+	keys := h.orderedKeys(entries)
+
+	result := make([][2]string, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, entries[key])
+	}
+	return result
+}
+
+// ApplyTo 将 headers 写入 fhttp 请求，并设置 http.HeaderOrderKey 与 http.PHeaderOrderKey
+// 使请求按浏览器的 header 顺序和伪头部顺序发送
+func (h *HTTPHeaders) ApplyTo(req *http.Request) {
+	if h == nil || req == nil {
+		return
+	}
+	if req.Header == nil {
+		req.Header = make(http.Header)
+	}
+
+	ordered := h.ToOrderedSlice()
+	order := make([]string, 0, len(ordered))
+	for _, kv := range ordered {
+		req.Header.Set(kv[0], kv[1])
+		order = append(order, strings.ToLower(kv[0]))
+	}
+
+	req.Header[http.HeaderOrderKey] = order
+	if len(h.PseudoHeaderOrder) > 0 {
+		req.Header[http.PHeaderOrderKey] = append([]string(nil), h.PseudoHeaderOrder...)
+	}
+}
+
+// entries 返回所有非空 header，key 为小写名称，自定义 header 覆盖同名标准 header
+func (h *HTTPHeaders) entries() map[string][2]string {
+	entries := make(map[string][2]string)
+	for key, value := range h.standardMap() {
+		entries[strings.ToLower(key)] = [2]string{key, value}
+	}
+	for key, value := range h.Custom {
+		if value != "" {
+			entries[strings.ToLower(key)] = [2]string{key, value}
+		}
+	}
+	return entries
+}
+
+// standardMap 返回系统生成的标准 headers（不含自定义 headers）
+func (h *HTTPHeaders) standardMap() map[string]string {
+	plain := *h
+	plain.Custom = nil
+	return plain.ToMapWithCustom(nil)
+}
+
+// orderedKeys 计算 entries 中所有 header（小写名称）的发送顺序
+func (h *HTTPHeaders) orderedKeys(entries map[string][2]string) []string {
+	placed := make(map[string]bool, len(entries))
+	positions := make(map[string]int, len(h.HeaderOrder))
+	for i, key := range h.HeaderOrder {
+		positions[strings.ToLower(key)] = i
+	}
+
+	// 按 HeaderOrder 排列的 header
+	base := make([]string, 0, len(entries))
+	for _, key := range h.HeaderOrder {
+		key = strings.ToLower(key)
+		if _, ok := entries[key]; ok && !placed[key] {
+			base = append(base, key)
+			placed[key] = true
+		}
+	}
+
+	// 不在 HeaderOrder 中的自定义 header：先按设置顺序，再按名称排序
+	extra := make([]string, 0)
+	for _, key := range h.customOrder {
+		key = strings.ToLower(key)
+		if _, ok := entries[key]; ok && !placed[key] {
+			extra = append(extra, key)
+			placed[key] = true
+		}
+	}
+	rest := make([]string, 0)
+	for key := range entries {
+		if !placed[key] {
+			rest = append(rest, key)
+		}
+	}
+	sort.Strings(rest)
+	extra = append(extra, rest...)
+
+	// 计算插入位置：CustomPosition 之后；如果它本身未出现，则放在顺序上位于它之前的最后一个 header 之后
+	insertAt := len(base)
+	if h.CustomPosition != "" {
+		if anchor, ok := positions[strings.ToLower(h.CustomPosition)]; ok {
+			insertAt = 0
+			for i, key := range base {
+				if positions[key] <= anchor {
+					insertAt = i + 1
+				}
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(base)+len(extra))
+	keys = append(keys, base[:insertAt]...)
+	keys = append(keys, extra...)
+	keys = append(keys, base[insertAt:]...)
+	return keys
+}
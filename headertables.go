@@ -0,0 +1,297 @@
+package fingerprint
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/vistone/fingerprint/internal/utils"
+)
+
+// browserVersion 浏览器版本号（主版本和次版本）
+type browserVersion struct {
+	major, minor int
+}
+
+// parseBrowserVersion 解析 "16.4"、"133.0.0.0" 形式的版本号，无法解析的部分为 0
+func parseBrowserVersion(version string) browserVersion {
+	parts := strings.SplitN(version, ".", 3)
+	major, _ := strconv.Atoi(parts[0])
+	v := browserVersion{major: major}
+	if len(parts) > 1 {
+		v.minor, _ = strconv.Atoi(parts[1])
+	}
+	return v
+}
+
+// atLeast 返回 v 是否不低于 other
+func (v browserVersion) atLeast(other browserVersion) bool {
+	return v.major > other.major || (v.major == other.major && v.minor >= other.minor)
+}
+
+// userAgentVersion 从 User-Agent 中提取浏览器版本，Chromium 内核浏览器返回 Chromium 的版本
+func userAgentVersion(browserType BrowserType, userAgent string) browserVersion {
+	switch browserType {
+	case BrowserFirefox:
+		return parseBrowserVersion(utils.ExtractProductVersion(userAgent, "Firefox/"))
+	case BrowserSafari:
+		return parseBrowserVersion(utils.ExtractProductVersion(userAgent, "Version/"))
+	}
+	return parseBrowserVersion(utils.ExtractChromeVersion(userAgent))
+}
+
+// headerRelease 浏览器从 since 版本开始发送的 headers，直到下一个 headerRelease 的版本
+type headerRelease struct {
+	since                   browserVersion
+	accept                  string                 // 导航请求的 Accept
+	acceptEncoding          string                 // Accept-Encoding
+	fetchMetadata           bool                   // 是否发送 Sec-Fetch-Site、Sec-Fetch-Mode 和 Sec-Fetch-Dest
+	fetchUser               bool                   // 用户激活的导航是否发送 Sec-Fetch-User
+	upgradeInsecureRequests bool                   // 导航请求是否发送 Upgrade-Insecure-Requests: 1
+	te                      string                 // TE 头
+	subresourceAccepts      map[RequestKind]string // 子资源请求的 Accept，未列出的类型为 */*
+	priorities              map[RequestKind]string // Priority 头（RFC 9218），为 nil 时不发送
+	languageFormat          languageFormat         // Accept-Language 的格式
+}
+
+// Chromium 各版本共用的值
+var (
+	chromiumAccept             = "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,image/apng,*/*;q=0.8,application/signed-exchange;v=b3;q=0.7"
+	chromiumLegacyAccept       = "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,image/apng,*/*;q=0.8,application/signed-exchange;v=b3;q=0.9"
+	chromiumSubresourceAccepts = map[RequestKind]string{
+		RequestImage: "image/avif,image/webp,image/apng,image/svg+xml,image/*,*/*;q=0.8",
+		RequestStyle: "text/css,*/*;q=0.1",
+	}
+	// 阻塞渲染的样式表和脚本不带 i（增量处理）
+	chromiumPriorities = map[RequestKind]string{
+		RequestNavigate: "u=0, i",
+		RequestIframe:   "u=0, i",
+		RequestFetch:    "u=1, i",
+		RequestXHR:      "u=1, i",
+		RequestImage:    "u=2, i",
+		RequestScript:   "u=1",
+		RequestStyle:    "u=0",
+	}
+)
+
+// Firefox 各版本共用的值
+var (
+	firefoxStyleAccept = "text/css,*/*;q=0.1"
+	firefoxPriorities  = map[RequestKind]string{
+		RequestNavigate: "u=0, i",
+		RequestIframe:   "u=4, i",
+		RequestFetch:    "u=4",
+		RequestXHR:      "u=4",
+		RequestImage:    "u=5, i",
+		RequestScript:   "u=2",
+		RequestStyle:    "u=2",
+	}
+)
+
+// Safari 各版本共用的值
+var (
+	safariAccept      = "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"
+	safariStyleAccept = "text/css,*/*;q=0.1"
+	safariPriorities  = map[RequestKind]string{
+		RequestNavigate: "u=0, i",
+		RequestIframe:   "u=0, i",
+		RequestFetch:    "u=3, i",
+		RequestXHR:      "u=3, i",
+		RequestImage:    "u=3, i",
+		RequestScript:   "u=2",
+		RequestStyle:    "u=1",
+	}
+)
+
+// headerTables 各浏览器按版本排列的 headers，Chromium 内核浏览器（Chrome、Edge、Opera）按 Chromium 版本查找
+var headerTables = map[BrowserType][]headerRelease{
+	BrowserChrome: {
+		{
+			accept:                  chromiumLegacyAccept,
+			acceptEncoding:          "gzip, deflate, br",
+			fetchMetadata:           true,
+			fetchUser:               true,
+			upgradeInsecureRequests: true,
+			subresourceAccepts:      chromiumSubresourceAccepts,
+		},
+		{
+			// Chrome 110 将 signed-exchange 的 q 值从 0.9 降为 0.7
+			since:                   browserVersion{major: 110},
+			accept:                  chromiumAccept,
+			acceptEncoding:          "gzip, deflate, br",
+			fetchMetadata:           true,
+			fetchUser:               true,
+			upgradeInsecureRequests: true,
+			subresourceAccepts:      chromiumSubresourceAccepts,
+		},
+		{
+			// Chrome 123 开始支持 zstd
+			since:                   browserVersion{major: 123},
+			accept:                  chromiumAccept,
+			acceptEncoding:          "gzip, deflate, br, zstd",
+			fetchMetadata:           true,
+			fetchUser:               true,
+			upgradeInsecureRequests: true,
+			subresourceAccepts:      chromiumSubresourceAccepts,
+		},
+		{
+			// Chrome 124 开始发送 Priority 头
+			since:                   browserVersion{major: 124},
+			accept:                  chromiumAccept,
+			acceptEncoding:          "gzip, deflate, br, zstd",
+			fetchMetadata:           true,
+			fetchUser:               true,
+			upgradeInsecureRequests: true,
+			subresourceAccepts:      chromiumSubresourceAccepts,
+			priorities:              chromiumPriorities,
+		},
+	},
+	BrowserFirefox: {
+		{
+			accept:                  "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,*/*;q=0.8",
+			acceptEncoding:          "gzip, deflate, br",
+			upgradeInsecureRequests: true,
+			te:                      "trailers",
+			languageFormat:          languageFirefox,
+			subresourceAccepts: map[RequestKind]string{
+				RequestImage: "image/webp,*/*",
+				RequestStyle: firefoxStyleAccept,
+			},
+		},
+		{
+			// Firefox 90 开始发送 Sec-Fetch-*
+			since:                   browserVersion{major: 90},
+			accept:                  "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,*/*;q=0.8",
+			acceptEncoding:          "gzip, deflate, br",
+			fetchMetadata:           true,
+			fetchUser:               true,
+			upgradeInsecureRequests: true,
+			te:                      "trailers",
+			languageFormat:          languageFirefox,
+			subresourceAccepts: map[RequestKind]string{
+				RequestImage: "image/webp,*/*",
+				RequestStyle: firefoxStyleAccept,
+			},
+		},
+		{
+			// Firefox 93 默认启用 AVIF
+			since:                   browserVersion{major: 93},
+			accept:                  "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,*/*;q=0.8",
+			acceptEncoding:          "gzip, deflate, br",
+			fetchMetadata:           true,
+			fetchUser:               true,
+			upgradeInsecureRequests: true,
+			te:                      "trailers",
+			languageFormat:          languageFirefox,
+			subresourceAccepts: map[RequestKind]string{
+				RequestImage: "image/avif,image/webp,*/*",
+				RequestStyle: firefoxStyleAccept,
+			},
+		},
+		{
+			// Firefox 126 开始支持 zstd
+			since:                   browserVersion{major: 126},
+			accept:                  "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,*/*;q=0.8",
+			acceptEncoding:          "gzip, deflate, br, zstd",
+			fetchMetadata:           true,
+			fetchUser:               true,
+			upgradeInsecureRequests: true,
+			te:                      "trailers",
+			languageFormat:          languageFirefox,
+			subresourceAccepts: map[RequestKind]string{
+				RequestImage: "image/avif,image/webp,*/*",
+				RequestStyle: firefoxStyleAccept,
+			},
+		},
+		{
+			// Firefox 128 从导航请求的 Accept 中移除图片类型，并开始发送 Priority 头
+			since:                   browserVersion{major: 128},
+			accept:                  "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+			acceptEncoding:          "gzip, deflate, br, zstd",
+			fetchMetadata:           true,
+			fetchUser:               true,
+			upgradeInsecureRequests: true,
+			te:                      "trailers",
+			languageFormat:          languageFirefox,
+			subresourceAccepts: map[RequestKind]string{
+				RequestImage: "image/avif,image/webp,image/png,image/svg+xml,image/*;q=0.8,*/*;q=0.5",
+				RequestStyle: firefoxStyleAccept,
+			},
+			priorities: firefoxPriorities,
+		},
+	},
+	BrowserSafari: {
+		{
+			accept:         safariAccept,
+			acceptEncoding: "gzip, deflate, br",
+			languageFormat: languageSafari,
+			subresourceAccepts: map[RequestKind]string{
+				RequestImage: "image/webp,image/png,image/svg+xml,image/*;q=0.8,video/*;q=0.8,*/*;q=0.5",
+				RequestStyle: safariStyleAccept,
+			},
+		},
+		{
+			// Safari 16 支持 AVIF
+			since:          browserVersion{major: 16},
+			accept:         safariAccept,
+			acceptEncoding: "gzip, deflate, br",
+			languageFormat: languageSafari,
+			subresourceAccepts: map[RequestKind]string{
+				RequestImage: "image/webp,image/avif,video/*;q=0.8,image/png,image/svg+xml,image/*;q=0.8,*/*;q=0.5",
+				RequestStyle: safariStyleAccept,
+			},
+		},
+		{
+			// Safari 16.4 开始发送 Sec-Fetch-Site、Sec-Fetch-Mode 和 Sec-Fetch-Dest，不发送 Sec-Fetch-User
+			since:          browserVersion{major: 16, minor: 4},
+			accept:         safariAccept,
+			acceptEncoding: "gzip, deflate, br",
+			languageFormat: languageSafari,
+			fetchMetadata:  true,
+			subresourceAccepts: map[RequestKind]string{
+				RequestImage: "image/webp,image/avif,video/*;q=0.8,image/png,image/svg+xml,image/*;q=0.8,*/*;q=0.5",
+				RequestStyle: safariStyleAccept,
+			},
+		},
+		{
+			// Safari 17 支持 JPEG XL 和 HEIC，并开始发送 Priority 头
+			since:          browserVersion{major: 17},
+			accept:         safariAccept,
+			acceptEncoding: "gzip, deflate, br",
+			languageFormat: languageSafari,
+			fetchMetadata:  true,
+			subresourceAccepts: map[RequestKind]string{
+				RequestImage: "image/webp,image/avif,image/jxl,image/heic,image/heic-sequence,video/*;q=0.8,image/png,image/svg+xml,image/*;q=0.8,*/*;q=0.5",
+				RequestStyle: safariStyleAccept,
+			},
+			priorities: safariPriorities,
+		},
+	},
+}
+
+// headerReleaseFor 返回 User-Agent 中的浏览器版本对应的 headers
+// 未知浏览器使用 Chrome 的表；无法从 User-Agent 中提取版本时使用最新版本
+func headerReleaseFor(browserType BrowserType, userAgent string) headerRelease {
+	releases, ok := headerTables[browserType]
+	if !ok {
+		releases = headerTables[BrowserChrome]
+	}
+	version := userAgentVersion(browserType, userAgent)
+	if version.major == 0 {
+		return releases[len(releases)-1]
+	}
+	release := releases[0]
+	for _, r := range releases[1:] {
+		if version.atLeast(r.since) {
+			release = r
+		}
+	}
+	return release
+}
+
+// subresourceAccept 返回 kind 类型子资源请求的 Accept
+func (r headerRelease) subresourceAccept(kind RequestKind) string {
+	if accept, ok := r.subresourceAccepts[kind]; ok {
+		return accept
+	}
+	return "*/*"
+}
@@ -0,0 +1,408 @@
+package fingerprint
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vistone/fingerprint/profiles"
+)
+
+// Identity 一个会话 key 对应的身份，记录生成指纹时的所有随机选择
+// 相同的 Identity 总是得到相同的 User-Agent、headers 和 client hints
+type Identity struct {
+	Key        string          `json:"key"`
+	Profile    string          `json:"profile"`            // profile 名称
+	OS         OperatingSystem `json:"os"`                 // 操作系统（移动端 profile 为零值）
+	Language   string          `json:"language"`           // 逗号分隔、按偏好排序的 locale 列表（见 ParseLocales）
+	Country    string          `json:"country,omitempty"`  // Persona 选中的国家/地区，没有使用 Persona 时为空
+	Timezone   *Timezone       `json:"timezone,omitempty"` // Persona 选中的时区，没有使用 Persona 时为 nil
+	Generation int             `json:"generation"`         // 轮换次数，每次轮换加 1
+	CreatedAt  time.Time       `json:"created_at"`         // 生成时间，用于判断是否过期
+}
+
+// IdentityStorage 身份的持久化存储，实现必须是线程安全的
+type IdentityStorage interface {
+	// Load 读取 key 对应的身份，不存在时返回 false
+	Load(key string) (Identity, bool, error)
+	// Save 保存身份，已存在时覆盖
+	Save(identity Identity) error
+	// Delete 删除 key 对应的身份，不存在时不返回错误
+	Delete(key string) error
+}
+
+// IdentityStore 为每个会话 key（如账号）提供稳定的指纹
+// 第一次访问 key 时根据 key 确定性地推导一个身份（profile、操作系统、语言）并保存，之后总是返回相同的身份；
+// 设置了 TTL 时身份过期后会轮换为新的身份
+type IdentityStore struct {
+	mu       sync.Mutex
+	storage  IdentityStorage
+	registry *profiles.Registry
+	weights  *Weights // 为 nil 时使用当前权重（见 SetWeights）
+	persona  *Persona // 不为 nil 时按 Persona 推导身份（见 WithIdentityPersona）
+	ttl      time.Duration
+	seed     int64
+	now      func() time.Time
+}
+
+// IdentityOption NewIdentityStore 的可选配置
+type IdentityOption func(*IdentityStore)
+
+// WithIdentityStorage 使用指定的持久化存储，默认使用内存存储
+func WithIdentityStorage(storage IdentityStorage) IdentityOption {
+	return func(s *IdentityStore) {
+		if storage != nil {
+			s.storage = storage
+		}
+	}
+}
+
+// WithIdentityTTL 身份生成 ttl 时间后轮换，默认不轮换
+func WithIdentityTTL(ttl time.Duration) IdentityOption {
+	return func(s *IdentityStore) {
+		s.ttl = ttl
+	}
+}
+
+// WithIdentitySeed 推导身份时与 key 混合的种子，不同种子下相同 key 得到不同的身份，默认 0
+func WithIdentitySeed(seed int64) IdentityOption {
+	return func(s *IdentityStore) {
+		s.seed = seed
+	}
+}
+
+// WithIdentityRegistry 从指定注册表中选择 profile，默认使用 DefaultRegistry
+func WithIdentityRegistry(registry *Registry) IdentityOption {
+	return func(s *IdentityStore) {
+		if registry != nil {
+			s.registry = registry
+		}
+	}
+}
+
+// WithIdentityWeights 使用指定权重选择 profile、操作系统和语言，默认使用当前权重（见 SetWeights）
+func WithIdentityWeights(weights *Weights) IdentityOption {
+	return func(s *IdentityStore) {
+		s.weights = weights
+	}
+}
+
+// WithIdentityPersona 按 Persona 推导身份：profile 所在的平台、locale 列表和时区与 Persona 选中的国家/地区一致，
+// 国家/地区和时区与身份一起保存；已保存的身份不受影响
+func WithIdentityPersona(persona *Persona) IdentityOption {
+	return func(s *IdentityStore) {
+		s.persona = persona
+	}
+}
+
+// WithIdentityClock 使用指定的时钟判断身份是否过期，默认使用 time.Now
+func WithIdentityClock(now func() time.Time) IdentityOption {
+	return func(s *IdentityStore) {
+		if now != nil {
+			s.now = now
+		}
+	}
+}
+
+// NewIdentityStore 创建身份存储
+func NewIdentityStore(opts ...IdentityOption) *IdentityStore {
+	s := &IdentityStore{
+		storage:  NewMemoryIdentityStorage(),
+		registry: profiles.DefaultRegistry,
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Get 返回 key 对应的指纹，同一个 key 在身份轮换之前总是得到相同的 profile、User-Agent、操作系统、语言、国家/地区和时区
+// 已保存的身份过期、其 profile 已不在注册表中或与保存的操作系统不兼容（见 CompatibleOS）时，会推导新的身份并保存；
+// key 为空、存储读写失败或无法推导身份时返回错误
+func (s *IdentityStore) Get(key string) (*FingerprintResult, error) {
+	if key == "" {
+		return nil, fmt.Errorf("identity key cannot be empty")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	identity, ok, err := s.storage.Load(key)
+	if err != nil {
+		return nil, err
+	}
+	generation := 0
+	if ok {
+		expired := s.ttl > 0 && !s.now().Before(identity.CreatedAt.Add(s.ttl))
+		if profile, exists := s.registry.Get(identity.Profile); exists && !expired && CompatibleOS(profile, identity.OS) {
+			return s.result(identity, profile)
+		}
+		generation = identity.Generation
+		if expired {
+			generation++
+		}
+	}
+	return s.renew(key, generation)
+}
+
+// Rotate 立即为 key 轮换新的身份并返回对应的指纹
+func (s *IdentityStore) Rotate(key string) (*FingerprintResult, error) {
+	if key == "" {
+		return nil, fmt.Errorf("identity key cannot be empty")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	identity, ok, err := s.storage.Load(key)
+	if err != nil {
+		return nil, err
+	}
+	generation := 0
+	if ok {
+		generation = identity.Generation + 1
+	}
+	return s.renew(key, generation)
+}
+
+// Identity 返回 key 当前保存的身份，不存在时返回 false
+func (s *IdentityStore) Identity(key string) (Identity, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.storage.Load(key)
+}
+
+// Delete 删除 key 对应的身份，下次访问时重新推导（与删除前的第一代身份相同）
+func (s *IdentityStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.storage.Delete(key)
+}
+
+// renew 推导 key 第 generation 代的身份，保存后返回对应的指纹；调用方需持有 s.mu
+func (s *IdentityStore) renew(key string, generation int) (*FingerprintResult, error) {
+	identity, err := s.derive(key, generation)
+	if err != nil {
+		return nil, err
+	}
+	profile, _ := s.registry.Get(identity.Profile)
+	result, err := s.result(identity, profile)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.storage.Save(identity); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// result 根据身份生成指纹，包括保存的国家/地区和时区
+func (s *IdentityStore) result(identity Identity, profile ClientProfile) (*FingerprintResult, error) {
+	result, err := buildFingerprintResult(s.registry, identity.Profile, profile, identity.OS, ParseLocales(identity.Language))
+	if err != nil {
+		return nil, err
+	}
+	result.Country = identity.Country
+	if identity.Timezone != nil {
+		timezone := *identity.Timezone
+		result.Timezone = &timezone
+	}
+	return result, nil
+}
+
+// derive 根据 key、种子和代数确定性地选择 profile、操作系统和语言，设置了 Persona 时还选择国家/地区和时区
+// 注册表、权重和 Persona 不变时，相同的参数总是得到相同的身份
+func (s *IdentityStore) derive(key string, generation int) (Identity, error) {
+	w := s.weights
+	if w == nil {
+		w = GetWeights()
+	}
+	rng := rand.New(rand.NewSource(identitySeed(s.seed, key, generation)))
+
+	if s.persona != nil {
+		// 与 Generator 的 Persona 加权选择相同，使用按 key 推导的随机数生成器
+		snapshot := s.registry.Snapshot()
+		names := make([]string, 0, len(snapshot))
+		for name := range snapshot {
+			names = append(names, name)
+		}
+		g := &Generator{rng: rng, registry: s.registry, weights: w, persona: s.persona}
+		choice, err := g.choosePersona(snapshot, names, OperatingSystem{}, "", true)
+		if err != nil {
+			return Identity{}, err
+		}
+		return Identity{
+			Key:        key,
+			Profile:    choice.name,
+			OS:         choice.os,
+			Language:   strings.Join(choice.locales, ","),
+			Country:    choice.country,
+			Timezone:   &choice.timezone,
+			Generation: generation,
+			CreatedAt:  s.now(),
+		}, nil
+	}
+
+	name, ok := w.chooseProfile(rng, s.registry.List(), s.registry.Get)
+	if !ok {
+		return Identity{}, fmt.Errorf("no TLS client profiles with positive weight available")
+	}
+	profile, _ := s.registry.Get(name)
+	return Identity{
+		Key:        key,
+		Profile:    name,
+		OS:         w.ChooseOSFor(rng, profile.Metadata()),
+		Language:   strings.Join(w.ChooseLanguage(rng), ","),
+		Generation: generation,
+		CreatedAt:  s.now(),
+	}, nil
+}
+
+// identitySeed 使用 FNV-1a 将种子、key 和代数混合为随机数种子
+func identitySeed(seed int64, key string, generation int) int64 {
+	h := fnv.New64a()
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], uint64(seed))
+	h.Write(buf[:])
+	binary.LittleEndian.PutUint64(buf[:], uint64(generation))
+	h.Write(buf[:])
+	h.Write([]byte(key))
+	return int64(h.Sum64())
+}
+
+// MemoryIdentityStorage 内存中的身份存储，进程退出后丢失
+type MemoryIdentityStorage struct {
+	mu         sync.RWMutex
+	identities map[string]Identity
+}
+
+// NewMemoryIdentityStorage 创建内存身份存储
+func NewMemoryIdentityStorage() *MemoryIdentityStorage {
+	return &MemoryIdentityStorage{identities: make(map[string]Identity)}
+}
+
+// Load 读取 key 对应的身份
+func (m *MemoryIdentityStorage) Load(key string) (Identity, bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	identity, ok := m.identities[key]
+	return identity, ok, nil
+}
+
+// Save 保存身份
+func (m *MemoryIdentityStorage) Save(identity Identity) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.identities[identity.Key] = identity
+	return nil
+}
+
+// Delete 删除 key 对应的身份
+func (m *MemoryIdentityStorage) Delete(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.identities, key)
+	return nil
+}
+
+// FileIdentityStorage 保存在 JSON 文件中的身份存储
+// 文件内容为 key 到 Identity 的 JSON 对象，每次修改后整个文件被原子地重写
+type FileIdentityStorage struct {
+	mu         sync.RWMutex
+	path       string
+	identities map[string]Identity
+}
+
+// NewFileIdentityStorage 创建使用 path 文件的身份存储，文件不存在时在第一次保存时创建
+func NewFileIdentityStorage(path string) (*FileIdentityStorage, error) {
+	f := &FileIdentityStorage{
+		path:       path,
+		identities: make(map[string]Identity),
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return f, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &f.identities); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return f, nil
+}
+
+// Load 读取 key 对应的身份
+func (f *FileIdentityStorage) Load(key string) (Identity, bool, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	identity, ok := f.identities[key]
+	return identity, ok, nil
+}
+
+// Save 保存身份并重写文件
+func (f *FileIdentityStorage) Save(identity Identity) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	previous, existed := f.identities[identity.Key]
+	f.identities[identity.Key] = identity
+	if err := f.flush(); err != nil {
+		// 写入失败时恢复内存中的状态，保持与文件一致
+		if existed {
+			f.identities[identity.Key] = previous
+		} else {
+			delete(f.identities, identity.Key)
+		}
+		return err
+	}
+	return nil
+}
+
+// Delete 删除 key 对应的身份并重写文件
+func (f *FileIdentityStorage) Delete(key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	previous, existed := f.identities[key]
+	if !existed {
+		return nil
+	}
+	delete(f.identities, key)
+	if err := f.flush(); err != nil {
+		f.identities[key] = previous
+		return err
+	}
+	return nil
+}
+
+// flush 先写入临时文件再重命名，避免进程中断时留下不完整的文件；调用方需持有 f.mu
+func (f *FileIdentityStorage) flush() error {
+	data, err := json.MarshalIndent(f.identities, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), f.path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
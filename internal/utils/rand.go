@@ -59,6 +59,13 @@ func (r *RandGenerator) Int63n(n int64) int64 {
 	return r.rng.Int63n(n)
 }
 
+// Float64 返回 [0, 1) 范围内的随机浮点数
+func (r *RandGenerator) Float64() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rng.Float64()
+}
+
 // Shuffle 随机打乱切片
 func (r *RandGenerator) Shuffle(n int, swap func(i, j int)) {
 	r.mu.Lock()
@@ -80,3 +87,37 @@ func RandomChoice[T any](items []T) T {
 func RandomChoiceString(items []string) string {
 	return RandomChoice(items)
 }
+
+// WeightedIndex 按权重随机选择一个下标，权重不大于 0 的元素不会被选中
+// rng 为 nil 时使用全局随机数生成器；所有权重都不大于 0 时返回 -1
+func WeightedIndex(rng *rand.Rand, weights []float64) int {
+	total := 0.0
+	for _, w := range weights {
+		if w > 0 {
+			total += w
+		}
+	}
+	if total <= 0 {
+		return -1
+	}
+
+	var x float64
+	if rng != nil {
+		x = rng.Float64() * total
+	} else {
+		x = GetGlobalRandGenerator().Float64() * total
+	}
+	last := -1
+	for i, w := range weights {
+		if w <= 0 {
+			continue
+		}
+		if x < w {
+			return i
+		}
+		x -= w
+		last = i
+	}
+	// 浮点误差可能导致 x 略大于剩余权重，返回最后一个有效元素
+	return last
+}
@@ -1,6 +1,9 @@
 package utils
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // ExtractChromeVersion 从 User-Agent 中提取 Chrome 版本号
 func ExtractChromeVersion(ua string) string {
@@ -35,3 +38,40 @@ func ExtractPlatform(ua string) string {
 func FormatUserAgent(template string, args ...interface{}) string {
 	return fmt.Sprintf(template, args...)
 }
+
+// ExtractProductVersion 从 User-Agent 中提取 product（如 "OPR/"、"Mac OS X "、"Android "）之后的完整版本号
+// 版本号在空格、分号或右括号处结束，找不到时返回空字符串
+func ExtractProductVersion(ua, product string) string {
+	start := Index(ua, product)
+	if start == -1 {
+		return ""
+	}
+	start += len(product)
+	end := start
+	for end < len(ua) && ua[end] != ' ' && ua[end] != ';' && ua[end] != ')' {
+		end++
+	}
+	return ua[start:end]
+}
+
+// ExtractAndroidModel 从 Android User-Agent 中提取设备型号，如 "(Linux; Android 13; Pixel 7)" 中的 "Pixel 7"
+// 型号为 Android 版本之后括号内的最后一项，去掉 " Build/..." 后缀；找不到时返回空字符串
+func ExtractAndroidModel(ua string) string {
+	start := Index(ua, "Android ")
+	if start == -1 {
+		return ""
+	}
+	end := Index(ua[start:], ")")
+	if end == -1 {
+		return ""
+	}
+	parts := Split(ua[start:start+end], ";")
+	if len(parts) < 2 {
+		return ""
+	}
+	model := strings.TrimSpace(parts[len(parts)-1])
+	if i := Index(model, " Build/"); i != -1 {
+		model = model[:i]
+	}
+	return model
+}
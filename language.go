@@ -0,0 +1,128 @@
+package fingerprint
+
+import (
+	"fmt"
+	"strings"
+)
+
+// languageFormat 浏览器格式化 Accept-Language 的规则
+type languageFormat int
+
+const (
+	languageChromium languageFormat = iota // 补充基础语言，q 值每项减 0.1
+	languageFirefox                        // 按原样发送，q 值按语言数量均分
+	languageSafari                         // 只发送首选语言
+)
+
+// defaultLocales locale 列表为空时使用的语言
+var defaultLocales = []string{"en-US"}
+
+// ParseLocales 解析逗号分隔、按偏好排序的 locale 列表（如 "de-CH,fr,en"）
+// 也接受 Accept-Language 格式（如 "de-CH,de;q=0.9,en;q=0.8"），q 值等参数被忽略；重复的 locale（忽略大小写）只保留第一个
+func ParseLocales(s string) []string {
+	locales := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, token := range strings.Split(s, ",") {
+		locale := strings.TrimSpace(strings.SplitN(token, ";", 2)[0])
+		if locale == "" || locale == "*" || seen[strings.ToLower(locale)] {
+			continue
+		}
+		seen[strings.ToLower(locale)] = true
+		locales = append(locales, locale)
+	}
+	return locales
+}
+
+// FormatAcceptLanguage 返回浏览器在用户按 locales 顺序设置语言偏好时发送的 Accept-Language
+// 格式随浏览器和 User-Agent 中的版本变化：
+//   - Chrome、Edge、Opera：在地区变体之后补充基础语言，q 值从 0.9 开始每项减 0.1（最低 0.1），
+//     如 [de-CH fr en] → "de-CH,de;q=0.9,fr;q=0.8,en;q=0.7"
+//   - Firefox：不补充基础语言，第 i 项的 q 值为 1 - i/n（少于 10 项时保留一位小数），
+//     如 [de en-US en] → "de,en-US;q=0.7,en;q=0.3"
+//   - Safari：只发送首选语言及其基础语言，如 [de-DE en] → "de-DE,de;q=0.9"
+//
+// locales 为空时使用 en-US
+func FormatAcceptLanguage(browserType BrowserType, userAgent string, locales []string) string {
+	return formatAcceptLanguage(headerReleaseFor(browserType, userAgent).languageFormat, locales)
+}
+
+// formatAcceptLanguage 按 format 格式化 locale 列表
+func formatAcceptLanguage(format languageFormat, locales []string) string {
+	locales = ParseLocales(strings.Join(locales, ","))
+	if len(locales) == 0 {
+		locales = defaultLocales
+	}
+
+	switch format {
+	case languageFirefox:
+		return firefoxAcceptLanguage(locales)
+	case languageSafari:
+		return chromiumAcceptLanguage(expandBaseLanguages(locales[:1]))
+	}
+	return chromiumAcceptLanguage(expandBaseLanguages(locales))
+}
+
+// baseLanguage 返回 locale 的基础语言，如 de-CH → de
+func baseLanguage(locale string) string {
+	return strings.SplitN(locale, "-", 2)[0]
+}
+
+// expandBaseLanguages 在地区变体之后补充列表中没有的基础语言，与 Chromium 的规则一致
+// 同一语言的多个地区变体相邻时，基础语言放在最后一个变体之后，如 [en-US en-GB] → [en-US en-GB en]
+func expandBaseLanguages(locales []string) []string {
+	present := make(map[string]bool, len(locales))
+	for _, locale := range locales {
+		present[strings.ToLower(locale)] = true
+	}
+
+	expanded := make([]string, 0, len(locales)*2)
+	for i, locale := range locales {
+		expanded = append(expanded, locale)
+		base := baseLanguage(locale)
+		if present[strings.ToLower(base)] {
+			continue
+		}
+		if i+1 < len(locales) && strings.EqualFold(baseLanguage(locales[i+1]), base) {
+			continue
+		}
+		expanded = append(expanded, base)
+		present[strings.ToLower(base)] = true
+	}
+	return expanded
+}
+
+// chromiumAcceptLanguage 第一项不带 q 值，之后每项减 0.1，最低为 0.1
+func chromiumAcceptLanguage(locales []string) string {
+	parts := make([]string, len(locales))
+	q := 10
+	for i, locale := range locales {
+		if i == 0 {
+			parts[i] = locale
+		} else {
+			parts[i] = fmt.Sprintf("%s;q=0.%d", locale, q)
+		}
+		if q > 1 {
+			q--
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// firefoxAcceptLanguage q 值按语言数量均分，与 Firefox 的 nsHttpHandler 一致
+func firefoxAcceptLanguage(locales []string) string {
+	parts := make([]string, len(locales))
+	dec := 1.0 / float64(len(locales))
+	q := 1.0
+	for i, locale := range locales {
+		switch {
+		case i == 0:
+			parts[i] = locale
+		case len(locales) < 10:
+			parts[i] = fmt.Sprintf("%s;q=0.%d", locale, int((q+0.05)*10))
+		default:
+			parts[i] = fmt.Sprintf("%s;q=0.%02d", locale, int((q+0.005)*100))
+		}
+		q -= dec
+	}
+	return strings.Join(parts, ",")
+}
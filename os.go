@@ -0,0 +1,291 @@
+package fingerprint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/vistone/fingerprint/profiles"
+)
+
+// OperatingSystem 操作系统，零值表示未指定（随机选择与 profile 兼容的操作系统）
+// User-Agent 中的操作系统部分按浏览器的冻结规则生成（见 UserAgentToken），真实的版本和架构只体现在高熵 client hints 中：
+// 如 Windows 10 和 Windows 11 的 User-Agent 相同，只能通过 Sec-CH-UA-Platform-Version 区分
+type OperatingSystem struct {
+	Family          profiles.Platform // 平台：windows、macos、linux
+	Version         string            // 系统版本：Windows 为 "7"、"8.1"、"10"、"11"，macOS 为 "14.7.1" 等，Linux 为空
+	Arch            string            // CPU 架构：x86 或 arm（与 Sec-CH-UA-Arch 相同）
+	Bitness         int               // 位数：64 或 32（与 Sec-CH-UA-Bitness 相同）
+	PlatformVersion string            // Sec-CH-UA-Platform-Version，如 Windows 11 为 "15.0.0"，macOS 14.7.1 为 "14.7.1"
+}
+
+// 预定义的操作系统
+var (
+	OSWindows10   = NewOperatingSystem(profiles.PlatformWindows, "10", "x86", 64)
+	OSWindows11   = NewOperatingSystem(profiles.PlatformWindows, "11", "x86", 64)
+	OSMacOS12     = NewOperatingSystem(profiles.PlatformMacOS, "12.7.6", "arm", 64)
+	OSMacOS13     = NewOperatingSystem(profiles.PlatformMacOS, "13.7.1", "arm", 64)
+	OSMacOS14     = NewOperatingSystem(profiles.PlatformMacOS, "14.7.1", "arm", 64)
+	OSMacOS15     = NewOperatingSystem(profiles.PlatformMacOS, "15.1.1", "arm", 64)
+	OSLinux       = NewOperatingSystem(profiles.PlatformLinux, "", "x86", 64)
+	OSLinuxUbuntu = OSLinux // User-Agent 和 client hints 中不区分发行版
+	OSLinuxDebian = OSLinux
+	OSLinuxARM64  = NewOperatingSystem(profiles.PlatformLinux, "", "arm", 64)
+)
+
+// windowsPlatformVersions Windows 版本对应的 Sec-CH-UA-Platform-Version（Windows.Foundation.UniversalApiContract 版本）
+// Windows 8.1 及以下为 "0.0.0"，Windows 10 22H2 为 "10.0.0"，Windows 11 22H2/23H2 为 "15.0.0"
+var windowsPlatformVersions = map[string]string{
+	"10": "10.0.0",
+	"11": "15.0.0",
+}
+
+// windowsNTVersions Windows 版本对应的 NT 版本（User-Agent 中的 Windows NT x.y），Windows 11 仍报告 10.0
+var windowsNTVersions = map[string]string{
+	"7":   "6.1",
+	"8":   "6.2",
+	"8.1": "6.3",
+	"10":  "10.0",
+	"11":  "10.0",
+}
+
+// linuxPlatformVersion Linux 的 Sec-CH-UA-Platform-Version（内核版本）
+const linuxPlatformVersion = "6.8.0"
+
+// NewOperatingSystem 创建操作系统，PlatformVersion 按平台和版本推导
+func NewOperatingSystem(family profiles.Platform, version, arch string, bitness int) OperatingSystem {
+	os := OperatingSystem{Family: family, Version: version, Arch: arch, Bitness: bitness}
+	os.PlatformVersion = os.defaultPlatformVersion()
+	return os
+}
+
+// defaultPlatformVersion 按平台和版本推导 Sec-CH-UA-Platform-Version
+func (os OperatingSystem) defaultPlatformVersion() string {
+	switch os.Family {
+	case profiles.PlatformWindows:
+		if version, ok := windowsPlatformVersions[os.Version]; ok {
+			return version
+		}
+		return "0.0.0"
+	case profiles.PlatformMacOS:
+		return normalizePlatformVersion(os.Version)
+	case profiles.PlatformLinux:
+		return linuxPlatformVersion
+	}
+	return ""
+}
+
+// IsZero 返回是否为零值（未指定操作系统）
+func (os OperatingSystem) IsZero() bool {
+	return os == OperatingSystem{}
+}
+
+// archName 返回架构和位数的组合名称：x86_64、x86、arm64、arm
+func (os OperatingSystem) archName() string {
+	switch {
+	case os.Arch == "x86" && os.Bitness == 32:
+		return "x86"
+	case os.Arch == "x86":
+		return "x86_64"
+	case os.Arch == "arm" && os.Bitness == 32:
+		return "arm"
+	case os.Arch == "arm":
+		return "arm64"
+	}
+	return ""
+}
+
+// archNames archName 对应的架构和位数
+var archNames = map[string]struct {
+	arch    string
+	bitness int
+}{
+	"x86_64": {"x86", 64},
+	"x86":    {"x86", 32},
+	"arm64":  {"arm", 64},
+	"arm":    {"arm", 32},
+}
+
+// String 返回 "family[-version]-arch" 形式的名称，如 "windows-11-x86_64"、"macos-14.7.1-arm64"、"linux-x86_64"
+// PlatformVersion 与按平台和版本推导的值不同时追加 "+PlatformVersion"，如 "windows-11-x86_64+19.0.0"
+func (os OperatingSystem) String() string {
+	if os.IsZero() {
+		return ""
+	}
+	parts := []string{string(os.Family)}
+	if os.Version != "" {
+		parts = append(parts, os.Version)
+	}
+	if arch := os.archName(); arch != "" {
+		parts = append(parts, arch)
+	}
+	s := strings.Join(parts, "-")
+	if os.PlatformVersion != os.defaultPlatformVersion() {
+		s += "+" + os.PlatformVersion
+	}
+	return s
+}
+
+// MarshalText 序列化为 String 的格式，用作权重配置和身份存储中的键
+func (os OperatingSystem) MarshalText() ([]byte, error) {
+	return []byte(os.String()), nil
+}
+
+// UnmarshalText 解析 ParseOperatingSystem 接受的格式，空字符串为零值
+func (os *OperatingSystem) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*os = OperatingSystem{}
+		return nil
+	}
+	parsed, err := ParseOperatingSystem(string(text))
+	if err != nil {
+		return err
+	}
+	*os = parsed
+	return nil
+}
+
+var (
+	windowsNTPattern = regexp.MustCompile(`Windows NT (\d+\.\d+)`)
+	macOSPattern     = regexp.MustCompile(`Mac OS X (\d+)[_.](\d+)(?:[_.](\d+))?`)
+)
+
+// ParseOperatingSystem 解析 String 格式的名称（如 "windows-11-x86_64"、"macos-14.7.1-arm64"、"linux-arm64"），
+// 也接受旧版本使用的 User-Agent 片段（如 "Windows NT 10.0; Win64; x64"、"Macintosh; Intel Mac OS X 14_0_0"、"X11; Linux x86_64"），
+// 以兼容已有的权重配置和身份存储
+func ParseOperatingSystem(s string) (OperatingSystem, error) {
+	s = strings.TrimSpace(s)
+	if strings.ContainsAny(s, " ;") {
+		return parseUserAgentOS(s)
+	}
+
+	name, platformVersion, hasPlatformVersion := strings.Cut(s, "+")
+	parts := strings.Split(strings.ToLower(name), "-")
+	family := profiles.Platform(parts[0])
+	switch family {
+	case profiles.PlatformWindows, profiles.PlatformMacOS, profiles.PlatformLinux:
+	default:
+		return OperatingSystem{}, fmt.Errorf("unknown operating system %q", s)
+	}
+
+	arch, bitness := "x86", 64
+	if family == profiles.PlatformMacOS {
+		arch = "arm"
+	}
+	if len(parts) > 1 {
+		if a, ok := archNames[parts[len(parts)-1]]; ok {
+			arch, bitness = a.arch, a.bitness
+			parts = parts[:len(parts)-1]
+		}
+	}
+	if len(parts) > 2 {
+		return OperatingSystem{}, fmt.Errorf("invalid operating system %q", s)
+	}
+	version := ""
+	if len(parts) == 2 {
+		version = parts[1]
+	}
+
+	os := NewOperatingSystem(family, version, arch, bitness)
+	if hasPlatformVersion {
+		os.PlatformVersion = platformVersion
+	}
+	return os, nil
+}
+
+// parseUserAgentOS 解析 User-Agent 中的操作系统部分，已知的版本返回对应的预定义操作系统；
+// 包含 Intel 的 macOS 片段解析为 x86 架构，版本使用片段中的版本（预定义的 macOS 都是 Apple 芯片）
+func parseUserAgentOS(s string) (OperatingSystem, error) {
+	switch {
+	case strings.Contains(s, "Windows"):
+		nt := "10.0"
+		if m := windowsNTPattern.FindStringSubmatch(s); m != nil {
+			nt = m[1]
+		}
+		version := "10"
+		for v, ntVersion := range windowsNTVersions {
+			if ntVersion == nt && v != "11" {
+				version = v
+			}
+		}
+		if strings.Contains(s, "Win64") || strings.Contains(s, "WOW64") {
+			return NewOperatingSystem(profiles.PlatformWindows, version, "x86", 64), nil
+		}
+		return NewOperatingSystem(profiles.PlatformWindows, version, "x86", 32), nil
+	case strings.Contains(s, "Mac OS X"):
+		m := macOSPattern.FindStringSubmatch(s)
+		if m == nil {
+			return OperatingSystem{}, fmt.Errorf("unknown operating system %q", s)
+		}
+		version := m[1] + "." + m[2]
+		if m[3] != "" {
+			version += "." + m[3]
+		}
+		// Intel 表示 x86 架构，macOS 10.x 只运行在 Intel 芯片上
+		if strings.Contains(s, "Intel") || m[1] == "10" {
+			return NewOperatingSystem(profiles.PlatformMacOS, version, "x86", 64), nil
+		}
+		for _, os := range []OperatingSystem{OSMacOS12, OSMacOS13, OSMacOS14, OSMacOS15} {
+			if majorVersion(os.Version) == majorVersion(m[1]) {
+				return os, nil
+			}
+		}
+		return NewOperatingSystem(profiles.PlatformMacOS, version, "arm", 64), nil
+	case strings.Contains(s, "Linux"):
+		switch {
+		case strings.Contains(s, "aarch64"):
+			return OSLinuxARM64, nil
+		case strings.Contains(s, "i686"):
+			return NewOperatingSystem(profiles.PlatformLinux, "", "x86", 32), nil
+		}
+		return OSLinux, nil
+	}
+	return OperatingSystem{}, fmt.Errorf("unknown operating system %q", s)
+}
+
+// UserAgentToken 返回浏览器在 User-Agent 中报告的操作系统部分，按各浏览器的冻结规则生成：
+//   - Windows：NT 版本（Windows 11 仍为 "Windows NT 10.0"）；Chromium 内核浏览器总是报告 "Win64; x64"，
+//     Firefox 在 32 位系统上不报告架构
+//   - macOS：Chromium 内核浏览器和 Safari 冻结为 "Macintosh; Intel Mac OS X 10_15_7"，
+//     Firefox 冻结为 "Macintosh; Intel Mac OS X 10.15"；更早的版本报告实际版本
+//   - Linux：Chromium 内核浏览器冻结为 "X11; Linux x86_64"，Firefox 报告实际架构（如 "X11; Linux aarch64"）
+func (os OperatingSystem) UserAgentToken(browserType BrowserType) string {
+	firefox := browserType == BrowserFirefox
+	switch os.Family {
+	case profiles.PlatformWindows:
+		nt, ok := windowsNTVersions[os.Version]
+		if !ok {
+			nt = "10.0"
+		}
+		if firefox && os.Bitness == 32 {
+			return "Windows NT " + nt
+		}
+		return "Windows NT " + nt + "; Win64; x64"
+	case profiles.PlatformMacOS:
+		version := parseBrowserVersion(os.Version)
+		if version.atLeast(browserVersion{major: 10, minor: 15}) {
+			if firefox {
+				return "Macintosh; Intel Mac OS X 10.15"
+			}
+			return "Macintosh; Intel Mac OS X 10_15_7"
+		}
+		if firefox {
+			return fmt.Sprintf("Macintosh; Intel Mac OS X %d.%d", version.major, version.minor)
+		}
+		return "Macintosh; Intel Mac OS X " + strings.ReplaceAll(normalizePlatformVersion(os.Version), ".", "_")
+	case profiles.PlatformLinux:
+		if !firefox {
+			return "X11; Linux x86_64"
+		}
+		switch os.archName() {
+		case "arm64":
+			return "X11; Linux aarch64"
+		case "x86":
+			return "X11; Linux i686"
+		case "arm":
+			return "X11; Linux armv7l"
+		}
+		return "X11; Linux x86_64"
+	}
+	return ""
+}
@@ -0,0 +1,286 @@
+package fingerprint
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vistone/fingerprint/internal/utils"
+	"github.com/vistone/fingerprint/profiles"
+)
+
+// marketsJSON 各国家/地区的 locale、时区和平台份额
+// locale 列表按 CLDR territoryInfo 中的语言使用人口估算，时区来自 CLDR/IANA 并按人口估算份额，平台份额按 StatCounter 的统计估算
+//
+//go:embed data/markets.json
+var marketsJSON []byte
+
+// Timezone 时区
+type Timezone struct {
+	Name   string `json:"name"`   // IANA 时区名称，如 Europe/Berlin
+	Offset int    `json:"offset"` // 标准时间（非夏令时）相对 UTC 的偏移，单位分钟，东区为正
+}
+
+// Location 返回时区对应的 *time.Location；系统没有该时区的数据时返回固定为标准时间偏移的时区
+func (tz Timezone) Location() *time.Location {
+	if loc, err := time.LoadLocation(tz.Name); err == nil {
+		return loc
+	}
+	return time.FixedZone(tz.Name, tz.Offset*60)
+}
+
+// market 一个国家/地区用户的 locale 列表、时区和平台份额
+type market struct {
+	Locales   map[string]float64            `json:"locales"` // 逗号分隔、按偏好排序的 locale 列表 → 份额
+	Timezones []timezoneShare               `json:"timezones"`
+	Platforms map[profiles.Platform]float64 `json:"platforms"` // 平台 → 份额，决定 User-Agent 和 Sec-CH-UA-Platform
+}
+
+// timezoneShare 时区及其份额
+type timezoneShare struct {
+	Timezone
+	Weight float64 `json:"weight"`
+}
+
+// markets 内置数据集，键为 ISO 3166-1 alpha-2 代码
+var markets = mustLoadMarkets(marketsJSON)
+
+// mustLoadMarkets 解析内置数据集，数据集无效时 panic
+func mustLoadMarkets(data []byte) map[string]*market {
+	var loaded map[string]*market
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		panic(fmt.Sprintf("fingerprint: invalid market dataset: %v", err))
+	}
+	return loaded
+}
+
+// ErrUnknownCountry 内置数据集中没有该国家/地区
+type ErrUnknownCountry struct {
+	Country string
+}
+
+func (e *ErrUnknownCountry) Error() string {
+	return "unknown persona country: " + e.Country
+}
+
+// Persona 出口 IP 所在市场的用户画像，决定指纹的 locale 列表（Accept-Language）、时区和平台（User-Agent、Sec-CH-UA-Platform）
+// 每次生成指纹时先按权重选择一个国家/地区，之后的所有选择都来自该国家/地区，使 headers、User-Agent 和时区与出口位置一致
+type Persona struct {
+	countries []string // 按代码排序
+	weights   []float64
+}
+
+// PersonaCountries 返回内置数据集中的国家/地区代码（ISO 3166-1 alpha-2，按代码排序）
+func PersonaCountries() []string {
+	countries := make([]string, 0, len(markets))
+	for country := range markets {
+		countries = append(countries, country)
+	}
+	sort.Strings(countries)
+	return countries
+}
+
+// NewPersona 根据 ISO 3166-1 alpha-2 国家/地区代码（如 "DE"，不区分大小写）创建 Persona
+func NewPersona(country string) (*Persona, error) {
+	return NewPersonaMix(map[string]float64{country: 1})
+}
+
+// NewPersonaMix 按权重组合多个国家/地区，用于出口 IP 分布在多个国家的代理池，如 {"DE": 3, "AT": 1}
+// 权重是相对值，权重为 0 的国家/地区不会被选中
+func NewPersonaMix(countries map[string]float64) (*Persona, error) {
+	weights := make(map[string]float64, len(countries))
+	total := 0.0
+	for country, weight := range countries {
+		code := strings.ToUpper(strings.TrimSpace(country))
+		if _, ok := markets[code]; !ok {
+			return nil, &ErrUnknownCountry{Country: country}
+		}
+		if weight < 0 {
+			return nil, fmt.Errorf("persona weight for %s must not be negative: %v", code, weight)
+		}
+		weights[code] += weight
+		total += weight
+	}
+	if total <= 0 {
+		return nil, fmt.Errorf("persona needs at least one country with positive weight")
+	}
+
+	p := &Persona{}
+	for code := range weights {
+		p.countries = append(p.countries, code)
+	}
+	sort.Strings(p.countries)
+	for _, code := range p.countries {
+		p.weights = append(p.weights, weights[code])
+	}
+	return p, nil
+}
+
+// Countries 返回 Persona 包含的国家/地区代码（按代码排序）
+func (p *Persona) Countries() []string {
+	return append([]string(nil), p.countries...)
+}
+
+// choose 按权重选择一个国家/地区，rng 为 nil 时使用全局随机数生成器
+func (p *Persona) choose(rng *rand.Rand) (string, *market) {
+	country := p.countries[utils.WeightedIndex(rng, p.weights)]
+	return country, markets[country]
+}
+
+// chooseKey 按权重从 map 中选择一个键，键按字典序排列以保证相同种子得到相同结果；所有权重都为 0 时返回 false
+func chooseKey[K ~string](rng *rand.Rand, shares map[K]float64) (K, bool) {
+	keys := make([]K, 0, len(shares))
+	for key := range shares {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	weights := make([]float64, len(keys))
+	for i, key := range keys {
+		weights[i] = shares[key]
+	}
+	i := utils.WeightedIndex(rng, weights)
+	if i < 0 {
+		var zero K
+		return zero, false
+	}
+	return keys[i], true
+}
+
+// chooseLocales 按份额选择 locale 列表
+func (m *market) chooseLocales(rng *rand.Rand) []string {
+	if locales, ok := chooseKey(rng, m.Locales); ok {
+		return ParseLocales(locales)
+	}
+	return append([]string(nil), defaultLocales...)
+}
+
+// chooseTimezone 按份额选择时区
+func (m *market) chooseTimezone(rng *rand.Rand) Timezone {
+	weights := make([]float64, len(m.Timezones))
+	for i, tz := range m.Timezones {
+		weights[i] = tz.Weight
+	}
+	if i := utils.WeightedIndex(rng, weights); i >= 0 {
+		return m.Timezones[i].Timezone
+	}
+	return Timezone{Name: "UTC"}
+}
+
+// choosePlatform 按份额选择一个有候选 profile 的平台，返回该平台的候选（保持 names 的顺序）
+// 所有平台都没有候选时返回 false
+func (m *market) choosePlatform(rng *rand.Rand, snapshot map[string]ClientProfile, names []string) (profiles.Platform, []string, bool) {
+	candidates := make(map[profiles.Platform][]string, len(m.Platforms))
+	shares := make(map[profiles.Platform]float64, len(m.Platforms))
+	for platform, share := range m.Platforms {
+		for _, name := range names {
+			if matchPlatform(snapshot[name].Metadata(), platform) {
+				candidates[platform] = append(candidates[platform], name)
+			}
+		}
+		if len(candidates[platform]) > 0 {
+			shares[platform] = share
+		}
+	}
+	platform, ok := chooseKey(rng, shares)
+	return platform, candidates[platform], ok
+}
+
+// WithPersona 按 Persona 选择 locale 列表、时区和平台，使指纹与出口 IP 所在的市场一致
+// 设置后 GetRandomFingerprint、GetWeightedRandomFingerprint、GetRandomFingerprintByBrowser、SelectRandom、
+// RandomLanguage 和 GenerateHeaders 等方法都使用 Persona
+func WithPersona(persona *Persona) GeneratorOption {
+	return func(g *Generator) {
+		g.persona = persona
+	}
+}
+
+// GetRandomFingerprintWithPersona 按 Persona 随机获取一个指纹和对应的 User-Agent，profile 在 Persona 选中的平台中均匀选择
+func GetRandomFingerprintWithPersona(persona *Persona) (*FingerprintResult, error) {
+	return personaGenerator(persona).GetRandomFingerprint()
+}
+
+// GetWeightedRandomFingerprintWithPersona 按 Persona 随机获取一个指纹和对应的 User-Agent，profile 在 Persona 选中的平台中按当前权重选择
+func GetWeightedRandomFingerprintWithPersona(persona *Persona) (*FingerprintResult, error) {
+	return personaGenerator(persona).GetWeightedRandomFingerprint()
+}
+
+// personaGenerator 返回与默认 Generator 相同（全局随机数生成器、DefaultRegistry、当前权重）但使用 persona 的 Generator
+func personaGenerator(persona *Persona) *Generator {
+	return &Generator{registry: profiles.DefaultRegistry, persona: persona}
+}
+
+// personaChoice 按 Persona 做出的一次选择
+type personaChoice struct {
+	name     string // profile 名称
+	os       OperatingSystem
+	locales  []string
+	country  string
+	timezone Timezone
+}
+
+// personaFingerprint 按 Persona 选择 profile、操作系统、locale 列表和时区（见 choosePersona）并生成指纹；调用方需持有 g.lock()
+func (g *Generator) personaFingerprint(snapshot map[string]ClientProfile, names []string, os OperatingSystem, platform profiles.Platform, weighted bool) (*FingerprintResult, error) {
+	choice, err := g.choosePersona(snapshot, names, os, platform, weighted)
+	if err != nil {
+		return nil, err
+	}
+	result, err := buildFingerprintResult(g.registry, choice.name, snapshot[choice.name], choice.os, choice.locales)
+	if err != nil {
+		return nil, err
+	}
+	result.Country = choice.country
+	result.Timezone = &choice.timezone
+	return result, nil
+}
+
+// choosePersona 按 Persona 选择国家/地区，再从候选 profile 中选择一个
+// 未指定 os 和 platform 时先按该国家/地区的平台份额选择一个有候选 profile 的平台，只在该平台的候选中选择；
+// 之后为选中的 profile 随机选择该平台上兼容的操作系统。指定了 os 时只在与其兼容的候选中选择。
+// weighted 为 true 时按权重选择 profile，否则均匀选择。调用方需持有 g.lock()
+func (g *Generator) choosePersona(snapshot map[string]ClientProfile, names []string, os OperatingSystem, platform profiles.Platform, weighted bool) (personaChoice, error) {
+	country, m := g.persona.choose(g.rng)
+
+	w := g.activeWeights()
+	candidates := make([]string, 0, len(names))
+	for _, name := range filterCompatible(snapshot, names, os) {
+		if !weighted || w.profileWeight(name, snapshot[name]) > 0 {
+			candidates = append(candidates, name)
+		}
+	}
+	if len(candidates) == 0 {
+		return personaChoice{}, fmt.Errorf("no TLS client profiles with positive weight available")
+	}
+	sort.Strings(candidates)
+
+	if os.IsZero() && platform == "" {
+		if chosen, matched, ok := m.choosePlatform(g.rng, snapshot, candidates); ok {
+			platform, candidates = chosen, matched
+		}
+	}
+
+	var name string
+	if weighted {
+		name, _ = w.chooseProfile(g.rng, candidates, snapshotLookup(snapshot))
+	} else {
+		name = candidates[g.intn(len(candidates))]
+	}
+	if os.IsZero() {
+		os = g.randomOSForPlatform(platform, snapshot[name])
+	}
+	if os.IsZero() {
+		os = g.randomOSFor(snapshot[name])
+	}
+
+	return personaChoice{
+		name:     name,
+		os:       os,
+		locales:  m.chooseLocales(g.rng),
+		country:  country,
+		timezone: m.chooseTimezone(g.rng),
+	}, nil
+}
@@ -0,0 +1,45 @@
+package fingerprint
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/bogdanfinn/fhttp/http2"
+)
+
+// http2PriorityWeights RFC 9218 紧急程度 0-7 对应的 HTTP/2 权重，与 Chromium 的映射一致
+var http2PriorityWeights = []int{256, 220, 183, 147, 110, 74, 37, 1}
+
+// alignPriority 使导航请求的 Priority 头与 profile 的 HEADERS 帧优先级（GetHeaderPriority）一致
+//   - 依赖其他流（RFC 7540 优先级树，如旧版 Firefox）时浏览器通过 PRIORITY 帧表达优先级，不发送 Priority 头
+//   - 否则紧急程度由 HEADERS 帧的权重决定
+func (h *HTTPHeaders) alignPriority(param *http2.PriorityParam) {
+	if h.Priority == "" || param == nil {
+		return
+	}
+	if param.StreamDep != 0 {
+		h.Priority = ""
+		return
+	}
+	// PriorityParam.Weight 为实际权重减 1
+	weight := int(param.Weight) + 1
+	urgency := len(http2PriorityWeights) - 1
+	for i, w := range http2PriorityWeights {
+		if weight >= w {
+			urgency = i
+			break
+		}
+	}
+	h.Priority = withUrgency(h.Priority, urgency)
+}
+
+// withUrgency 替换 Priority 头中的紧急程度，保留其他参数
+func withUrgency(priority string, urgency int) string {
+	params := strings.Split(priority, ", ")
+	for i, param := range params {
+		if strings.HasPrefix(param, "u=") {
+			params[i] = "u=" + strconv.Itoa(urgency)
+		}
+	}
+	return strings.Join(params, ", ")
+}
@@ -8,14 +8,45 @@ import (
 // 提供主包的统一接口
 type ClientProfile = profiles.ClientProfile
 
+// Metadata 是 profiles.Metadata 的类型别名，描述 profile 的浏览器、引擎、版本、平台、设备类型和 TLS 特性
+// 通过 ClientProfile.Metadata() 获取
+type Metadata = profiles.Metadata
+
 // DefaultClientProfile 默认客户端指纹配置（Chrome 133）
 var DefaultClientProfile = profiles.DefaultClientProfile
 
-// MappedTLSClients 所有可用的 TLS 客户端指纹映射表
-// 可以通过字符串名称快速获取对应的指纹配置
+// MappedTLSClients TLS 客户端指纹的映射表，可以通过字符串名称快速获取对应的指纹配置
+// 该表是 DefaultRegistry 的存储：注册、加载的 profile 会出现在表中，直接写入表中的 profile 也会被随机指纹、
+// User-Agent 和 Select 等函数看到；该表本身不是并发安全的，并发程序应通过 DefaultRegistry 修改
 var MappedTLSClients = profiles.MappedTLSClients
 
+// Registry 是 profiles.Registry 的类型别名，并发安全的 profile 注册表
+type Registry = profiles.Registry
+
+// DefaultRegistry 包含所有内置 profile 的默认注册表
+// 随机指纹和 User-Agent 函数都从该注册表读取 profile
+var DefaultRegistry = profiles.DefaultRegistry
+
+// NewRegistry 创建空的注册表，可用于测试等需要隔离包级状态的场景
+// 这是 profiles.NewRegistry 的重新导出，也可以使用 DefaultRegistry.Clone 复制内置 profile
+var NewRegistry = profiles.NewRegistry
+
 // NewClientProfile 创建一个新的客户端指纹配置
 // 这是 profiles.NewClientProfile 的重新导出
 var NewClientProfile = profiles.NewClientProfile
 
+// FromJA3 根据 JA3 字符串和 Akamai HTTP/2 指纹构造客户端指纹配置
+// 这是 profiles.FromJA3 的重新导出，可选配置见 profiles.JA3Option
+var FromJA3 = profiles.FromJA3
+
+// FromClientHelloBytes 根据抓包得到的 ClientHello 原始字节构造客户端指纹配置
+// 这是 profiles.FromClientHelloBytes 的重新导出，可选配置见 profiles.ClientHelloOption
+var FromClientHelloBytes = profiles.FromClientHelloBytes
+
+// LoadDir 加载目录中的 profile 文件并注册到 DefaultRegistry
+// 这是 profiles.LoadDir 的重新导出，文件格式见 profiles.ProfileDefinition
+var LoadDir = profiles.LoadDir
+
+// LoadFS 加载 fs.FS 中的 profile 文件并注册到 DefaultRegistry
+// 这是 profiles.LoadFS 的重新导出
+var LoadFS = profiles.LoadFS
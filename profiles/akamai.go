@@ -0,0 +1,58 @@
+package profiles
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// AkamaiFingerprint 返回 profile 的 Akamai HTTP/2 指纹
+// 格式：SETTINGS|WINDOW_UPDATE|PRIORITY|伪头部顺序，例如 Chrome：
+//
+//	1:65536;2:0;4:6291456;6:262144|15663105|0|m,a,s,p
+//
+// SETTINGS 按 settingsOrder 输出 id:value；PRIORITY 帧输出 streamID:exclusive:dependency:weight，
+// 其中 weight 为帧中的实际权重（PriorityParam.Weight + 1）；没有 WINDOW_UPDATE 或 PRIORITY 帧时对应字段为 0
+func (c ClientProfile) AkamaiFingerprint() string {
+	settings := make([]string, 0, len(c.settingsOrder))
+	for _, id := range c.settingsOrder {
+		settings = append(settings, fmt.Sprintf("%d:%d", uint16(id), c.settings[id]))
+	}
+
+	priorities := make([]string, 0, len(c.priorities))
+	for _, p := range c.priorities {
+		exclusive := 0
+		if p.PriorityParam.Exclusive {
+			exclusive = 1
+		}
+		priorities = append(priorities, fmt.Sprintf("%d:%d:%d:%d",
+			p.StreamID, exclusive, p.PriorityParam.StreamDep, int(p.PriorityParam.Weight)+1))
+	}
+	priority := strings.Join(priorities, ",")
+	if priority == "" {
+		priority = "0"
+	}
+
+	pseudoHeaders := make([]string, 0, len(c.pseudoHeaderOrder))
+	for _, h := range c.pseudoHeaderOrder {
+		if len(h) > 1 {
+			// ":method" -> "m"
+			pseudoHeaders = append(pseudoHeaders, h[1:2])
+		}
+	}
+
+	return strings.Join([]string{
+		strings.Join(settings, ";"),
+		strconv.FormatUint(uint64(c.connectionFlow), 10),
+		priority,
+		strings.Join(pseudoHeaders, ","),
+	}, "|")
+}
+
+// AkamaiFingerprintHash 返回 Akamai HTTP/2 指纹的 MD5 哈希（小写十六进制）
+func (c ClientProfile) AkamaiFingerprintHash() string {
+	sum := md5.Sum([]byte(c.AkamaiFingerprint()))
+	return hex.EncodeToString(sum[:])
+}
@@ -1,12 +1,15 @@
 package profiles
 
 import (
+	"time"
+
 	"github.com/bogdanfinn/fhttp/http2"
 	tls "github.com/bogdanfinn/utls"
 	"github.com/bogdanfinn/utls/dicttls"
 )
 
 var Firefox_135 = ClientProfile{
+	metadata: Metadata{Browser: BrowserFirefox, Engine: EngineGecko, MajorVersion: 135, Device: DeviceDesktop, ReleaseDate: releaseDate(2025, time.February, 4), Features: FeaturePQ | FeatureECH},
 	clientHelloId: tls.ClientHelloID{
 		Client:               "Firefox",
 		RandomExtensionOrder: false,
@@ -137,6 +140,7 @@ var Firefox_135 = ClientProfile{
 }
 
 var Firefox_133 = ClientProfile{
+	metadata: Metadata{Browser: BrowserFirefox, Engine: EngineGecko, MajorVersion: 133, Device: DeviceDesktop, ReleaseDate: releaseDate(2024, time.November, 26), Features: FeaturePQ | FeatureECH},
 	clientHelloId: tls.ClientHelloID{
 		Client:               "Firefox",
 		RandomExtensionOrder: false,
@@ -266,6 +270,7 @@ var Firefox_133 = ClientProfile{
 }
 
 var Chrome_130_PSK = ClientProfile{
+	metadata: Metadata{Browser: BrowserChrome, Engine: EngineBlink, MajorVersion: 130, Device: DeviceDesktop, ReleaseDate: releaseDate(2024, time.October, 15), Features: FeaturePSK | FeatureECH},
 	clientHelloId: tls.ClientHelloID{
 		Client:               "Chrome",
 		RandomExtensionOrder: false,
@@ -374,6 +379,7 @@ var Chrome_130_PSK = ClientProfile{
 }
 
 var Chrome_131_PSK = ClientProfile{
+	metadata: Metadata{Browser: BrowserChrome, Engine: EngineBlink, MajorVersion: 131, Device: DeviceDesktop, ReleaseDate: releaseDate(2024, time.November, 12), Features: FeaturePSK | FeaturePQ | FeatureECH},
 	clientHelloId: tls.ClientHelloID{
 		Client:               "Chrome",
 		RandomExtensionOrder: false,
@@ -484,6 +490,7 @@ var Chrome_131_PSK = ClientProfile{
 }
 
 var Chrome_131 = ClientProfile{
+	metadata: Metadata{Browser: BrowserChrome, Engine: EngineBlink, MajorVersion: 131, Device: DeviceDesktop, ReleaseDate: releaseDate(2024, time.November, 12), Features: FeaturePQ | FeatureECH},
 	clientHelloId: tls.ClientHelloID{
 		Client:               "Chrome",
 		RandomExtensionOrder: false,
@@ -593,6 +600,7 @@ var Chrome_131 = ClientProfile{
 }
 
 var Firefox_132 = ClientProfile{
+	metadata: Metadata{Browser: BrowserFirefox, Engine: EngineGecko, MajorVersion: 132, Device: DeviceDesktop, ReleaseDate: releaseDate(2024, time.October, 29), Features: FeaturePQ | FeatureECH},
 	clientHelloId: tls.ClientHelloID{
 		Client:               "Firefox",
 		RandomExtensionOrder: false,
@@ -724,6 +732,7 @@ var Firefox_132 = ClientProfile{
 }
 
 var Firefox_123 = ClientProfile{
+	metadata: Metadata{Browser: BrowserFirefox, Engine: EngineGecko, MajorVersion: 123, Device: DeviceDesktop, ReleaseDate: releaseDate(2024, time.February, 20), Features: FeatureECH},
 	clientHelloId: tls.ClientHelloID{
 		Client:               "Firefox",
 		RandomExtensionOrder: false,
@@ -866,6 +875,7 @@ var Firefox_123 = ClientProfile{
 }
 
 var Firefox_120 = ClientProfile{
+	metadata: Metadata{Browser: BrowserFirefox, Engine: EngineGecko, MajorVersion: 120, Device: DeviceDesktop, ReleaseDate: releaseDate(2023, time.November, 21), Features: FeatureECH},
 	clientHelloId: tls.ClientHelloID{
 		Client:               "Firefox",
 		RandomExtensionOrder: false,
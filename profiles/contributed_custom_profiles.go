@@ -8,6 +8,7 @@ import (
 )
 
 var ZalandoAndroidMobile = ClientProfile{
+	metadata: Metadata{Platform: PlatformAndroid, Device: DeviceMobile, App: "zalando"},
 	clientHelloId: tls.ClientHelloID{
 		Client:  "ZalandoAndroidCustom",
 		Version: "1",
@@ -100,6 +101,7 @@ var ZalandoAndroidMobile = ClientProfile{
 }
 
 var ZalandoIosMobile = ClientProfile{
+	metadata: Metadata{Platform: PlatformIOS, Device: DeviceMobile, App: "zalando"},
 	clientHelloId: tls.ClientHelloID{
 		Client:  "ZalandoIosCustom",
 		Version: "1",
@@ -201,6 +203,7 @@ var ZalandoIosMobile = ClientProfile{
 }
 
 var NikeIosMobile = ClientProfile{
+	metadata: Metadata{Platform: PlatformIOS, Device: DeviceMobile, App: "nike"},
 	clientHelloId: tls.ClientHelloID{
 		Client:  "NikeIosCustom",
 		Version: "1",
@@ -302,6 +305,7 @@ var NikeIosMobile = ClientProfile{
 }
 
 var NikeAndroidMobile = ClientProfile{
+	metadata: Metadata{Platform: PlatformAndroid, Device: DeviceMobile, App: "nike"},
 	clientHelloId: tls.ClientHelloID{
 		Client:  "NikeAndroidCustom",
 		Version: "1",
@@ -394,6 +398,7 @@ var NikeAndroidMobile = ClientProfile{
 }
 
 var CloudflareCustom = ClientProfile{
+	metadata: Metadata{Platform: PlatformWindows, Device: DeviceDesktop, App: "cloudscraper"},
 	clientHelloId: tls.ClientHelloID{
 		Client:  "CloudflareCustom",
 		Version: "1",
@@ -480,6 +485,7 @@ var CloudflareCustom = ClientProfile{
 }
 
 var MMSIos = ClientProfile{
+	metadata: Metadata{Platform: PlatformIOS, Device: DeviceMobile, App: "mms"},
 	clientHelloId: tls.ClientHelloID{
 		Client:  "MMSIos",
 		Version: "1",
@@ -573,6 +579,7 @@ var MMSIos = ClientProfile{
 }
 
 var MeshIos = ClientProfile{
+	metadata: Metadata{Platform: PlatformIOS, Device: DeviceMobile, App: "mesh"},
 	clientHelloId: tls.ClientHelloID{
 		Client:  "MeshIos",
 		Version: "1",
@@ -676,6 +683,7 @@ var MeshIos = ClientProfile{
 }
 
 var MeshAndroid = ClientProfile{
+	metadata: Metadata{Platform: PlatformAndroid, Device: DeviceMobile, App: "mesh"},
 	clientHelloId: tls.ClientHelloID{
 		Client:  "MeshAndroid",
 		Version: "1",
@@ -777,6 +785,7 @@ var MeshAndroid = ClientProfile{
 }
 
 var MeshIos2 = ClientProfile{
+	metadata: Metadata{Platform: PlatformIOS, Device: DeviceMobile, App: "mesh"},
 	clientHelloId: tls.ClientHelloID{
 		Client:  "MeshIos2",
 		Version: "1",
@@ -878,6 +887,7 @@ var MeshIos2 = ClientProfile{
 }
 
 var MeshAndroid2 = ClientProfile{
+	metadata: Metadata{Platform: PlatformAndroid, Device: DeviceMobile, App: "mesh"},
 	clientHelloId: tls.ClientHelloID{
 		Client:  "MeshAndroid2",
 		Version: "1",
@@ -956,6 +966,7 @@ var MeshAndroid2 = ClientProfile{
 }
 
 var ConfirmedIos = ClientProfile{
+	metadata: Metadata{Platform: PlatformIOS, Device: DeviceMobile, App: "confirmed"},
 	clientHelloId: tls.ClientHelloID{
 		Client:  "ConfirmedIos",
 		Version: "1",
@@ -1059,6 +1070,7 @@ var ConfirmedIos = ClientProfile{
 }
 
 var ConfirmedAndroid = ClientProfile{
+	metadata: Metadata{Platform: PlatformAndroid, Device: DeviceMobile, App: "confirmed"},
 	clientHelloId: tls.ClientHelloID{
 		Client:  "ConfirmedAndroid",
 		Version: "1",
@@ -1129,6 +1141,7 @@ var ConfirmedAndroid = ClientProfile{
 }
 
 var ConfirmedAndroid2 = ClientProfile{
+	metadata: Metadata{Platform: PlatformAndroid, Device: DeviceMobile, App: "confirmed"},
 	clientHelloId: tls.ClientHelloID{
 		Client:  "ConfirmedAndroid2",
 		Version: "1",
@@ -1199,6 +1212,7 @@ var ConfirmedAndroid2 = ClientProfile{
 }
 
 var Okhttp4Android13 = ClientProfile{
+	metadata: Metadata{Platform: PlatformAndroid, Device: DeviceMobile, App: "okhttp4"},
 	clientHelloId: tls.ClientHelloID{
 		Client:  "OkHttp4Android13",
 		Version: "4.10.0",
@@ -1223,6 +1237,7 @@ var Okhttp4Android13 = ClientProfile{
 	connectionFlow: 16711681,
 }
 var Okhttp4Android12 = ClientProfile{
+	metadata: Metadata{Platform: PlatformAndroid, Device: DeviceMobile, App: "okhttp4"},
 	clientHelloId: tls.ClientHelloID{
 		Client:  "OkHttp4Android12",
 		Version: "4.10.0",
@@ -1248,6 +1263,7 @@ var Okhttp4Android12 = ClientProfile{
 }
 
 var Okhttp4Android11 = ClientProfile{
+	metadata: Metadata{Platform: PlatformAndroid, Device: DeviceMobile, App: "okhttp4"},
 	clientHelloId: tls.ClientHelloID{
 		Client:  "OkHttp4Android11",
 		Version: "4.10.0",
@@ -1273,6 +1289,7 @@ var Okhttp4Android11 = ClientProfile{
 }
 
 var Okhttp4Android10 = ClientProfile{
+	metadata: Metadata{Platform: PlatformAndroid, Device: DeviceMobile, App: "okhttp4"},
 	clientHelloId: tls.ClientHelloID{
 		Client:  "OkHttp4Android10",
 		Version: "4.10.0",
@@ -1357,6 +1374,7 @@ var Okhttp4Android10 = ClientProfile{
 }
 
 var Okhttp4Android9 = ClientProfile{
+	metadata: Metadata{Platform: PlatformAndroid, Device: DeviceMobile, App: "okhttp4"},
 	clientHelloId: tls.ClientHelloID{
 		Client:  "OkHttp4Android9",
 		Version: "4.10.0",
@@ -1428,6 +1446,7 @@ var Okhttp4Android9 = ClientProfile{
 }
 
 var Okhttp4Android8 = ClientProfile{
+	metadata: Metadata{Platform: PlatformAndroid, Device: DeviceMobile, App: "okhttp4"},
 	clientHelloId: tls.ClientHelloID{
 		Client:  "OkHttp4Android8",
 		Version: "4.10.0",
@@ -1497,6 +1516,7 @@ var Okhttp4Android8 = ClientProfile{
 }
 
 var Okhttp4Android7 = ClientProfile{
+	metadata: Metadata{Platform: PlatformAndroid, Device: DeviceMobile, App: "okhttp4"},
 	clientHelloId: tls.ClientHelloID{
 		Client:  "OkHttp4Android7",
 		Version: "4.10.0",
@@ -0,0 +1,143 @@
+package profiles
+
+import (
+	"fmt"
+
+	"github.com/bogdanfinn/fhttp/http2"
+	tls "github.com/bogdanfinn/utls"
+)
+
+// ClientHelloOption FromClientHelloBytes 的可选配置
+type ClientHelloOption func(*clientHelloOptions)
+
+type clientHelloOptions struct {
+	client         string
+	version        string
+	h2             string
+	headerPriority *http2.PriorityParam
+}
+
+// WithClientHelloName 设置生成的 tls.ClientHelloID 的 Client 和 Version
+// 默认 Client 为 "Captured"，Version 为抓包 ClientHello 的 JA3 哈希
+func WithClientHelloName(client, version string) ClientHelloOption {
+	return func(o *clientHelloOptions) {
+		o.client = client
+		o.version = version
+	}
+}
+
+// WithClientHelloHTTP2 设置 Akamai HTTP/2 指纹（格式与 AkamaiFingerprint 的输出一致）
+// ClientHello 中不包含 HTTP/2 参数，不设置时 profile 使用 fhttp 的默认值
+func WithClientHelloHTTP2(h2 string) ClientHelloOption {
+	return func(o *clientHelloOptions) {
+		o.h2 = h2
+	}
+}
+
+// WithClientHelloHeaderPriority 设置 HEADERS 帧的优先级
+func WithClientHelloHeaderPriority(priority *http2.PriorityParam) ClientHelloOption {
+	return func(o *clientHelloOptions) {
+		o.headerPriority = priority
+	}
+}
+
+// FromClientHelloBytes 根据抓包得到的 ClientHello 原始字节构造 ClientProfile
+// raw 可以是完整的 TLS 记录（以 0x16 开头），也可以是不带记录头的握手消息（以 0x01 开头）
+//
+// 与 FromJA3 不同，扩展内容（ALPS 协议、key_share 曲线、签名算法、证书压缩算法等）会被完整保留；
+// GREASE 值会还原为占位符，每次握手重新生成；key_share 的公钥和 PSK 也会在握手时重新生成。
+// utls 无法识别的扩展以 tls.GenericExtension 原样保留。格式错误时返回 *ErrInvalidFingerprint
+func FromClientHelloBytes(raw []byte, opts ...ClientHelloOption) (ClientProfile, error) {
+	options := clientHelloOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	record, err := clientHelloRecord(raw)
+	if err != nil {
+		return ClientProfile{}, err
+	}
+
+	spec, err := parseClientHello(record)
+	if err != nil {
+		return ClientProfile{}, err
+	}
+
+	h2Profile := ClientProfile{}
+	if options.h2 != "" {
+		h2Profile, err = parseAkamaiFingerprint(options.h2)
+		if err != nil {
+			return ClientProfile{}, err
+		}
+	}
+
+	client, version := options.client, options.version
+	if client == "" {
+		client = "Captured"
+	}
+	if version == "" {
+		profile := ClientProfile{clientHelloId: tls.ClientHelloID{SpecFactory: func() (tls.ClientHelloSpec, error) { return spec, nil }}}
+		if version, err = profile.JA3Hash(); err != nil {
+			return ClientProfile{}, err
+		}
+	}
+
+	clientHelloId := tls.ClientHelloID{
+		Client:  client,
+		Version: version,
+		// 扩展是带状态的指针，每次都重新解析，保证不同连接之间不共享扩展实例
+		SpecFactory: func() (tls.ClientHelloSpec, error) {
+			return parseClientHello(record)
+		},
+	}
+
+	return NewClientProfile(
+		clientHelloId,
+		h2Profile.settings,
+		h2Profile.settingsOrder,
+		h2Profile.pseudoHeaderOrder,
+		h2Profile.connectionFlow,
+		h2Profile.priorities,
+		options.headerPriority,
+	), nil
+}
+
+// recordTypeHandshake、handshakeTypeClientHello TLS 记录类型和握手消息类型
+const (
+	recordTypeHandshake      = 0x16
+	handshakeTypeClientHello = 0x01
+)
+
+// clientHelloRecord 返回带 TLS 记录头的 ClientHello 副本，不带记录头时补上
+func clientHelloRecord(raw []byte) ([]byte, error) {
+	invalid := func(reason string) error {
+		return &ErrInvalidFingerprint{Kind: "ClientHello", Reason: reason}
+	}
+
+	if len(raw) < 4 {
+		return nil, invalid("data too short")
+	}
+
+	switch raw[0] {
+	case recordTypeHandshake:
+		return append([]byte(nil), raw...), nil
+	case handshakeTypeClientHello:
+		if len(raw) > 0xffff {
+			return nil, invalid("handshake message too long")
+		}
+		record := make([]byte, 0, len(raw)+5)
+		record = append(record, recordTypeHandshake, 0x03, 0x01, byte(len(raw)>>8), byte(len(raw)))
+		return append(record, raw...), nil
+	}
+	return nil, invalid(fmt.Sprintf("unexpected first byte 0x%02x, expected a TLS handshake record or ClientHello message", raw[0]))
+}
+
+// parseClientHello 将 ClientHello 记录解析为 ClientHelloSpec
+func parseClientHello(record []byte) (tls.ClientHelloSpec, error) {
+	fingerprinter := &tls.Fingerprinter{AllowBluntMimicry: true, RealPSKResumption: true}
+	spec, err := fingerprinter.RawClientHello(record)
+	if err != nil {
+		return tls.ClientHelloSpec{}, &ErrInvalidFingerprint{Kind: "ClientHello", Reason: err.Error()}
+	}
+	return *spec, nil
+}
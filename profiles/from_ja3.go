@@ -0,0 +1,515 @@
+package profiles
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bogdanfinn/fhttp/http2"
+	tls "github.com/bogdanfinn/utls"
+)
+
+// ErrInvalidFingerprint 指纹字符串格式错误
+type ErrInvalidFingerprint struct {
+	Kind   string // 指纹类型，例如 "JA3"、"Akamai HTTP/2"
+	Reason string
+}
+
+func (e *ErrInvalidFingerprint) Error() string {
+	return "invalid " + e.Kind + " fingerprint: " + e.Reason
+}
+
+// JA3Option FromJA3 的可选配置
+// JA3 只记录扩展编号，不包含扩展内容，以下选项用于补全这些内容
+type JA3Option func(*ja3Options)
+
+type ja3Options struct {
+	client          string
+	version         string
+	alpn            []string
+	signatures      []tls.SignatureScheme
+	keyShareCurves  []tls.CurveID
+	certCompression []tls.CertCompressionAlgo
+	grease          bool
+	headerPriority  *http2.PriorityParam
+}
+
+// WithJA3ClientHelloID 设置生成的 tls.ClientHelloID 的 Client 和 Version
+// 默认 Client 为 "JA3"，Version 为 JA3 字符串的 MD5
+func WithJA3ClientHelloID(client, version string) JA3Option {
+	return func(o *ja3Options) {
+		o.client = client
+		o.version = version
+	}
+}
+
+// WithJA3ALPN 设置 ALPN 扩展（16）和 ALPS 扩展中的协议，默认 h2、http/1.1
+func WithJA3ALPN(protocols ...string) JA3Option {
+	return func(o *ja3Options) {
+		o.alpn = protocols
+	}
+}
+
+// WithJA3SignatureAlgorithms 设置 signature_algorithms（13）和 signature_algorithms_cert（50）扩展的内容
+func WithJA3SignatureAlgorithms(schemes ...tls.SignatureScheme) JA3Option {
+	return func(o *ja3Options) {
+		o.signatures = schemes
+	}
+}
+
+// WithJA3KeyShareCurves 设置 key_share 扩展（51）中发送的曲线
+// 默认根据 supported_groups 推断：包含 X25519MLKEM768 或 X25519Kyber768 时发送该曲线和 X25519，否则只发送第一个曲线
+func WithJA3KeyShareCurves(curves ...tls.CurveID) JA3Option {
+	return func(o *ja3Options) {
+		o.keyShareCurves = curves
+	}
+}
+
+// WithJA3CertCompression 设置 compress_certificate 扩展（27）的算法，默认 brotli
+func WithJA3CertCompression(algorithms ...tls.CertCompressionAlgo) JA3Option {
+	return func(o *ja3Options) {
+		o.certCompression = algorithms
+	}
+}
+
+// WithJA3GREASE 在密码套件、扩展、曲线、key_share 和 supported_versions 中插入 GREASE 值
+// JA3 会去除 GREASE，复现 Chrome 等使用 GREASE 的客户端时需要开启
+func WithJA3GREASE() JA3Option {
+	return func(o *ja3Options) {
+		o.grease = true
+	}
+}
+
+// WithJA3HeaderPriority 设置 HEADERS 帧的优先级，Akamai 指纹中不包含该信息
+func WithJA3HeaderPriority(priority *http2.PriorityParam) JA3Option {
+	return func(o *ja3Options) {
+		o.headerPriority = priority
+	}
+}
+
+// FromJA3 根据 JA3 字符串和 Akamai HTTP/2 指纹构造 ClientProfile
+// ja3 格式：TLSVersion,Ciphers,Extensions,EllipticCurves,EllipticCurvePointFormats
+// h2 格式：SETTINGS|WINDOW_UPDATE|PRIORITY|伪头部顺序（与 AkamaiFingerprint 的输出一致），为空时不设置 HTTP/2 参数
+//
+// 已知扩展会按常见浏览器的默认内容构造，未知扩展编号使用空内容的 tls.GenericExtension；
+// 格式错误时返回 *ErrInvalidFingerprint
+func FromJA3(ja3 string, h2 string, opts ...JA3Option) (ClientProfile, error) {
+	options := ja3Options{
+		alpn: []string{"h2", "http/1.1"},
+		signatures: []tls.SignatureScheme{
+			tls.ECDSAWithP256AndSHA256,
+			tls.PSSWithSHA256,
+			tls.PKCS1WithSHA256,
+			tls.ECDSAWithP384AndSHA384,
+			tls.PSSWithSHA384,
+			tls.PKCS1WithSHA384,
+			tls.PSSWithSHA512,
+			tls.PKCS1WithSHA512,
+		},
+		certCompression: []tls.CertCompressionAlgo{tls.CertCompressionBrotli},
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	parsed, err := parseJA3(ja3)
+	if err != nil {
+		return ClientProfile{}, err
+	}
+
+	// 提前构造一次，尽早发现无法构造的扩展
+	if _, err := parsed.spec(options); err != nil {
+		return ClientProfile{}, err
+	}
+
+	h2Profile := ClientProfile{}
+	if strings.TrimSpace(h2) != "" {
+		h2Profile, err = parseAkamaiFingerprint(h2)
+		if err != nil {
+			return ClientProfile{}, err
+		}
+	}
+
+	client, version := options.client, options.version
+	if client == "" {
+		client = "JA3"
+	}
+	if version == "" {
+		sum := md5.Sum([]byte(strings.TrimSpace(ja3)))
+		version = hex.EncodeToString(sum[:])
+	}
+
+	clientHelloId := tls.ClientHelloID{
+		Client:  client,
+		Version: version,
+		SpecFactory: func() (tls.ClientHelloSpec, error) {
+			return parsed.spec(options)
+		},
+	}
+
+	return NewClientProfile(
+		clientHelloId,
+		h2Profile.settings,
+		h2Profile.settingsOrder,
+		h2Profile.pseudoHeaderOrder,
+		h2Profile.connectionFlow,
+		h2Profile.priorities,
+		options.headerPriority,
+	), nil
+}
+
+// ja3Fields 解析后的 JA3 字段
+type ja3Fields struct {
+	version    uint16
+	ciphers    []uint16
+	extensions []uint16
+	curves     []uint16
+	points     []uint16
+}
+
+// parseJA3 解析 JA3 字符串的 5 个字段，曲线和点格式允许为空
+func parseJA3(ja3 string) (ja3Fields, error) {
+	fields := strings.Split(strings.TrimSpace(ja3), ",")
+	if len(fields) != 5 {
+		return ja3Fields{}, &ErrInvalidFingerprint{Kind: "JA3", Reason: fmt.Sprintf("expected 5 comma separated fields, got %d", len(fields))}
+	}
+
+	version, err := strconv.ParseUint(fields[0], 10, 16)
+	if err != nil {
+		return ja3Fields{}, &ErrInvalidFingerprint{Kind: "JA3", Reason: fmt.Sprintf("invalid TLS version %q", fields[0])}
+	}
+
+	names := []string{"cipher", "extension", "curve", "point format"}
+	values := make([][]uint16, len(names))
+	for i, name := range names {
+		values[i], err = parseUint16List(fields[i+1])
+		if err != nil {
+			return ja3Fields{}, &ErrInvalidFingerprint{Kind: "JA3", Reason: fmt.Sprintf("invalid %s %v", name, err)}
+		}
+	}
+	if len(values[0]) == 0 {
+		return ja3Fields{}, &ErrInvalidFingerprint{Kind: "JA3", Reason: "cipher list is empty"}
+	}
+
+	for _, point := range values[3] {
+		if point > 0xff {
+			return ja3Fields{}, &ErrInvalidFingerprint{Kind: "JA3", Reason: fmt.Sprintf("invalid point format %d", point)}
+		}
+	}
+
+	return ja3Fields{
+		version:    uint16(version),
+		ciphers:    values[0],
+		extensions: values[1],
+		curves:     values[2],
+		points:     values[3],
+	}, nil
+}
+
+// parseUint16List 解析以 "-" 分隔的十进制列表，空字符串返回空列表
+func parseUint16List(field string) ([]uint16, error) {
+	if field == "" {
+		return nil, nil
+	}
+	parts := strings.Split(field, "-")
+	values := make([]uint16, 0, len(parts))
+	for _, part := range parts {
+		v, err := strconv.ParseUint(part, 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("%q", part)
+		}
+		values = append(values, uint16(v))
+	}
+	return values, nil
+}
+
+// spec 根据 JA3 字段构造 ClientHelloSpec，每次调用都返回新的扩展实例
+func (f ja3Fields) spec(options ja3Options) (tls.ClientHelloSpec, error) {
+	ciphers := make([]uint16, 0, len(f.ciphers)+1)
+	if options.grease {
+		ciphers = append(ciphers, tls.GREASE_PLACEHOLDER)
+	}
+	ciphers = append(ciphers, f.ciphers...)
+
+	curves := make([]tls.CurveID, 0, len(f.curves)+1)
+	if options.grease {
+		curves = append(curves, tls.CurveID(tls.GREASE_PLACEHOLDER))
+	}
+	for _, curve := range f.curves {
+		curves = append(curves, tls.CurveID(curve))
+	}
+
+	points := make([]byte, 0, len(f.points))
+	for _, point := range f.points {
+		points = append(points, byte(point))
+	}
+
+	hasSupportedVersions := false
+	for _, id := range f.extensions {
+		if id == tls.ExtensionSupportedVersions {
+			hasSupportedVersions = true
+		}
+	}
+
+	extensions := make([]tls.TLSExtension, 0, len(f.extensions)+2)
+	if options.grease {
+		extensions = append(extensions, &tls.UtlsGREASEExtension{})
+	}
+	// 第二个 GREASE 扩展在 padding 之前（BoringSSL 的行为），没有 padding 时在最后；
+	// pre_shared_key 必须是最后一个扩展，GREASE 扩展放在它之前
+	trailingGREASE := options.grease
+	for i, id := range f.extensions {
+		last := i == len(f.extensions)-1
+		if trailingGREASE && (id == tls.ExtensionPadding || (last && id == tls.ExtensionPreSharedKey)) {
+			extensions = append(extensions, &tls.UtlsGREASEExtension{})
+			trailingGREASE = false
+		}
+		ext, err := ja3Extension(id, curves, points, options)
+		if err != nil {
+			return tls.ClientHelloSpec{}, err
+		}
+		extensions = append(extensions, ext)
+	}
+	if trailingGREASE {
+		extensions = append(extensions, &tls.UtlsGREASEExtension{})
+	}
+
+	spec := tls.ClientHelloSpec{
+		CipherSuites:       ciphers,
+		CompressionMethods: []byte{tls.CompressionNone},
+		Extensions:         extensions,
+		TLSVersMax:         f.version,
+		TLSVersMin:         tls.VersionTLS10,
+	}
+	if hasSupportedVersions {
+		spec.TLSVersMax = tls.VersionTLS13
+		spec.TLSVersMin = tls.VersionTLS12
+	}
+	return spec, nil
+}
+
+// ja3Extension 根据扩展编号构造扩展，未知编号返回 tls.GenericExtension
+func ja3Extension(id uint16, curves []tls.CurveID, points []byte, options ja3Options) (tls.TLSExtension, error) {
+	switch id {
+	case tls.ExtensionServerName:
+		return &tls.SNIExtension{}, nil
+	case tls.ExtensionStatusRequest:
+		return &tls.StatusRequestExtension{}, nil
+	case tls.ExtensionSupportedCurves:
+		return &tls.SupportedCurvesExtension{Curves: append([]tls.CurveID(nil), curves...)}, nil
+	case tls.ExtensionSupportedPoints:
+		return &tls.SupportedPointsExtension{SupportedPoints: append([]byte(nil), points...)}, nil
+	case tls.ExtensionSignatureAlgorithms:
+		return &tls.SignatureAlgorithmsExtension{SupportedSignatureAlgorithms: append([]tls.SignatureScheme(nil), options.signatures...)}, nil
+	case tls.ExtensionALPN:
+		return &tls.ALPNExtension{AlpnProtocols: append([]string(nil), options.alpn...)}, nil
+	case tls.ExtensionStatusRequestV2:
+		return &tls.StatusRequestV2Extension{}, nil
+	case tls.ExtensionSCT:
+		return &tls.SCTExtension{}, nil
+	case tls.ExtensionPadding:
+		return &tls.UtlsPaddingExtension{GetPaddingLen: tls.BoringPaddingStyle}, nil
+	case tls.ExtensionExtendedMasterSecret:
+		return &tls.ExtendedMasterSecretExtension{}, nil
+	case extensionTokenBinding:
+		return &tls.FakeTokenBindingExtension{MajorVersion: 1, MinorVersion: 0, KeyParameters: []uint8{0, 1, 2}}, nil
+	case tls.ExtensionCompressCertificate:
+		return &tls.UtlsCompressCertExtension{Algorithms: append([]tls.CertCompressionAlgo(nil), options.certCompression...)}, nil
+	case tls.ExtensionRecordSizeLimit:
+		return &tls.FakeRecordSizeLimitExtension{Limit: 0x4001}, nil
+	case tls.ExtensionDelegatedCredentials:
+		return &tls.FakeDelegatedCredentialsExtension{SupportedSignatureAlgorithms: []tls.SignatureScheme{
+			tls.ECDSAWithP256AndSHA256,
+			tls.ECDSAWithP384AndSHA384,
+			tls.ECDSAWithP521AndSHA512,
+			tls.ECDSAWithSHA1,
+		}}, nil
+	case tls.ExtensionSessionTicket:
+		return &tls.SessionTicketExtension{}, nil
+	case tls.ExtensionPreSharedKey:
+		return &tls.UtlsPreSharedKeyExtension{}, nil
+	case tls.ExtensionSupportedVersions:
+		versions := make([]uint16, 0, 3)
+		if options.grease {
+			versions = append(versions, tls.GREASE_PLACEHOLDER)
+		}
+		versions = append(versions, tls.VersionTLS13, tls.VersionTLS12)
+		return &tls.SupportedVersionsExtension{Versions: versions}, nil
+	case tls.ExtensionCookie:
+		return &tls.CookieExtension{}, nil
+	case tls.ExtensionPSKModes:
+		return &tls.PSKKeyExchangeModesExtension{Modes: []uint8{tls.PskModeDHE}}, nil
+	case tls.ExtensionSignatureAlgorithmsCert:
+		return &tls.SignatureAlgorithmsCertExtension{SupportedSignatureAlgorithms: append([]tls.SignatureScheme(nil), options.signatures...)}, nil
+	case tls.ExtensionKeyShare:
+		return &tls.KeyShareExtension{KeyShares: ja3KeyShares(curves, options)}, nil
+	case tls.ExtensionNextProtoNeg:
+		return &tls.NPNExtension{}, nil
+	case tls.ExtensionALPSOld:
+		return &tls.ApplicationSettingsExtension{SupportedProtocols: ja3ALPSProtocols(options.alpn)}, nil
+	case tls.ExtensionALPS:
+		return &tls.ApplicationSettingsExtensionNew{SupportedProtocols: ja3ALPSProtocols(options.alpn)}, nil
+	case extensionChannelIDOld:
+		return &tls.FakeChannelIDExtension{OldExtensionID: true}, nil
+	case extensionChannelID:
+		return &tls.FakeChannelIDExtension{}, nil
+	case tls.ExtensionECH:
+		return tls.BoringGREASEECH(), nil
+	case tls.ExtensionRenegotiationInfo:
+		return &tls.RenegotiationInfoExtension{Renegotiation: tls.RenegotiateOnceAsClient}, nil
+	}
+
+	if isGREASE(id) {
+		return &tls.UtlsGREASEExtension{}, nil
+	}
+	return &tls.GenericExtension{Id: id}, nil
+}
+
+// ja3KeyShares 返回 key_share 扩展中发送的曲线
+func ja3KeyShares(curves []tls.CurveID, options ja3Options) []tls.KeyShare {
+	shares := make([]tls.KeyShare, 0, 3)
+	if options.grease {
+		shares = append(shares, tls.KeyShare{Group: tls.CurveID(tls.GREASE_PLACEHOLDER), Data: []byte{0}})
+	}
+
+	if len(options.keyShareCurves) > 0 {
+		for _, curve := range options.keyShareCurves {
+			shares = append(shares, tls.KeyShare{Group: curve})
+		}
+		return shares
+	}
+
+	hasX25519 := false
+	for _, curve := range curves {
+		if curve == tls.X25519 {
+			hasX25519 = true
+		}
+	}
+	for _, curve := range curves {
+		if curve == tls.X25519MLKEM768 || curve == tls.X25519Kyber768Draft00 {
+			shares = append(shares, tls.KeyShare{Group: curve})
+			if hasX25519 {
+				shares = append(shares, tls.KeyShare{Group: tls.X25519})
+			}
+			return shares
+		}
+	}
+	for _, curve := range curves {
+		if !isGREASE(uint16(curve)) {
+			return append(shares, tls.KeyShare{Group: curve})
+		}
+	}
+	return append(shares, tls.KeyShare{Group: tls.X25519})
+}
+
+// ja3ALPSProtocols ALPS 扩展只声明 HTTP/2 及以上协议
+func ja3ALPSProtocols(alpn []string) []string {
+	protocols := make([]string, 0, len(alpn))
+	for _, p := range alpn {
+		if p == "h2" || p == "h3" {
+			protocols = append(protocols, p)
+		}
+	}
+	if len(protocols) == 0 {
+		protocols = append(protocols, "h2")
+	}
+	return protocols
+}
+
+// pseudoHeaderNames Akamai 指纹中伪头部缩写与名称的对应关系
+var pseudoHeaderNames = map[string]string{
+	"m": ":method",
+	"a": ":authority",
+	"s": ":scheme",
+	"p": ":path",
+}
+
+// parseAkamaiFingerprint 解析 Akamai HTTP/2 指纹，返回只包含 HTTP/2 参数的 ClientProfile
+// PRIORITY 字段中的 weight 为帧中的实际权重，会转换为 PriorityParam.Weight（减 1）
+func parseAkamaiFingerprint(h2 string) (ClientProfile, error) {
+	invalid := func(format string, args ...any) error {
+		return &ErrInvalidFingerprint{Kind: "Akamai HTTP/2", Reason: fmt.Sprintf(format, args...)}
+	}
+
+	fields := strings.Split(strings.TrimSpace(h2), "|")
+	if len(fields) != 4 {
+		return ClientProfile{}, invalid("expected 4 '|' separated fields, got %d", len(fields))
+	}
+
+	settings := make(map[http2.SettingID]uint32)
+	settingsOrder := make([]http2.SettingID, 0)
+	if fields[0] != "" {
+		for _, setting := range strings.Split(fields[0], ";") {
+			key, value, ok := strings.Cut(setting, ":")
+			if !ok {
+				return ClientProfile{}, invalid("invalid setting %q", setting)
+			}
+			id, err := strconv.ParseUint(key, 10, 16)
+			if err != nil {
+				return ClientProfile{}, invalid("invalid setting id %q", key)
+			}
+			v, err := strconv.ParseUint(value, 10, 32)
+			if err != nil {
+				return ClientProfile{}, invalid("invalid setting value %q", value)
+			}
+			if _, exists := settings[http2.SettingID(id)]; exists {
+				return ClientProfile{}, invalid("duplicate setting %d", id)
+			}
+			settings[http2.SettingID(id)] = uint32(v)
+			settingsOrder = append(settingsOrder, http2.SettingID(id))
+		}
+	}
+
+	connectionFlow, err := strconv.ParseUint(fields[1], 10, 32)
+	if err != nil {
+		return ClientProfile{}, invalid("invalid window update %q", fields[1])
+	}
+
+	var priorities []http2.Priority
+	if fields[2] != "0" && fields[2] != "" {
+		for _, frame := range strings.Split(fields[2], ",") {
+			parts := strings.Split(frame, ":")
+			if len(parts) != 4 {
+				return ClientProfile{}, invalid("invalid priority frame %q", frame)
+			}
+			numbers := make([]uint64, len(parts))
+			for i, part := range parts {
+				if numbers[i], err = strconv.ParseUint(part, 10, 32); err != nil {
+					return ClientProfile{}, invalid("invalid priority frame %q", frame)
+				}
+			}
+			if numbers[1] > 1 || numbers[3] < 1 || numbers[3] > 256 {
+				return ClientProfile{}, invalid("invalid priority frame %q", frame)
+			}
+			priorities = append(priorities, http2.Priority{
+				StreamID: uint32(numbers[0]),
+				PriorityParam: http2.PriorityParam{
+					Exclusive: numbers[1] == 1,
+					StreamDep: uint32(numbers[2]),
+					Weight:    uint8(numbers[3] - 1),
+				},
+			})
+		}
+	}
+
+	pseudoHeaderOrder := make([]string, 0, 4)
+	if fields[3] != "" {
+		for _, short := range strings.Split(fields[3], ",") {
+			name, ok := pseudoHeaderNames[short]
+			if !ok {
+				return ClientProfile{}, invalid("unknown pseudo header %q", short)
+			}
+			pseudoHeaderOrder = append(pseudoHeaderOrder, name)
+		}
+	}
+
+	return ClientProfile{
+		settings:          settings,
+		settingsOrder:     settingsOrder,
+		connectionFlow:    uint32(connectionFlow),
+		priorities:        priorities,
+		pseudoHeaderOrder: pseudoHeaderOrder,
+	}, nil
+}
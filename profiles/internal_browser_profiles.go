@@ -1,11 +1,14 @@
 package profiles
 
 import (
+	"time"
+
 	"github.com/bogdanfinn/fhttp/http2"
 	tls "github.com/bogdanfinn/utls"
 )
 
 var Chrome_133_PSK = ClientProfile{
+	metadata: Metadata{Browser: BrowserChrome, Engine: EngineBlink, MajorVersion: 133, Device: DeviceDesktop, ReleaseDate: releaseDate(2025, time.February, 4), Features: FeaturePSK | FeaturePQ | FeatureECH},
 	clientHelloId: tls.ClientHelloID{
 		Client:               "Chrome",
 		RandomExtensionOrder: false,
@@ -117,6 +120,7 @@ var Chrome_133_PSK = ClientProfile{
 }
 
 var Chrome_133 = ClientProfile{
+	metadata: Metadata{Browser: BrowserChrome, Engine: EngineBlink, MajorVersion: 133, Device: DeviceDesktop, ReleaseDate: releaseDate(2025, time.February, 4), Features: FeaturePQ | FeatureECH},
 	clientHelloId: tls.ClientHelloID{
 		Client:               "Chrome",
 		RandomExtensionOrder: false,
@@ -227,6 +231,7 @@ var Chrome_133 = ClientProfile{
 }
 
 var Chrome_117 = ClientProfile{
+	metadata: Metadata{Browser: BrowserChrome, Engine: EngineBlink, MajorVersion: 117, Device: DeviceDesktop, ReleaseDate: releaseDate(2023, time.September, 12)},
 	clientHelloId: tls.ClientHelloID{
 		Client:               "Chrome",
 		RandomExtensionOrder: false,
@@ -329,6 +334,7 @@ var Chrome_117 = ClientProfile{
 }
 
 var Chrome_124 = ClientProfile{
+	metadata: Metadata{Browser: BrowserChrome, Engine: EngineBlink, MajorVersion: 124, Device: DeviceDesktop, ReleaseDate: releaseDate(2024, time.April, 16), Features: FeaturePQ | FeatureECH},
 	clientHelloId: tls.ClientHelloID{
 		Client:               "Chrome",
 		RandomExtensionOrder: false,
@@ -433,6 +439,7 @@ var Chrome_124 = ClientProfile{
 }
 
 var Chrome_120 = ClientProfile{
+	metadata: Metadata{Browser: BrowserChrome, Engine: EngineBlink, MajorVersion: 120, Device: DeviceDesktop, ReleaseDate: releaseDate(2023, time.December, 5), Features: FeatureECH},
 	clientHelloId: tls.ClientHelloID{
 		Client:               "Chrome",
 		RandomExtensionOrder: false,
@@ -535,6 +542,7 @@ var Chrome_120 = ClientProfile{
 }
 
 var Chrome_112 = ClientProfile{
+	metadata:      Metadata{Browser: BrowserChrome, Engine: EngineBlink, MajorVersion: 112, Device: DeviceDesktop, ReleaseDate: releaseDate(2023, time.April, 4)},
 	clientHelloId: tls.HelloChrome_112,
 	settings: map[http2.SettingID]uint32{
 		http2.SettingHeaderTableSize:      65536,
@@ -560,6 +568,7 @@ var Chrome_112 = ClientProfile{
 }
 
 var Chrome_116_PSK = ClientProfile{
+	metadata:      Metadata{Browser: BrowserChrome, Engine: EngineBlink, MajorVersion: 116, Device: DeviceDesktop, ReleaseDate: releaseDate(2023, time.August, 15), Features: FeaturePSK},
 	clientHelloId: tls.HelloChrome_112_PSK,
 	settings: map[http2.SettingID]uint32{
 		http2.SettingHeaderTableSize:      65536,
@@ -585,6 +594,7 @@ var Chrome_116_PSK = ClientProfile{
 }
 
 var Chrome_116_PSK_PQ = ClientProfile{
+	metadata:      Metadata{Browser: BrowserChrome, Engine: EngineBlink, MajorVersion: 116, Device: DeviceDesktop, ReleaseDate: releaseDate(2023, time.August, 15), Features: FeaturePSK | FeaturePQ},
 	clientHelloId: tls.HelloChrome_115_PQ_PSK,
 	settings: map[http2.SettingID]uint32{
 		http2.SettingHeaderTableSize:      65536,
@@ -610,6 +620,7 @@ var Chrome_116_PSK_PQ = ClientProfile{
 }
 
 var Chrome_111 = ClientProfile{
+	metadata:      Metadata{Browser: BrowserChrome, Engine: EngineBlink, MajorVersion: 111, Device: DeviceDesktop, ReleaseDate: releaseDate(2023, time.March, 7)},
 	clientHelloId: tls.HelloChrome_111,
 	settings: map[http2.SettingID]uint32{
 		http2.SettingHeaderTableSize:      65536,
@@ -635,6 +646,7 @@ var Chrome_111 = ClientProfile{
 }
 
 var Chrome_110 = ClientProfile{
+	metadata:      Metadata{Browser: BrowserChrome, Engine: EngineBlink, MajorVersion: 110, Device: DeviceDesktop, ReleaseDate: releaseDate(2023, time.February, 7)},
 	clientHelloId: tls.HelloChrome_110,
 	settings: map[http2.SettingID]uint32{
 		http2.SettingHeaderTableSize:      65536,
@@ -660,6 +672,7 @@ var Chrome_110 = ClientProfile{
 }
 
 var Chrome_109 = ClientProfile{
+	metadata:      Metadata{Browser: BrowserChrome, Engine: EngineBlink, MajorVersion: 109, Device: DeviceDesktop, ReleaseDate: releaseDate(2023, time.January, 10)},
 	clientHelloId: tls.HelloChrome_109,
 	settings: map[http2.SettingID]uint32{
 		http2.SettingHeaderTableSize:      65536,
@@ -685,6 +698,7 @@ var Chrome_109 = ClientProfile{
 }
 
 var Chrome_108 = ClientProfile{
+	metadata:      Metadata{Browser: BrowserChrome, Engine: EngineBlink, MajorVersion: 108, Device: DeviceDesktop, ReleaseDate: releaseDate(2022, time.November, 29)},
 	clientHelloId: tls.HelloChrome_108,
 	settings: map[http2.SettingID]uint32{
 		http2.SettingHeaderTableSize:      65536,
@@ -710,6 +724,7 @@ var Chrome_108 = ClientProfile{
 }
 
 var Chrome_107 = ClientProfile{
+	metadata:      Metadata{Browser: BrowserChrome, Engine: EngineBlink, MajorVersion: 107, Device: DeviceDesktop, ReleaseDate: releaseDate(2022, time.October, 25)},
 	clientHelloId: tls.HelloChrome_107,
 	settings: map[http2.SettingID]uint32{
 		http2.SettingHeaderTableSize:      65536,
@@ -735,6 +750,7 @@ var Chrome_107 = ClientProfile{
 }
 
 var Chrome_106 = ClientProfile{
+	metadata:      Metadata{Browser: BrowserChrome, Engine: EngineBlink, MajorVersion: 106, Device: DeviceDesktop, ReleaseDate: releaseDate(2022, time.September, 27)},
 	clientHelloId: tls.HelloChrome_106,
 	settings: map[http2.SettingID]uint32{
 		http2.SettingHeaderTableSize:      65536,
@@ -760,6 +776,7 @@ var Chrome_106 = ClientProfile{
 }
 
 var Chrome_105 = ClientProfile{
+	metadata:      Metadata{Browser: BrowserChrome, Engine: EngineBlink, MajorVersion: 105, Device: DeviceDesktop, ReleaseDate: releaseDate(2022, time.August, 30)},
 	clientHelloId: tls.HelloChrome_105,
 	settings: map[http2.SettingID]uint32{
 		http2.SettingHeaderTableSize:      65536,
@@ -783,6 +800,7 @@ var Chrome_105 = ClientProfile{
 }
 
 var Chrome_104 = ClientProfile{
+	metadata:      Metadata{Browser: BrowserChrome, Engine: EngineBlink, MajorVersion: 104, Device: DeviceDesktop, ReleaseDate: releaseDate(2022, time.August, 2)},
 	clientHelloId: tls.HelloChrome_104,
 	settings: map[http2.SettingID]uint32{
 		http2.SettingHeaderTableSize:      65536,
@@ -806,6 +824,7 @@ var Chrome_104 = ClientProfile{
 }
 
 var Chrome_103 = ClientProfile{
+	metadata:      Metadata{Browser: BrowserChrome, Engine: EngineBlink, MajorVersion: 103, Device: DeviceDesktop, ReleaseDate: releaseDate(2022, time.June, 21)},
 	clientHelloId: tls.HelloChrome_103,
 	settings: map[http2.SettingID]uint32{
 		http2.SettingHeaderTableSize:      65536,
@@ -829,6 +848,7 @@ var Chrome_103 = ClientProfile{
 }
 
 var Safari_15_6_1 = ClientProfile{
+	metadata:      Metadata{Browser: BrowserSafari, Engine: EngineWebKit, MajorVersion: 15, MinorVersion: 6, Platform: PlatformMacOS, Device: DeviceDesktop, ReleaseDate: releaseDate(2022, time.August, 17)},
 	clientHelloId: tls.HelloSafari_15_6_1,
 	settings: map[http2.SettingID]uint32{
 		http2.SettingInitialWindowSize:    4194304,
@@ -848,6 +868,7 @@ var Safari_15_6_1 = ClientProfile{
 }
 
 var Safari_16_0 = ClientProfile{
+	metadata:      Metadata{Browser: BrowserSafari, Engine: EngineWebKit, MajorVersion: 16, Platform: PlatformMacOS, Device: DeviceDesktop, ReleaseDate: releaseDate(2022, time.September, 12)},
 	clientHelloId: tls.HelloSafari_16_0,
 	settings: map[http2.SettingID]uint32{
 		http2.SettingInitialWindowSize:    4194304,
@@ -867,6 +888,7 @@ var Safari_16_0 = ClientProfile{
 }
 
 var Safari_Ipad_15_6 = ClientProfile{
+	metadata:      Metadata{Browser: BrowserSafari, Engine: EngineWebKit, MajorVersion: 15, MinorVersion: 6, Platform: PlatformIPadOS, Device: DeviceTablet, ReleaseDate: releaseDate(2022, time.July, 20)},
 	clientHelloId: tls.HelloIPad_15_6,
 	settings: map[http2.SettingID]uint32{
 		http2.SettingInitialWindowSize:    2097152,
@@ -886,6 +908,7 @@ var Safari_Ipad_15_6 = ClientProfile{
 }
 
 var Safari_IOS_17_0 = ClientProfile{
+	metadata: Metadata{Browser: BrowserSafari, Engine: EngineWebKit, MajorVersion: 17, Platform: PlatformIOS, Device: DeviceMobile, ReleaseDate: releaseDate(2023, time.September, 18)},
 	clientHelloId: tls.ClientHelloID{
 		Client:               "iOS",
 		RandomExtensionOrder: false,
@@ -993,6 +1016,7 @@ var Safari_IOS_17_0 = ClientProfile{
 }
 
 var Safari_IOS_18_5 = ClientProfile{
+	metadata: Metadata{Browser: BrowserSafari, Engine: EngineWebKit, MajorVersion: 18, MinorVersion: 5, Platform: PlatformIOS, Device: DeviceMobile, ReleaseDate: releaseDate(2025, time.May, 12)},
 	clientHelloId: tls.ClientHelloID{
 		Client:               "iOS",
 		RandomExtensionOrder: false,
@@ -1106,6 +1130,7 @@ var Safari_IOS_18_5 = ClientProfile{
 }
 
 var Safari_IOS_18_0 = ClientProfile{
+	metadata: Metadata{Browser: BrowserSafari, Engine: EngineWebKit, MajorVersion: 18, Platform: PlatformIOS, Device: DeviceMobile, ReleaseDate: releaseDate(2024, time.September, 16)},
 	clientHelloId: tls.ClientHelloID{
 		Client:               "iOS",
 		RandomExtensionOrder: false,
@@ -1217,6 +1242,7 @@ var Safari_IOS_18_0 = ClientProfile{
 }
 
 var Safari_IOS_16_0 = ClientProfile{
+	metadata:      Metadata{Browser: BrowserSafari, Engine: EngineWebKit, MajorVersion: 16, Platform: PlatformIOS, Device: DeviceMobile, ReleaseDate: releaseDate(2022, time.September, 12)},
 	clientHelloId: tls.HelloIOS_16_0,
 	settings: map[http2.SettingID]uint32{
 		http2.SettingInitialWindowSize:    2097152,
@@ -1236,6 +1262,7 @@ var Safari_IOS_16_0 = ClientProfile{
 }
 
 var Safari_IOS_15_5 = ClientProfile{
+	metadata:      Metadata{Browser: BrowserSafari, Engine: EngineWebKit, MajorVersion: 15, MinorVersion: 5, Platform: PlatformIOS, Device: DeviceMobile, ReleaseDate: releaseDate(2022, time.May, 16)},
 	clientHelloId: tls.HelloIOS_15_5,
 	settings: map[http2.SettingID]uint32{
 		http2.SettingInitialWindowSize:    2097152,
@@ -1255,6 +1282,7 @@ var Safari_IOS_15_5 = ClientProfile{
 }
 
 var Safari_IOS_15_6 = ClientProfile{
+	metadata:      Metadata{Browser: BrowserSafari, Engine: EngineWebKit, MajorVersion: 15, MinorVersion: 6, Platform: PlatformIOS, Device: DeviceMobile, ReleaseDate: releaseDate(2022, time.July, 20)},
 	clientHelloId: tls.HelloIOS_15_6,
 	settings: map[http2.SettingID]uint32{
 		http2.SettingInitialWindowSize:    2097152,
@@ -1274,6 +1302,7 @@ var Safari_IOS_15_6 = ClientProfile{
 }
 
 var Firefox_117 = ClientProfile{
+	metadata: Metadata{Browser: BrowserFirefox, Engine: EngineGecko, MajorVersion: 117, Device: DeviceDesktop, ReleaseDate: releaseDate(2023, time.August, 29)},
 	clientHelloId: tls.ClientHelloID{
 		Client:               "Firefox",
 		RandomExtensionOrder: false,
@@ -1417,6 +1446,7 @@ var Firefox_117 = ClientProfile{
 }
 
 var Firefox_110 = ClientProfile{
+	metadata:      Metadata{Browser: BrowserFirefox, Engine: EngineGecko, MajorVersion: 110, Device: DeviceDesktop, ReleaseDate: releaseDate(2023, time.February, 14)},
 	clientHelloId: tls.HelloFirefox_110,
 	settings: map[http2.SettingID]uint32{
 		http2.SettingHeaderTableSize:   65536,
@@ -1475,6 +1505,7 @@ var Firefox_110 = ClientProfile{
 }
 
 var Firefox_108 = ClientProfile{
+	metadata:      Metadata{Browser: BrowserFirefox, Engine: EngineGecko, MajorVersion: 108, Device: DeviceDesktop, ReleaseDate: releaseDate(2022, time.December, 13)},
 	clientHelloId: tls.HelloFirefox_108,
 	settings: map[http2.SettingID]uint32{
 		http2.SettingHeaderTableSize:   65536,
@@ -1533,6 +1564,7 @@ var Firefox_108 = ClientProfile{
 }
 
 var Firefox_106 = ClientProfile{
+	metadata:      Metadata{Browser: BrowserFirefox, Engine: EngineGecko, MajorVersion: 106, Device: DeviceDesktop, ReleaseDate: releaseDate(2022, time.October, 18)},
 	clientHelloId: tls.HelloFirefox_106,
 	settings: map[http2.SettingID]uint32{
 		http2.SettingHeaderTableSize:   65536,
@@ -1591,6 +1623,7 @@ var Firefox_106 = ClientProfile{
 }
 
 var Firefox_105 = ClientProfile{
+	metadata:      Metadata{Browser: BrowserFirefox, Engine: EngineGecko, MajorVersion: 105, Device: DeviceDesktop, ReleaseDate: releaseDate(2022, time.September, 20)},
 	clientHelloId: tls.HelloFirefox_105,
 	settings: map[http2.SettingID]uint32{
 		http2.SettingHeaderTableSize:   65536,
@@ -1649,6 +1682,7 @@ var Firefox_105 = ClientProfile{
 }
 
 var Firefox_104 = ClientProfile{
+	metadata:      Metadata{Browser: BrowserFirefox, Engine: EngineGecko, MajorVersion: 104, Device: DeviceDesktop, ReleaseDate: releaseDate(2022, time.August, 23)},
 	clientHelloId: tls.HelloFirefox_104,
 	settings: map[http2.SettingID]uint32{
 		http2.SettingHeaderTableSize:   65536,
@@ -1707,6 +1741,7 @@ var Firefox_104 = ClientProfile{
 }
 
 var Firefox_102 = ClientProfile{
+	metadata:      Metadata{Browser: BrowserFirefox, Engine: EngineGecko, MajorVersion: 102, Device: DeviceDesktop, ReleaseDate: releaseDate(2022, time.June, 28)},
 	clientHelloId: tls.HelloFirefox_102,
 	settings: map[http2.SettingID]uint32{
 		http2.SettingHeaderTableSize:   65536,
@@ -1765,6 +1800,7 @@ var Firefox_102 = ClientProfile{
 }
 
 var Opera_90 = ClientProfile{
+	metadata:      Metadata{Browser: BrowserOpera, Engine: EngineBlink, MajorVersion: 90, Device: DeviceDesktop, ReleaseDate: releaseDate(2022, time.August, 18)},
 	clientHelloId: tls.HelloOpera_90,
 	settings: map[http2.SettingID]uint32{
 		http2.SettingHeaderTableSize:      65536,
@@ -1788,6 +1824,7 @@ var Opera_90 = ClientProfile{
 }
 
 var Opera_91 = ClientProfile{
+	metadata:      Metadata{Browser: BrowserOpera, Engine: EngineBlink, MajorVersion: 91, Device: DeviceDesktop, ReleaseDate: releaseDate(2022, time.September, 14)},
 	clientHelloId: tls.HelloOpera_91,
 	settings: map[http2.SettingID]uint32{
 		http2.SettingHeaderTableSize:      65536,
@@ -1811,6 +1848,7 @@ var Opera_91 = ClientProfile{
 }
 
 var Opera_89 = ClientProfile{
+	metadata:      Metadata{Browser: BrowserOpera, Engine: EngineBlink, MajorVersion: 89, Device: DeviceDesktop, ReleaseDate: releaseDate(2022, time.July, 7)},
 	clientHelloId: tls.HelloOpera_89,
 	settings: map[http2.SettingID]uint32{
 		http2.SettingHeaderTableSize:      65536,
@@ -1832,3 +1870,99 @@ var Opera_89 = ClientProfile{
 	},
 	connectionFlow: 15663105,
 }
+
+// Edge_133 Microsoft Edge 133（桌面）
+// Edge 与同版本 Chrome 使用相同的 Chromium TLS 实现和 HTTP/2 设置
+var Edge_133 = ClientProfile{
+	metadata: Metadata{Browser: BrowserEdge, Engine: EngineBlink, MajorVersion: 133, Device: DeviceDesktop, ReleaseDate: releaseDate(2025, time.February, 6), Features: FeaturePQ | FeatureECH},
+	clientHelloId: tls.ClientHelloID{
+		Client:               "Edge",
+		RandomExtensionOrder: false,
+		Version:              "133",
+		Seed:                 nil,
+		SpecFactory:          Chrome_133.clientHelloId.SpecFactory,
+	},
+	settings: map[http2.SettingID]uint32{
+		http2.SettingHeaderTableSize:   65536,
+		http2.SettingEnablePush:        0,
+		http2.SettingInitialWindowSize: 6291456,
+		http2.SettingMaxHeaderListSize: 262144,
+	},
+	settingsOrder: []http2.SettingID{
+		http2.SettingHeaderTableSize,
+		http2.SettingEnablePush,
+		http2.SettingInitialWindowSize,
+		http2.SettingMaxHeaderListSize,
+	},
+	pseudoHeaderOrder: []string{
+		":method",
+		":authority",
+		":scheme",
+		":path",
+	},
+	connectionFlow: 15663105,
+}
+
+// Edge_131 Microsoft Edge 131（桌面）
+// Edge 与同版本 Chrome 使用相同的 Chromium TLS 实现和 HTTP/2 设置
+var Edge_131 = ClientProfile{
+	metadata: Metadata{Browser: BrowserEdge, Engine: EngineBlink, MajorVersion: 131, Device: DeviceDesktop, ReleaseDate: releaseDate(2024, time.November, 14), Features: FeaturePQ | FeatureECH},
+	clientHelloId: tls.ClientHelloID{
+		Client:               "Edge",
+		RandomExtensionOrder: false,
+		Version:              "131",
+		Seed:                 nil,
+		SpecFactory:          Chrome_131.clientHelloId.SpecFactory,
+	},
+	settings: map[http2.SettingID]uint32{
+		http2.SettingHeaderTableSize:   65536,
+		http2.SettingEnablePush:        0,
+		http2.SettingInitialWindowSize: 6291456,
+		http2.SettingMaxHeaderListSize: 262144,
+	},
+	settingsOrder: []http2.SettingID{
+		http2.SettingHeaderTableSize,
+		http2.SettingEnablePush,
+		http2.SettingInitialWindowSize,
+		http2.SettingMaxHeaderListSize,
+	},
+	pseudoHeaderOrder: []string{
+		":method",
+		":authority",
+		":scheme",
+		":path",
+	},
+	connectionFlow: 15663105,
+}
+
+// Edge_Android_131 Microsoft Edge 131（Android）
+// Edge 与同版本 Chrome 使用相同的 Chromium TLS 实现和 HTTP/2 设置
+var Edge_Android_131 = ClientProfile{
+	metadata: Metadata{Browser: BrowserEdge, Engine: EngineBlink, MajorVersion: 131, Platform: PlatformAndroid, Device: DeviceMobile, ReleaseDate: releaseDate(2024, time.November, 14), Features: FeaturePQ | FeatureECH},
+	clientHelloId: tls.ClientHelloID{
+		Client:               "Edge",
+		RandomExtensionOrder: false,
+		Version:              "131_Android",
+		Seed:                 nil,
+		SpecFactory:          Chrome_131.clientHelloId.SpecFactory,
+	},
+	settings: map[http2.SettingID]uint32{
+		http2.SettingHeaderTableSize:   65536,
+		http2.SettingEnablePush:        0,
+		http2.SettingInitialWindowSize: 6291456,
+		http2.SettingMaxHeaderListSize: 262144,
+	},
+	settingsOrder: []http2.SettingID{
+		http2.SettingHeaderTableSize,
+		http2.SettingEnablePush,
+		http2.SettingInitialWindowSize,
+		http2.SettingMaxHeaderListSize,
+	},
+	pseudoHeaderOrder: []string{
+		":method",
+		":authority",
+		":scheme",
+		":path",
+	},
+	connectionFlow: 15663105,
+}
@@ -7,7 +7,7 @@ import (
 	tls "github.com/bogdanfinn/utls"
 )
 
-var MMSIos2 = getMMSClientProfile2()
+var MMSIos2 = getMMSClientProfile2().WithMetadata(Metadata{Platform: PlatformIOS, Device: DeviceMobile, App: "mms"})
 
 func getMMSClientProfile2() ClientProfile {
 	clientHelloId := tls.ClientHelloID{
@@ -105,7 +105,7 @@ func getMMSClientProfile2() ClientProfile {
 	return NewClientProfile(clientHelloId, settings, settingsOrder, pseudoHeaderOrder, 15663105, nil, nil)
 }
 
-var MMSIos3 = getMMSClientProfile3()
+var MMSIos3 = getMMSClientProfile3().WithMetadata(Metadata{Platform: PlatformIOS, Device: DeviceMobile, App: "mms"})
 
 func getMMSClientProfile3() ClientProfile {
 	clientHelloId := tls.ClientHelloID{
@@ -0,0 +1,107 @@
+package profiles
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"strconv"
+	"strings"
+
+	tls "github.com/bogdanfinn/utls"
+)
+
+// JA3 返回 profile 的 JA3 指纹字符串
+// 格式：TLSVersion,Ciphers,Extensions,EllipticCurves,EllipticCurvePointFormats
+// 所有 GREASE 值（密码套件、扩展、曲线）都会被去除，扩展保持 ClientHelloSpec 中的顺序
+func (c ClientProfile) JA3() (string, error) {
+	spec, err := c.resolveClientHelloSpec()
+	if err != nil {
+		return "", err
+	}
+	return ja3FromSpec(spec)
+}
+
+// JA3Hash 返回 JA3 字符串的 MD5 哈希（小写十六进制）
+func (c ClientProfile) JA3Hash() (string, error) {
+	ja3, err := c.JA3()
+	if err != nil {
+		return "", err
+	}
+	sum := md5.Sum([]byte(ja3))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// ja3FromSpec 根据 ClientHelloSpec 计算 JA3 字符串
+func ja3FromSpec(spec tls.ClientHelloSpec) (string, error) {
+	ciphers := make([]uint16, 0, len(spec.CipherSuites))
+	for _, cipher := range spec.CipherSuites {
+		if !isGREASE(cipher) {
+			ciphers = append(ciphers, cipher)
+		}
+	}
+
+	extensions := make([]uint16, 0, len(spec.Extensions))
+	curves := make([]uint16, 0)
+	points := make([]uint16, 0)
+	for _, ext := range spec.Extensions {
+		id, err := extensionID(ext)
+		if err != nil {
+			return "", err
+		}
+		if isGREASE(id) {
+			continue
+		}
+		extensions = append(extensions, id)
+
+		switch e := ext.(type) {
+		case *tls.SupportedCurvesExtension:
+			for _, curve := range e.Curves {
+				if !isGREASE(uint16(curve)) {
+					curves = append(curves, uint16(curve))
+				}
+			}
+		case *tls.SupportedPointsExtension:
+			for _, point := range e.SupportedPoints {
+				points = append(points, uint16(point))
+			}
+		}
+	}
+
+	fields := []string{
+		strconv.Itoa(int(clientHelloVersion(spec))),
+		joinUint16(ciphers, "-"),
+		joinUint16(extensions, "-"),
+		joinUint16(curves, "-"),
+		joinUint16(points, "-"),
+	}
+	return strings.Join(fields, ","), nil
+}
+
+// clientHelloVersion 返回 ClientHello 中 legacy_version 字段的值
+// TLS 1.3 客户端在该字段中固定发送 TLS 1.2（771），真实版本放在 supported_versions 扩展中
+func clientHelloVersion(spec tls.ClientHelloSpec) uint16 {
+	maxVersion := spec.TLSVersMax
+	if maxVersion == 0 {
+		for _, ext := range spec.Extensions {
+			if e, ok := ext.(*tls.SupportedVersionsExtension); ok {
+				for _, v := range e.Versions {
+					if !isGREASE(v) && v > maxVersion {
+						maxVersion = v
+					}
+				}
+			}
+		}
+	}
+	if maxVersion == 0 || maxVersion > tls.VersionTLS12 {
+		return tls.VersionTLS12
+	}
+	return maxVersion
+}
+
+// joinUint16 将数字以十进制形式用 sep 连接
+func joinUint16(values []uint16, sep string) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = strconv.Itoa(int(v))
+	}
+	return strings.Join(parts, sep)
+}
@@ -0,0 +1,179 @@
+package profiles
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	tls "github.com/bogdanfinn/utls"
+)
+
+// JA4 返回 profile 的 JA4 指纹（FoxIO JA4 TLS Client Fingerprint）
+// 格式：a_b_c
+//   - a：协议(t/q) + TLS 版本 + SNI 标志(d/i) + 密码套件数量 + 扩展数量 + ALPN 首尾字符
+//   - b：排序后的密码套件列表的 SHA256 前 12 位
+//   - c：排序后的扩展列表（不含 SNI、ALPN）加签名算法列表的 SHA256 前 12 位
+//
+// GREASE 值（包括 UtlsGREASEExtension）不参与计算；BoringGREASEECH 是真实的 ECH 扩展（0xfe0d），会被计入；
+// PSK 扩展（41）按 ClientHelloSpec 中的定义计入
+func (c ClientProfile) JA4() (string, error) {
+	parts, err := c.ja4Parts()
+	if err != nil {
+		return "", err
+	}
+	return parts.prefix + "_" + ja4Hash(parts.ciphers) + "_" + ja4Hash(parts.extensionsAndSignatures()), nil
+}
+
+// JA4R 返回 JA4_r（raw）格式的指纹，即 JA4 中参与哈希的原始列表
+func (c ClientProfile) JA4R() (string, error) {
+	parts, err := c.ja4Parts()
+	if err != nil {
+		return "", err
+	}
+	return parts.prefix + "_" + parts.ciphers + "_" + parts.extensionsAndSignatures(), nil
+}
+
+// ja4Components JA4 计算的中间结果
+type ja4Components struct {
+	prefix     string // JA4_a
+	ciphers    string // 排序后的密码套件（4 位十六进制，逗号分隔）
+	extensions string // 排序后的扩展（不含 SNI、ALPN）
+	signatures string // 签名算法（保持原始顺序）
+}
+
+// extensionsAndSignatures 返回 JA4_c 的原始字符串
+func (p ja4Components) extensionsAndSignatures() string {
+	if p.signatures == "" {
+		return p.extensions
+	}
+	return p.extensions + "_" + p.signatures
+}
+
+// ja4Parts 根据 ClientHelloSpec 计算 JA4 的各个部分
+func (c ClientProfile) ja4Parts() (ja4Components, error) {
+	spec, err := c.resolveClientHelloSpec()
+	if err != nil {
+		return ja4Components{}, err
+	}
+
+	ciphers := make([]string, 0, len(spec.CipherSuites))
+	for _, cipher := range spec.CipherSuites {
+		if !isGREASE(cipher) {
+			ciphers = append(ciphers, fmt.Sprintf("%04x", cipher))
+		}
+	}
+
+	protocol := "t"
+	hasSNI := false
+	extensionCount := 0
+	extensions := make([]string, 0, len(spec.Extensions))
+	signatures := make([]string, 0)
+	alpn := "00"
+	for _, ext := range spec.Extensions {
+		id, err := extensionID(ext)
+		if err != nil {
+			return ja4Components{}, err
+		}
+		if isGREASE(id) {
+			continue
+		}
+		extensionCount++
+
+		switch e := ext.(type) {
+		case *tls.SNIExtension:
+			hasSNI = true
+			continue
+		case *tls.ALPNExtension:
+			if len(e.AlpnProtocols) > 0 {
+				alpn = ja4ALPN(e.AlpnProtocols[0])
+			}
+			continue
+		case *tls.SignatureAlgorithmsExtension:
+			for _, scheme := range e.SupportedSignatureAlgorithms {
+				if !isGREASE(uint16(scheme)) {
+					signatures = append(signatures, fmt.Sprintf("%04x", uint16(scheme)))
+				}
+			}
+		case *tls.QUICTransportParametersExtension:
+			protocol = "q"
+		}
+		extensions = append(extensions, fmt.Sprintf("%04x", id))
+	}
+
+	sni := "i"
+	if hasSNI {
+		sni = "d"
+	}
+
+	sort.Strings(ciphers)
+	sort.Strings(extensions)
+
+	prefix := fmt.Sprintf("%s%s%s%02d%02d%s",
+		protocol, ja4Version(spec), sni, min(len(ciphers), 99), min(extensionCount, 99), alpn)
+
+	return ja4Components{
+		prefix:     prefix,
+		ciphers:    strings.Join(ciphers, ","),
+		extensions: strings.Join(extensions, ","),
+		signatures: strings.Join(signatures, ","),
+	}, nil
+}
+
+// ja4Version 返回 JA4 中的 TLS 版本标识，优先使用 supported_versions 扩展中的最高版本
+func ja4Version(spec tls.ClientHelloSpec) string {
+	var version uint16
+	for _, ext := range spec.Extensions {
+		if e, ok := ext.(*tls.SupportedVersionsExtension); ok {
+			for _, v := range e.Versions {
+				if !isGREASE(v) && v > version {
+					version = v
+				}
+			}
+		}
+	}
+	if version == 0 {
+		version = clientHelloVersion(spec)
+	}
+
+	switch version {
+	case tls.VersionTLS13:
+		return "13"
+	case tls.VersionTLS12:
+		return "12"
+	case tls.VersionTLS11:
+		return "11"
+	case tls.VersionTLS10:
+		return "10"
+	case tls.VersionSSL30:
+		return "s3"
+	}
+	return "00"
+}
+
+// ja4ALPN 返回 ALPN 第一个协议的首尾字符，非字母数字时使用首字节高位与尾字节低位的十六进制
+func ja4ALPN(protocol string) string {
+	if protocol == "" {
+		return "00"
+	}
+	first, last := protocol[0], protocol[len(protocol)-1]
+	if isAlphanumeric(first) && isAlphanumeric(last) {
+		return string([]byte{first, last})
+	}
+	return hex.EncodeToString([]byte{first})[:1] + hex.EncodeToString([]byte{last})[1:]
+}
+
+// isAlphanumeric 判断是否为 ASCII 字母或数字
+func isAlphanumeric(b byte) bool {
+	return (b >= '0' && b <= '9') || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
+
+// ja4Hash 返回 SHA256 的前 12 位十六进制，空输入返回 12 个 0
+func ja4Hash(raw string) string {
+	if raw == "" {
+		return "000000000000"
+	}
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])[:12]
+}
@@ -0,0 +1,234 @@
+package profiles
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProfileDefinition 从文件加载的 profile 及其附带信息
+//
+// 文件格式（JSON 或 YAML，扩展名 .json/.yaml/.yml）：
+//
+//	name: chrome_140                 # 注册的 profile 名称，为空时使用文件名
+//	browser: chrome                  # 浏览器类型，覆盖 profile.metadata.browser，都为空时由 client_hello_id.client 推断
+//	mobile: false                    # 为 true 时 profile.metadata.device 设为 mobile
+//	user_agent: "Mozilla/5.0 (%s) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/140.0.0.0 Safari/537.36"
+//	headers:                         # 覆盖生成的 headers，名称与 HTTPHeaders 一致（如 Sec-CH-UA）
+//	  Accept-Encoding: gzip, deflate, br, zstd
+//	header_order: [user-agent, accept, accept-encoding]
+//	profile:                         # ClientProfile 的序列化格式，见 serialize.go
+//	  client_hello_id: {client: Chrome, version: "140"}
+//	  tls: {...}
+//	  http2: {...}
+//
+// user_agent 中的 %s 为操作系统占位符，不含 %s 时原样使用
+type ProfileDefinition struct {
+	Name        string            `json:"name" yaml:"name"`
+	Browser     string            `json:"browser,omitempty" yaml:"browser,omitempty"`
+	Mobile      bool              `json:"mobile,omitempty" yaml:"mobile,omitempty"`
+	UserAgent   string            `json:"user_agent,omitempty" yaml:"user_agent,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	HeaderOrder []string          `json:"header_order,omitempty" yaml:"header_order,omitempty"`
+	Profile     ClientProfile     `json:"profile" yaml:"profile"`
+
+	Source string `json:"-" yaml:"-"` // 定义所在的文件
+}
+
+// ErrProfileConflict 加载的 profile 名称与已有 profile 冲突
+type ErrProfileConflict struct {
+	Name     string
+	Source   string // 冲突的文件
+	Existing string // 已有 profile 的来源，内置 profile 为 "built-in"
+}
+
+func (e *ErrProfileConflict) Error() string {
+	return fmt.Sprintf("profile %q in %s conflicts with existing profile from %s", e.Name, e.Source, e.Existing)
+}
+
+// Definition 返回 DefaultRegistry 中通过 LoadDir/LoadFS 加载的 profile 定义，内置 profile 返回 false
+func Definition(name string) (ProfileDefinition, bool) {
+	return DefaultRegistry.Definition(name)
+}
+
+// LoadDir 加载目录中的 profile 文件并注册到 DefaultRegistry，见 Registry.LoadDir
+func LoadDir(dir string) ([]string, error) {
+	return DefaultRegistry.LoadDir(dir)
+}
+
+// LoadFS 加载 fs.FS 中的 profile 文件并注册到 DefaultRegistry，见 Registry.LoadFS
+func LoadFS(fsys fs.FS) ([]string, error) {
+	return DefaultRegistry.LoadFS(fsys)
+}
+
+// Unload 从 DefaultRegistry 移除通过 LoadDir/LoadFS 加载的 profile，见 Registry.Unload
+func Unload(names ...string) {
+	DefaultRegistry.Unload(names...)
+}
+
+// LoadDir 加载目录（包括子目录）中的所有 profile 文件并注册到注册表，返回加载的名称
+func (r *Registry) LoadDir(dir string) ([]string, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+	return r.loadFS(os.DirFS(dir), dir)
+}
+
+// LoadFS 加载 fs.FS 中的所有 profile 文件并注册到注册表，返回加载的名称
+//
+// 加载是原子的：任何文件解析失败或名称冲突（与已注册的 profile 或本次加载的其他文件）时，
+// 不会注册任何 profile，并返回包含所有问题的错误，冲突以 *ErrProfileConflict 报告
+func (r *Registry) LoadFS(fsys fs.FS) ([]string, error) {
+	return r.loadFS(fsys, "")
+}
+
+func (r *Registry) loadFS(fsys fs.FS, root string) ([]string, error) {
+	files := make([]string, 0)
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && isProfileFile(p) {
+			files = append(files, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	defs := make([]ProfileDefinition, 0, len(files))
+	var errs []error
+	for _, file := range files {
+		source := file
+		if root != "" {
+			source = filepath.Join(root, filepath.FromSlash(file))
+		}
+		def, err := readDefinition(fsys, file, source)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		defs = append(defs, def)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	seen := make(map[string]string, len(defs))
+	for _, def := range defs {
+		if existing, ok := seen[def.Name]; ok {
+			errs = append(errs, &ErrProfileConflict{Name: def.Name, Source: def.Source, Existing: existing})
+			continue
+		}
+		if _, ok := r.profiles[def.Name]; ok {
+			existing := "built-in"
+			if loaded := r.definitions[def.Name]; loaded != nil {
+				existing = loaded.Source
+			}
+			errs = append(errs, &ErrProfileConflict{Name: def.Name, Source: def.Source, Existing: existing})
+			continue
+		}
+		seen[def.Name] = def.Source
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	names := make([]string, 0, len(defs))
+	for i := range defs {
+		r.set(defs[i].Name, defs[i].Profile, &defs[i])
+		names = append(names, defs[i].Name)
+	}
+	return names, nil
+}
+
+// Unload 移除通过 LoadDir/LoadFS 加载的 profile，其他 profile 不受影响
+func (r *Registry) Unload(names ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, name := range names {
+		if r.definitions[name] != nil {
+			r.delete(name)
+		}
+	}
+}
+
+// isProfileFile 判断文件扩展名是否为支持的格式
+func isProfileFile(name string) bool {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".json", ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
+// readDefinition 读取并解析单个 profile 文件
+func readDefinition(fsys fs.FS, file, source string) (ProfileDefinition, error) {
+	data, err := fs.ReadFile(fsys, file)
+	if err != nil {
+		return ProfileDefinition{}, err
+	}
+
+	var def ProfileDefinition
+	if strings.EqualFold(path.Ext(file), ".json") {
+		err = json.Unmarshal(data, &def)
+	} else {
+		err = yaml.Unmarshal(data, &def)
+	}
+	if err != nil {
+		return ProfileDefinition{}, fmt.Errorf("%s: %w", source, err)
+	}
+	// 反序列化得到的 profile 总是带有 SpecFactory，没有说明文件中缺少 profile
+	if def.Profile.clientHelloId.SpecFactory == nil {
+		return ProfileDefinition{}, fmt.Errorf("%s: missing profile", source)
+	}
+
+	if def.Name == "" {
+		def.Name = strings.TrimSuffix(path.Base(file), path.Ext(file))
+	}
+	def.Browser = strings.ToLower(def.Browser)
+	def.Source = source
+	def.Profile.metadata = def.metadata()
+	def.Browser = string(def.Profile.metadata.Browser)
+	def.Mobile = def.Profile.metadata.Mobile()
+	return def, nil
+}
+
+// metadata 合并文件中的 browser、mobile 字段与 profile 自带的 metadata
+// profile 没有 metadata 且文件未指定 browser 时，由 client_hello_id.client 推断浏览器类型（应用 profile 除外）
+func (def ProfileDefinition) metadata() Metadata {
+	m := def.Profile.metadata
+	if def.Browser != "" {
+		m.Browser = BrowserFamily(def.Browser)
+	}
+	if m.Browser == "" && m.App == "" {
+		switch browser := BrowserFamily(strings.ToLower(def.Profile.clientHelloId.Client)); browser {
+		case BrowserChrome, BrowserFirefox, BrowserSafari, BrowserOpera, BrowserEdge:
+			m.Browser = browser
+		}
+	}
+	if m.Engine == "" {
+		m.Engine = engineFor(m.Browser)
+	}
+	if def.Mobile && !m.Mobile() {
+		m.Device = DeviceMobile
+	}
+	if m.Device == "" {
+		m.Device = DeviceDesktop
+	}
+	return m
+}
@@ -0,0 +1,162 @@
+package profiles
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BrowserFamily 浏览器类型
+type BrowserFamily string
+
+const (
+	BrowserChrome  BrowserFamily = "chrome"
+	BrowserFirefox BrowserFamily = "firefox"
+	BrowserSafari  BrowserFamily = "safari"
+	BrowserOpera   BrowserFamily = "opera"
+	BrowserEdge    BrowserFamily = "edge"
+)
+
+// Engine 浏览器引擎
+type Engine string
+
+const (
+	EngineBlink  Engine = "blink"
+	EngineGecko  Engine = "gecko"
+	EngineWebKit Engine = "webkit"
+)
+
+// Platform 操作系统平台，为空表示不限定（桌面浏览器 profile 可以搭配任意桌面操作系统）
+type Platform string
+
+const (
+	PlatformWindows Platform = "windows"
+	PlatformMacOS   Platform = "macos"
+	PlatformLinux   Platform = "linux"
+	PlatformIOS     Platform = "ios"
+	PlatformIPadOS  Platform = "ipados"
+	PlatformAndroid Platform = "android"
+)
+
+// DeviceClass 设备类型
+type DeviceClass string
+
+const (
+	DeviceDesktop DeviceClass = "desktop"
+	DeviceMobile  DeviceClass = "mobile"
+	DeviceTablet  DeviceClass = "tablet"
+)
+
+// Features TLS 特性集合
+type Features uint8
+
+const (
+	FeaturePSK Features = 1 << iota // 发送 pre_shared_key 扩展（会话恢复）
+	FeaturePQ                       // key_share 包含后量子混合密钥交换（X25519Kyber768/X25519MLKEM768）
+	FeatureECH                      // 发送 encrypted_client_hello 扩展（包括 GREASE ECH）
+)
+
+// featureNames 特性名称，用于序列化
+var featureNames = []struct {
+	feature Features
+	name    string
+}{
+	{FeaturePSK, "psk"},
+	{FeaturePQ, "pq"},
+	{FeatureECH, "ech"},
+}
+
+// Has 判断是否包含 f 中的所有特性
+func (f Features) Has(features Features) bool {
+	return f&features == features
+}
+
+// Names 返回特性名称列表
+func (f Features) Names() []string {
+	names := make([]string, 0, len(featureNames))
+	for _, fn := range featureNames {
+		if f.Has(fn.feature) {
+			names = append(names, fn.name)
+		}
+	}
+	return names
+}
+
+// String 返回以逗号分隔的特性名称
+func (f Features) String() string {
+	return strings.Join(f.Names(), ",")
+}
+
+// ParseFeature 解析特性名称（psk、pq、ech，不区分大小写）
+func ParseFeature(name string) (Features, bool) {
+	for _, fn := range featureNames {
+		if strings.EqualFold(fn.name, name) {
+			return fn.feature, true
+		}
+	}
+	return 0, false
+}
+
+// Metadata profile 的结构化描述
+// 选择 profile、生成 User-Agent 和 headers 时使用这些信息，而不是解析 profile 名称
+type Metadata struct {
+	Browser      BrowserFamily // User-Agent 和 headers 所模拟的浏览器，应用 profile 为空
+	Engine       Engine
+	MajorVersion int // 浏览器主版本号
+	MinorVersion int // 浏览器次版本号（Safari 等使用，Chrome/Firefox 为 0）
+	Platform     Platform
+	Device       DeviceClass
+	ReleaseDate  time.Time // 浏览器版本的发布日期，未知时为零值
+	Features     Features
+
+	// App 非浏览器客户端（应用或 HTTP 库）的名称，浏览器 profile 为空
+	// 应用 profile 的 Browser、Engine 和版本为空：其 ClientHello 来自应用的网络库（如 OkHttp、CFNetwork），
+	// 生成 headers 时不使用浏览器的 header 表和 client hints
+	App string
+}
+
+// Mobile 是否为移动设备（手机或平板）
+func (m Metadata) Mobile() bool {
+	return m.Device == DeviceMobile || m.Device == DeviceTablet
+}
+
+// Version 返回版本号字符串，次版本号为 0 时只包含主版本号（如 "133"），Safari 总是包含次版本号（如 "17.0"）
+func (m Metadata) Version() string {
+	if m.MajorVersion == 0 {
+		return ""
+	}
+	version := strconv.Itoa(m.MajorVersion)
+	if m.MinorVersion != 0 || m.Browser == BrowserSafari {
+		version += "." + strconv.Itoa(m.MinorVersion)
+	}
+	return version
+}
+
+// Metadata 返回 profile 的结构化描述，没有设置时返回零值
+func (c ClientProfile) Metadata() Metadata {
+	return c.metadata
+}
+
+// WithMetadata 返回设置了 metadata 的 profile 副本
+func (c ClientProfile) WithMetadata(metadata Metadata) ClientProfile {
+	c.metadata = metadata
+	return c
+}
+
+// engineFor 返回浏览器对应的引擎
+func engineFor(browser BrowserFamily) Engine {
+	switch browser {
+	case BrowserChrome, BrowserOpera, BrowserEdge:
+		return EngineBlink
+	case BrowserFirefox:
+		return EngineGecko
+	case BrowserSafari:
+		return EngineWebKit
+	}
+	return ""
+}
+
+// releaseDate 返回 UTC 日期
+func releaseDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
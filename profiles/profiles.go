@@ -7,6 +7,9 @@ import (
 
 var DefaultClientProfile = Chrome_133
 
+// MappedTLSClients 所有 profile 的映射表，是 DefaultRegistry 的存储：
+// 通过 DefaultRegistry 注册、加载的 profile 会出现在该表中，直接写入该表的 profile 也可以被随机选择和生成 User-Agent
+// 该表本身不是并发安全的，并发程序应通过 DefaultRegistry（Register、Unregister、LoadDir、LoadFS、Snapshot）读写
 var MappedTLSClients = map[string]ClientProfile{
 	"chrome_103":             Chrome_103,
 	"chrome_104":             Chrome_104,
@@ -52,6 +55,9 @@ var MappedTLSClients = map[string]ClientProfile{
 	"opera_89":               Opera_89,
 	"opera_90":               Opera_90,
 	"opera_91":               Opera_91,
+	"edge_131":               Edge_131,
+	"edge_133":               Edge_133,
+	"edge_android_131":       Edge_Android_131,
 	// 移动端和自定义指纹
 	"zalando_android_mobile": ZalandoAndroidMobile,
 	"zalando_ios_mobile":     ZalandoIosMobile,
@@ -85,6 +91,7 @@ type ClientProfile struct {
 	pseudoHeaderOrder []string
 	settingsOrder     []http2.SettingID
 	connectionFlow    uint32
+	metadata          Metadata
 }
 
 func NewClientProfile(clientHelloId tls.ClientHelloID, settings map[http2.SettingID]uint32, settingsOrder []http2.SettingID, pseudoHeaderOrder []string, connectionFlow uint32, priorities []http2.Priority, headerPriority *http2.PriorityParam) ClientProfile {
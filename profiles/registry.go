@@ -0,0 +1,177 @@
+package profiles
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// DefaultRegistry 包含所有内置 profile 的默认注册表
+// 包级函数（LoadDir、LoadFS、Definition 以及主包的随机指纹、User-Agent 函数）都使用该注册表
+// DefaultRegistry 直接使用 MappedTLSClients 存储 profile，两者始终一致（见 MappedTLSClients）
+var DefaultRegistry = newDefaultRegistry()
+
+// ErrProfileExists 注册的 profile 名称已存在
+type ErrProfileExists struct {
+	Name string
+}
+
+func (e *ErrProfileExists) Error() string {
+	return "profile already registered: " + e.Name
+}
+
+// Registry 并发安全的 profile 注册表
+// 可以通过 NewRegistry 或 DefaultRegistry.Clone 创建独立的注册表，避免在测试中修改包级状态
+type Registry struct {
+	mu       sync.RWMutex
+	profiles map[string]ClientProfile
+
+	// definitions 通过 LoadDir/LoadFS 加载的 profile 的定义
+	definitions map[string]*ProfileDefinition
+}
+
+// NewRegistry 创建空的注册表
+func NewRegistry() *Registry {
+	return &Registry{profiles: make(map[string]ClientProfile), definitions: make(map[string]*ProfileDefinition)}
+}
+
+// newDefaultRegistry 创建以 MappedTLSClients 为存储的注册表
+// 注册表的修改直接写入 MappedTLSClients，直接写入 MappedTLSClients 的 profile 也会被注册表看到
+func newDefaultRegistry() *Registry {
+	return &Registry{profiles: MappedTLSClients, definitions: make(map[string]*ProfileDefinition)}
+}
+
+// Register 注册 profile，名称为空或已存在时返回错误
+func (r *Registry) Register(name string, profile ClientProfile) error {
+	if name == "" {
+		return fmt.Errorf("profile name cannot be empty")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.profiles[name]; ok {
+		return &ErrProfileExists{Name: name}
+	}
+	r.set(name, profile, nil)
+	return nil
+}
+
+// Unregister 移除 profile，返回 profile 是否存在
+func (r *Registry) Unregister(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.profiles[name]; !ok {
+		return false
+	}
+	r.delete(name)
+	return true
+}
+
+// Get 根据名称获取 profile
+func (r *Registry) Get(name string) (ClientProfile, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	profile, ok := r.profiles[name]
+	return profile, ok
+}
+
+// Definition 返回通过 LoadDir/LoadFS 加载的 profile 定义，其他 profile 返回 false
+func (r *Registry) Definition(name string) (ProfileDefinition, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	def := r.definitions[name]
+	if _, ok := r.profiles[name]; !ok || def == nil {
+		return ProfileDefinition{}, false
+	}
+	return *def, true
+}
+
+// Len 返回 profile 数量
+func (r *Registry) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.profiles)
+}
+
+// List 返回所有 profile 名称（按名称排序）
+func (r *Registry) List() []string {
+	r.mu.RLock()
+	names := make([]string, 0, len(r.profiles))
+	for name := range r.profiles {
+		names = append(names, name)
+	}
+	r.mu.RUnlock()
+
+	sort.Strings(names)
+	return names
+}
+
+// Filter 返回满足 match 的 profile 快照
+// match 在快照上执行，可以安全地调用注册表的其他方法
+func (r *Registry) Filter(match func(name string, profile ClientProfile) bool) map[string]ClientProfile {
+	result := make(map[string]ClientProfile)
+	for name, profile := range r.Snapshot() {
+		if match(name, profile) {
+			result[name] = profile
+		}
+	}
+	return result
+}
+
+// Snapshot 返回所有 profile 的副本，修改返回的 map 不会影响注册表
+func (r *Registry) Snapshot() map[string]ClientProfile {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	snapshot := make(map[string]ClientProfile, len(r.profiles))
+	for name, profile := range r.profiles {
+		snapshot[name] = profile
+	}
+	return snapshot
+}
+
+// Range 按名称顺序遍历 profile 快照，fn 返回 false 时停止
+// fn 在快照上执行，可以安全地调用注册表的其他方法
+func (r *Registry) Range(fn func(name string, profile ClientProfile) bool) {
+	snapshot := r.Snapshot()
+	names := make([]string, 0, len(snapshot))
+	for name := range snapshot {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if !fn(name, snapshot[name]) {
+			return
+		}
+	}
+}
+
+// Clone 返回包含相同 profile 的独立注册表，对副本的修改不会影响原注册表
+func (r *Registry) Clone() *Registry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	clone := NewRegistry()
+	for name, profile := range r.profiles {
+		clone.profiles[name] = profile
+	}
+	for name, def := range r.definitions {
+		clone.definitions[name] = def
+	}
+	return clone
+}
+
+// set 写入一项，definition 仅对通过 LoadDir/LoadFS 加载的 profile 非空，调用方需持有写锁
+func (r *Registry) set(name string, profile ClientProfile, definition *ProfileDefinition) {
+	r.profiles[name] = profile
+	if definition != nil {
+		r.definitions[name] = definition
+	} else {
+		delete(r.definitions, name)
+	}
+}
+
+// delete 删除一项，调用方需持有写锁
+func (r *Registry) delete(name string) {
+	delete(r.profiles, name)
+	delete(r.definitions, name)
+}
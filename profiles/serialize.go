@@ -0,0 +1,732 @@
+package profiles
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bogdanfinn/fhttp/http2"
+	tls "github.com/bogdanfinn/utls"
+	"gopkg.in/yaml.v3"
+)
+
+// ClientProfile 的 JSON/YAML 格式（JSON 与 YAML 使用相同的字段名）：
+//
+//	{
+//	  "client_hello_id": {"client": "Chrome", "version": "133", "random_extension_order": false},
+//	  "metadata": {                             // 可选，见 Metadata
+//	    "browser": "chrome", "engine": "blink", "major_version": 133, "device": "desktop",
+//	    "release_date": "2025-02-04", "features": ["pq", "ech"]
+//	  },
+//	  "tls": {
+//	    "tls_version_min": "0x0303",            // 可选，0 表示由 supported_versions 扩展决定
+//	    "tls_version_max": "0x0304",
+//	    "cipher_suites": ["GREASE", "0x1301", ...],
+//	    "compression_methods": [0],
+//	    "extensions": [
+//	      {"name": "grease"},
+//	      {"name": "supported_groups", "curves": ["GREASE", "0x11ec", "0x001d"]},
+//	      {"name": "key_share", "key_shares": [{"group": "GREASE", "data": "00"}, {"group": "0x001d"}]},
+//	      {"name": "generic", "id": "0x0016", "data": ""},
+//	      ...
+//	    ]
+//	  },
+//	  "http2": {
+//	    "settings": [{"id": 1, "value": 65536}, ...],  // 按发送顺序排列
+//	    "connection_flow": 15663105,
+//	    "priorities": [{"stream_id": 3, "exclusive": false, "stream_dep": 0, "weight": 200}],
+//	    "header_priority": {"exclusive": true, "stream_dep": 0, "weight": 255},
+//	    "pseudo_header_order": [":method", ":authority", ":scheme", ":path"]
+//	  }
+//	}
+//
+// 约定：
+//   - 密码套件、曲线、签名算法、TLS 版本、扩展编号等 16 位值使用 "0x" 开头的十六进制字符串，
+//     GREASE 占位符（tls.GREASE_PLACEHOLDER）写作 "GREASE"，每次握手时替换为随机 GREASE 值
+//   - 字节数据（key_share 数据、generic 扩展内容等）使用十六进制字符串
+//   - priority 的 weight 与 http2.PriorityParam.Weight 相同，即帧中的实际权重减 1
+//   - 扩展的 name 与 tls.TLSExtension 类型一一对应，参数字段见 extensionJSON；
+//     没有对应 name 的扩展类型以 generic 形式保存其序列化后的内容
+//   - ClientHelloID 的 Seed 不会被保存；反序列化得到的 profile 总是使用 SpecFactory
+
+// MarshalJSON 将 profile 序列化为 JSON，预定义 ClientHelloID 会展开为完整的 ClientHelloSpec
+func (c ClientProfile) MarshalJSON() ([]byte, error) {
+	p, err := c.toJSON()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(p)
+}
+
+// UnmarshalJSON 从 JSON 反序列化 profile
+func (c *ClientProfile) UnmarshalJSON(data []byte) error {
+	var p profileJSON
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+	return c.fromJSON(p)
+}
+
+// MarshalYAML 将 profile 序列化为 YAML，格式与 JSON 相同
+func (c ClientProfile) MarshalYAML() (interface{}, error) {
+	return c.toJSON()
+}
+
+// UnmarshalYAML 从 YAML 反序列化 profile
+func (c *ClientProfile) UnmarshalYAML(value *yaml.Node) error {
+	var p profileJSON
+	if err := value.Decode(&p); err != nil {
+		return err
+	}
+	return c.fromJSON(p)
+}
+
+// profileJSON ClientProfile 的序列化格式
+type profileJSON struct {
+	ClientHelloID clientHelloIDJSON `json:"client_hello_id" yaml:"client_hello_id"`
+	Metadata      *metadataJSON     `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	TLS           clientHelloJSON   `json:"tls" yaml:"tls"`
+	HTTP2         http2JSON         `json:"http2" yaml:"http2"`
+}
+
+type metadataJSON struct {
+	Browser      string   `json:"browser,omitempty" yaml:"browser,omitempty"`
+	Engine       string   `json:"engine,omitempty" yaml:"engine,omitempty"`
+	MajorVersion int      `json:"major_version,omitempty" yaml:"major_version,omitempty"`
+	MinorVersion int      `json:"minor_version,omitempty" yaml:"minor_version,omitempty"`
+	Platform     string   `json:"platform,omitempty" yaml:"platform,omitempty"`
+	Device       string   `json:"device,omitempty" yaml:"device,omitempty"`
+	ReleaseDate  string   `json:"release_date,omitempty" yaml:"release_date,omitempty"`
+	Features     []string `json:"features,omitempty" yaml:"features,omitempty"`
+	App          string   `json:"app,omitempty" yaml:"app,omitempty"`
+}
+
+type clientHelloIDJSON struct {
+	Client               string `json:"client" yaml:"client"`
+	Version              string `json:"version" yaml:"version"`
+	RandomExtensionOrder bool   `json:"random_extension_order,omitempty" yaml:"random_extension_order,omitempty"`
+}
+
+type clientHelloJSON struct {
+	TLSVersMin         hexUint16       `json:"tls_version_min,omitempty" yaml:"tls_version_min,omitempty"`
+	TLSVersMax         hexUint16       `json:"tls_version_max,omitempty" yaml:"tls_version_max,omitempty"`
+	CipherSuites       []hexUint16     `json:"cipher_suites" yaml:"cipher_suites"`
+	CompressionMethods []uint16        `json:"compression_methods" yaml:"compression_methods"`
+	Extensions         []extensionJSON `json:"extensions" yaml:"extensions"`
+}
+
+// extensionJSON TLS 扩展的序列化格式，Name 决定扩展类型，其余字段按类型使用
+type extensionJSON struct {
+	Name string `json:"name" yaml:"name"`
+
+	// generic：扩展编号和内容；grease：非 0 时为固定的 GREASE 值和扩展内容
+	ID   hexUint16 `json:"id,omitempty" yaml:"id,omitempty"`
+	Data hexBytes  `json:"data,omitempty" yaml:"data,omitempty"`
+
+	// server_name
+	ServerName string `json:"server_name,omitempty" yaml:"server_name,omitempty"`
+	// supported_groups
+	Curves []hexUint16 `json:"curves,omitempty" yaml:"curves,omitempty"`
+	// ec_point_formats、psk_key_exchange_modes、token_binding 的 key_parameters
+	Values []uint16 `json:"values,omitempty" yaml:"values,omitempty"`
+	// signature_algorithms、signature_algorithms_cert、delegated_credentials、compress_certificate
+	Algorithms []hexUint16 `json:"algorithms,omitempty" yaml:"algorithms,omitempty"`
+	// supported_versions
+	Versions []hexUint16 `json:"versions,omitempty" yaml:"versions,omitempty"`
+	// alpn、application_settings、application_settings_new、next_protocol_negotiation
+	Protocols []string `json:"protocols,omitempty" yaml:"protocols,omitempty"`
+	// key_share
+	KeyShares []keyShareJSON `json:"key_shares,omitempty" yaml:"key_shares,omitempty"`
+	// padding：style 为 "boring" 时使用 tls.BoringPaddingStyle
+	PaddingStyle string `json:"padding_style,omitempty" yaml:"padding_style,omitempty"`
+	PaddingLen   int    `json:"padding_len,omitempty" yaml:"padding_len,omitempty"`
+	WillPad      bool   `json:"will_pad,omitempty" yaml:"will_pad,omitempty"`
+	// record_size_limit
+	Limit uint16 `json:"limit,omitempty" yaml:"limit,omitempty"`
+	// token_binding
+	MajorVersion uint8 `json:"major_version,omitempty" yaml:"major_version,omitempty"`
+	MinorVersion uint8 `json:"minor_version,omitempty" yaml:"minor_version,omitempty"`
+	// renegotiation_info
+	Renegotiation int `json:"renegotiation,omitempty" yaml:"renegotiation,omitempty"`
+	// encrypted_client_hello（GREASE ECH）
+	ECHCipherSuites []echCipherSuiteJSON `json:"ech_cipher_suites,omitempty" yaml:"ech_cipher_suites,omitempty"`
+	ECHConfigIDs    []uint16             `json:"ech_config_ids,omitempty" yaml:"ech_config_ids,omitempty"`
+	ECHPayloadLens  []uint16             `json:"ech_payload_lens,omitempty" yaml:"ech_payload_lens,omitempty"`
+	// fake_pre_shared_key
+	Identities []pskIdentityJSON `json:"identities,omitempty" yaml:"identities,omitempty"`
+	Binders    []hexBytes        `json:"binders,omitempty" yaml:"binders,omitempty"`
+}
+
+type keyShareJSON struct {
+	Group hexUint16 `json:"group" yaml:"group"`
+	Data  hexBytes  `json:"data,omitempty" yaml:"data,omitempty"`
+}
+
+type echCipherSuiteJSON struct {
+	KDF  hexUint16 `json:"kdf" yaml:"kdf"`
+	AEAD hexUint16 `json:"aead" yaml:"aead"`
+}
+
+type pskIdentityJSON struct {
+	Identity            hexBytes `json:"identity" yaml:"identity"`
+	ObfuscatedTicketAge uint32   `json:"obfuscated_ticket_age" yaml:"obfuscated_ticket_age"`
+}
+
+type http2JSON struct {
+	Settings          []settingJSON      `json:"settings" yaml:"settings"`
+	ConnectionFlow    uint32             `json:"connection_flow" yaml:"connection_flow"`
+	Priorities        []priorityJSON     `json:"priorities,omitempty" yaml:"priorities,omitempty"`
+	HeaderPriority    *priorityParamJSON `json:"header_priority,omitempty" yaml:"header_priority,omitempty"`
+	PseudoHeaderOrder []string           `json:"pseudo_header_order" yaml:"pseudo_header_order"`
+}
+
+type settingJSON struct {
+	ID    uint16 `json:"id" yaml:"id"`
+	Value uint32 `json:"value" yaml:"value"`
+}
+
+type priorityJSON struct {
+	StreamID  uint32 `json:"stream_id" yaml:"stream_id"`
+	Exclusive bool   `json:"exclusive" yaml:"exclusive"`
+	StreamDep uint32 `json:"stream_dep" yaml:"stream_dep"`
+	Weight    uint8  `json:"weight" yaml:"weight"`
+}
+
+type priorityParamJSON struct {
+	Exclusive bool   `json:"exclusive" yaml:"exclusive"`
+	StreamDep uint32 `json:"stream_dep" yaml:"stream_dep"`
+	Weight    uint8  `json:"weight" yaml:"weight"`
+}
+
+// hexUint16 以十六进制字符串序列化的 16 位值，GREASE 占位符写作 "GREASE"
+type hexUint16 uint16
+
+func (v hexUint16) MarshalText() ([]byte, error) {
+	if uint16(v) == tls.GREASE_PLACEHOLDER {
+		return []byte("GREASE"), nil
+	}
+	return []byte(fmt.Sprintf("0x%04x", uint16(v))), nil
+}
+
+func (v *hexUint16) UnmarshalText(text []byte) error {
+	s := strings.TrimSpace(string(text))
+	if strings.EqualFold(s, "GREASE") {
+		*v = hexUint16(tls.GREASE_PLACEHOLDER)
+		return nil
+	}
+	n, err := strconv.ParseUint(s, 0, 16)
+	if err != nil {
+		return fmt.Errorf("invalid 16-bit value %q", s)
+	}
+	*v = hexUint16(n)
+	return nil
+}
+
+// hexBytes 以十六进制字符串序列化的字节数据
+type hexBytes []byte
+
+func (b hexBytes) MarshalText() ([]byte, error) {
+	return []byte(hex.EncodeToString(b)), nil
+}
+
+func (b *hexBytes) UnmarshalText(text []byte) error {
+	decoded, err := hex.DecodeString(strings.TrimSpace(string(text)))
+	if err != nil {
+		return fmt.Errorf("invalid hex data %q", text)
+	}
+	*b = decoded
+	return nil
+}
+
+// toJSON 将 profile 转换为序列化格式
+func (c ClientProfile) toJSON() (profileJSON, error) {
+	spec, err := c.resolveClientHelloSpec()
+	if err != nil {
+		return profileJSON{}, err
+	}
+
+	hello := clientHelloJSON{
+		TLSVersMin:         hexUint16(spec.TLSVersMin),
+		TLSVersMax:         hexUint16(spec.TLSVersMax),
+		CipherSuites:       toHexUint16s(spec.CipherSuites),
+		CompressionMethods: make([]uint16, 0, len(spec.CompressionMethods)),
+		Extensions:         make([]extensionJSON, 0, len(spec.Extensions)),
+	}
+	for _, method := range spec.CompressionMethods {
+		hello.CompressionMethods = append(hello.CompressionMethods, uint16(method))
+	}
+	for _, ext := range spec.Extensions {
+		e, err := marshalExtension(ext)
+		if err != nil {
+			return profileJSON{}, err
+		}
+		hello.Extensions = append(hello.Extensions, e)
+	}
+
+	h2 := http2JSON{
+		Settings:          make([]settingJSON, 0, len(c.settingsOrder)),
+		ConnectionFlow:    c.connectionFlow,
+		PseudoHeaderOrder: append([]string{}, c.pseudoHeaderOrder...),
+	}
+	for _, id := range c.settingsOrder {
+		h2.Settings = append(h2.Settings, settingJSON{ID: uint16(id), Value: c.settings[id]})
+	}
+	for _, p := range c.priorities {
+		h2.Priorities = append(h2.Priorities, priorityJSON{
+			StreamID:  p.StreamID,
+			Exclusive: p.PriorityParam.Exclusive,
+			StreamDep: p.PriorityParam.StreamDep,
+			Weight:    p.PriorityParam.Weight,
+		})
+	}
+	if c.headerPriority != nil {
+		param := toPriorityParamJSON(*c.headerPriority)
+		h2.HeaderPriority = &param
+	}
+
+	return profileJSON{
+		ClientHelloID: clientHelloIDJSON{
+			Client:               c.clientHelloId.Client,
+			Version:              c.clientHelloId.Version,
+			RandomExtensionOrder: c.clientHelloId.RandomExtensionOrder,
+		},
+		Metadata: toMetadataJSON(c.metadata),
+		TLS:      hello,
+		HTTP2:    h2,
+	}, nil
+}
+
+// fromJSON 根据序列化格式构造 profile，ClientHelloSpec 会先校验一次
+func (c *ClientProfile) fromJSON(p profileJSON) error {
+	if _, err := p.TLS.spec(); err != nil {
+		return err
+	}
+	metadata, err := p.Metadata.metadata()
+	if err != nil {
+		return err
+	}
+
+	var settings map[http2.SettingID]uint32
+	var settingsOrder []http2.SettingID
+	for _, s := range p.HTTP2.Settings {
+		if settings == nil {
+			settings = make(map[http2.SettingID]uint32, len(p.HTTP2.Settings))
+		}
+		if _, exists := settings[http2.SettingID(s.ID)]; exists {
+			return fmt.Errorf("duplicate HTTP/2 setting %d", s.ID)
+		}
+		settings[http2.SettingID(s.ID)] = s.Value
+		settingsOrder = append(settingsOrder, http2.SettingID(s.ID))
+	}
+
+	var priorities []http2.Priority
+	for _, priority := range p.HTTP2.Priorities {
+		priorities = append(priorities, http2.Priority{
+			StreamID:      priority.StreamID,
+			PriorityParam: http2.PriorityParam{Exclusive: priority.Exclusive, StreamDep: priority.StreamDep, Weight: priority.Weight},
+		})
+	}
+
+	var headerPriority *http2.PriorityParam
+	if p.HTTP2.HeaderPriority != nil {
+		param := p.HTTP2.HeaderPriority.priorityParam()
+		headerPriority = &param
+	}
+
+	hello := p.TLS
+	*c = NewClientProfile(
+		tls.ClientHelloID{
+			Client:               p.ClientHelloID.Client,
+			Version:              p.ClientHelloID.Version,
+			RandomExtensionOrder: p.ClientHelloID.RandomExtensionOrder,
+			// 扩展是带状态的指针，每次都重新构造
+			SpecFactory: func() (tls.ClientHelloSpec, error) {
+				return hello.spec()
+			},
+		},
+		settings,
+		settingsOrder,
+		p.HTTP2.PseudoHeaderOrder,
+		p.HTTP2.ConnectionFlow,
+		priorities,
+		headerPriority,
+	).WithMetadata(metadata)
+	return nil
+}
+
+// releaseDateLayout metadata 中发布日期的格式
+const releaseDateLayout = "2006-01-02"
+
+// toMetadataJSON 转换为序列化格式，零值返回 nil
+func toMetadataJSON(m Metadata) *metadataJSON {
+	if m == (Metadata{}) {
+		return nil
+	}
+	p := &metadataJSON{
+		Browser:      string(m.Browser),
+		Engine:       string(m.Engine),
+		MajorVersion: m.MajorVersion,
+		MinorVersion: m.MinorVersion,
+		Platform:     string(m.Platform),
+		Device:       string(m.Device),
+		App:          m.App,
+	}
+	if !m.ReleaseDate.IsZero() {
+		p.ReleaseDate = m.ReleaseDate.Format(releaseDateLayout)
+	}
+	if m.Features != 0 {
+		p.Features = m.Features.Names()
+	}
+	return p
+}
+
+// metadata 根据序列化格式构造 Metadata，nil 返回零值
+func (p *metadataJSON) metadata() (Metadata, error) {
+	if p == nil {
+		return Metadata{}, nil
+	}
+	m := Metadata{
+		Browser:      BrowserFamily(strings.ToLower(p.Browser)),
+		Engine:       Engine(strings.ToLower(p.Engine)),
+		MajorVersion: p.MajorVersion,
+		MinorVersion: p.MinorVersion,
+		Platform:     Platform(strings.ToLower(p.Platform)),
+		Device:       DeviceClass(strings.ToLower(p.Device)),
+		App:          p.App,
+	}
+	switch m.Device {
+	case "", DeviceDesktop, DeviceMobile, DeviceTablet:
+	default:
+		return Metadata{}, fmt.Errorf("unknown device class %q", p.Device)
+	}
+	if m.Engine == "" {
+		m.Engine = engineFor(m.Browser)
+	}
+	if p.ReleaseDate != "" {
+		date, err := time.Parse(releaseDateLayout, p.ReleaseDate)
+		if err != nil {
+			return Metadata{}, fmt.Errorf("invalid release date %q: %w", p.ReleaseDate, err)
+		}
+		m.ReleaseDate = date
+	}
+	for _, name := range p.Features {
+		feature, ok := ParseFeature(name)
+		if !ok {
+			return Metadata{}, fmt.Errorf("unknown feature %q", name)
+		}
+		m.Features |= feature
+	}
+	return m, nil
+}
+
+// spec 根据序列化格式构造新的 ClientHelloSpec
+func (h clientHelloJSON) spec() (tls.ClientHelloSpec, error) {
+	spec := tls.ClientHelloSpec{
+		TLSVersMin:         uint16(h.TLSVersMin),
+		TLSVersMax:         uint16(h.TLSVersMax),
+		CipherSuites:       fromHexUint16s(h.CipherSuites),
+		CompressionMethods: make([]uint8, 0, len(h.CompressionMethods)),
+		Extensions:         make([]tls.TLSExtension, 0, len(h.Extensions)),
+	}
+	for _, method := range h.CompressionMethods {
+		if method > 0xff {
+			return tls.ClientHelloSpec{}, fmt.Errorf("invalid compression method %d", method)
+		}
+		spec.CompressionMethods = append(spec.CompressionMethods, uint8(method))
+	}
+	for _, e := range h.Extensions {
+		ext, err := e.extension()
+		if err != nil {
+			return tls.ClientHelloSpec{}, err
+		}
+		spec.Extensions = append(spec.Extensions, ext)
+	}
+	return spec, nil
+}
+
+// marshalExtension 将 TLS 扩展转换为序列化格式
+func marshalExtension(ext tls.TLSExtension) (extensionJSON, error) {
+	switch e := ext.(type) {
+	case *tls.UtlsGREASEExtension:
+		return extensionJSON{Name: "grease", ID: hexUint16(e.Value), Data: e.Body}, nil
+	case *tls.SNIExtension:
+		return extensionJSON{Name: "server_name", ServerName: e.ServerName}, nil
+	case *tls.StatusRequestExtension:
+		return extensionJSON{Name: "status_request"}, nil
+	case *tls.SupportedCurvesExtension:
+		curves := make([]hexUint16, 0, len(e.Curves))
+		for _, curve := range e.Curves {
+			curves = append(curves, hexUint16(curve))
+		}
+		return extensionJSON{Name: "supported_groups", Curves: curves}, nil
+	case *tls.SupportedPointsExtension:
+		return extensionJSON{Name: "ec_point_formats", Values: toUint16s(e.SupportedPoints)}, nil
+	case *tls.SignatureAlgorithmsExtension:
+		return extensionJSON{Name: "signature_algorithms", Algorithms: schemesToHex(e.SupportedSignatureAlgorithms)}, nil
+	case *tls.ALPNExtension:
+		return extensionJSON{Name: "alpn", Protocols: e.AlpnProtocols}, nil
+	case *tls.StatusRequestV2Extension:
+		return extensionJSON{Name: "status_request_v2"}, nil
+	case *tls.SCTExtension:
+		return extensionJSON{Name: "signed_certificate_timestamp"}, nil
+	case *tls.UtlsPaddingExtension:
+		e2 := extensionJSON{Name: "padding", PaddingLen: e.PaddingLen, WillPad: e.WillPad}
+		if e.GetPaddingLen != nil {
+			e2.PaddingStyle = "boring"
+		}
+		return e2, nil
+	case *tls.ExtendedMasterSecretExtension:
+		return extensionJSON{Name: "extended_master_secret"}, nil
+	case *tls.FakeTokenBindingExtension:
+		return extensionJSON{Name: "token_binding", MajorVersion: e.MajorVersion, MinorVersion: e.MinorVersion, Values: toUint16s(e.KeyParameters)}, nil
+	case *tls.UtlsCompressCertExtension:
+		algorithms := make([]hexUint16, 0, len(e.Algorithms))
+		for _, algorithm := range e.Algorithms {
+			algorithms = append(algorithms, hexUint16(algorithm))
+		}
+		return extensionJSON{Name: "compress_certificate", Algorithms: algorithms}, nil
+	case *tls.FakeRecordSizeLimitExtension:
+		return extensionJSON{Name: "record_size_limit", Limit: e.Limit}, nil
+	case *tls.FakeDelegatedCredentialsExtension:
+		return extensionJSON{Name: "delegated_credentials", Algorithms: schemesToHex(e.SupportedSignatureAlgorithms)}, nil
+	case *tls.SessionTicketExtension:
+		return extensionJSON{Name: "session_ticket"}, nil
+	case *tls.UtlsPreSharedKeyExtension:
+		return extensionJSON{Name: "pre_shared_key"}, nil
+	case *tls.FakePreSharedKeyExtension:
+		e2 := extensionJSON{Name: "fake_pre_shared_key"}
+		for _, identity := range e.Identities {
+			e2.Identities = append(e2.Identities, pskIdentityJSON{Identity: identity.Label, ObfuscatedTicketAge: identity.ObfuscatedTicketAge})
+		}
+		for _, binder := range e.Binders {
+			e2.Binders = append(e2.Binders, binder)
+		}
+		return e2, nil
+	case *tls.SupportedVersionsExtension:
+		return extensionJSON{Name: "supported_versions", Versions: toHexUint16s(e.Versions)}, nil
+	case *tls.CookieExtension:
+		return extensionJSON{Name: "cookie", Data: e.Cookie}, nil
+	case *tls.PSKKeyExchangeModesExtension:
+		return extensionJSON{Name: "psk_key_exchange_modes", Values: toUint16s(e.Modes)}, nil
+	case *tls.SignatureAlgorithmsCertExtension:
+		return extensionJSON{Name: "signature_algorithms_cert", Algorithms: schemesToHex(e.SupportedSignatureAlgorithms)}, nil
+	case *tls.KeyShareExtension:
+		shares := make([]keyShareJSON, 0, len(e.KeyShares))
+		for _, share := range e.KeyShares {
+			shares = append(shares, keyShareJSON{Group: hexUint16(share.Group), Data: share.Data})
+		}
+		return extensionJSON{Name: "key_share", KeyShares: shares}, nil
+	case *tls.NPNExtension:
+		return extensionJSON{Name: "next_protocol_negotiation", Protocols: e.NextProtos}, nil
+	case *tls.ApplicationSettingsExtension:
+		return extensionJSON{Name: "application_settings", Protocols: e.SupportedProtocols}, nil
+	case *tls.ApplicationSettingsExtensionNew:
+		return extensionJSON{Name: "application_settings_new", Protocols: e.SupportedProtocols}, nil
+	case *tls.FakeChannelIDExtension:
+		if e.OldExtensionID {
+			return extensionJSON{Name: "channel_id_old"}, nil
+		}
+		return extensionJSON{Name: "channel_id"}, nil
+	case *tls.GREASEEncryptedClientHelloExtension:
+		e2 := extensionJSON{Name: "encrypted_client_hello", ECHPayloadLens: e.CandidatePayloadLens, Data: e.EncapsulatedKey}
+		for _, suite := range e.CandidateCipherSuites {
+			e2.ECHCipherSuites = append(e2.ECHCipherSuites, echCipherSuiteJSON{KDF: hexUint16(suite.KdfId), AEAD: hexUint16(suite.AeadId)})
+		}
+		e2.ECHConfigIDs = toUint16s(e.CandidateConfigIds)
+		return e2, nil
+	case *tls.RenegotiationInfoExtension:
+		return extensionJSON{Name: "renegotiation_info", Renegotiation: int(e.Renegotiation)}, nil
+	case *tls.GenericExtension:
+		return extensionJSON{Name: "generic", ID: hexUint16(e.Id), Data: e.Data}, nil
+	}
+
+	// 其他扩展：保存序列化后的内容（2 字节编号 + 2 字节长度 + 数据）
+	buf := make([]byte, ext.Len())
+	if n, _ := ext.Read(buf); n >= 4 {
+		return extensionJSON{Name: "generic", ID: hexUint16(uint16(buf[0])<<8 | uint16(buf[1])), Data: buf[4:n]}, nil
+	}
+	return extensionJSON{}, fmt.Errorf("unable to serialize TLS extension type %T", ext)
+}
+
+// extension 根据序列化格式构造 TLS 扩展
+func (e extensionJSON) extension() (tls.TLSExtension, error) {
+	switch e.Name {
+	case "grease":
+		return &tls.UtlsGREASEExtension{Value: uint16(e.ID), Body: e.Data}, nil
+	case "server_name":
+		return &tls.SNIExtension{ServerName: e.ServerName}, nil
+	case "status_request":
+		return &tls.StatusRequestExtension{}, nil
+	case "supported_groups":
+		curves := make([]tls.CurveID, 0, len(e.Curves))
+		for _, curve := range e.Curves {
+			curves = append(curves, tls.CurveID(curve))
+		}
+		return &tls.SupportedCurvesExtension{Curves: curves}, nil
+	case "ec_point_formats":
+		points, err := toBytes(e.Values)
+		if err != nil {
+			return nil, err
+		}
+		return &tls.SupportedPointsExtension{SupportedPoints: points}, nil
+	case "signature_algorithms":
+		return &tls.SignatureAlgorithmsExtension{SupportedSignatureAlgorithms: hexToSchemes(e.Algorithms)}, nil
+	case "alpn":
+		return &tls.ALPNExtension{AlpnProtocols: e.Protocols}, nil
+	case "status_request_v2":
+		return &tls.StatusRequestV2Extension{}, nil
+	case "signed_certificate_timestamp":
+		return &tls.SCTExtension{}, nil
+	case "padding":
+		ext := &tls.UtlsPaddingExtension{PaddingLen: e.PaddingLen, WillPad: e.WillPad}
+		switch e.PaddingStyle {
+		case "boring":
+			ext.GetPaddingLen = tls.BoringPaddingStyle
+		case "":
+		default:
+			return nil, fmt.Errorf("unknown padding style %q", e.PaddingStyle)
+		}
+		return ext, nil
+	case "extended_master_secret":
+		return &tls.ExtendedMasterSecretExtension{}, nil
+	case "token_binding":
+		params, err := toBytes(e.Values)
+		if err != nil {
+			return nil, err
+		}
+		return &tls.FakeTokenBindingExtension{MajorVersion: e.MajorVersion, MinorVersion: e.MinorVersion, KeyParameters: params}, nil
+	case "compress_certificate":
+		algorithms := make([]tls.CertCompressionAlgo, 0, len(e.Algorithms))
+		for _, algorithm := range e.Algorithms {
+			algorithms = append(algorithms, tls.CertCompressionAlgo(algorithm))
+		}
+		return &tls.UtlsCompressCertExtension{Algorithms: algorithms}, nil
+	case "record_size_limit":
+		return &tls.FakeRecordSizeLimitExtension{Limit: e.Limit}, nil
+	case "delegated_credentials":
+		return &tls.FakeDelegatedCredentialsExtension{SupportedSignatureAlgorithms: hexToSchemes(e.Algorithms)}, nil
+	case "session_ticket":
+		return &tls.SessionTicketExtension{}, nil
+	case "pre_shared_key":
+		return &tls.UtlsPreSharedKeyExtension{}, nil
+	case "fake_pre_shared_key":
+		ext := &tls.FakePreSharedKeyExtension{}
+		for _, identity := range e.Identities {
+			ext.Identities = append(ext.Identities, tls.PskIdentity{Label: identity.Identity, ObfuscatedTicketAge: identity.ObfuscatedTicketAge})
+		}
+		for _, binder := range e.Binders {
+			ext.Binders = append(ext.Binders, binder)
+		}
+		return ext, nil
+	case "supported_versions":
+		return &tls.SupportedVersionsExtension{Versions: fromHexUint16s(e.Versions)}, nil
+	case "cookie":
+		return &tls.CookieExtension{Cookie: e.Data}, nil
+	case "psk_key_exchange_modes":
+		modes, err := toBytes(e.Values)
+		if err != nil {
+			return nil, err
+		}
+		return &tls.PSKKeyExchangeModesExtension{Modes: modes}, nil
+	case "signature_algorithms_cert":
+		return &tls.SignatureAlgorithmsCertExtension{SupportedSignatureAlgorithms: hexToSchemes(e.Algorithms)}, nil
+	case "key_share":
+		shares := make([]tls.KeyShare, 0, len(e.KeyShares))
+		for _, share := range e.KeyShares {
+			shares = append(shares, tls.KeyShare{Group: tls.CurveID(share.Group), Data: share.Data})
+		}
+		return &tls.KeyShareExtension{KeyShares: shares}, nil
+	case "next_protocol_negotiation":
+		return &tls.NPNExtension{NextProtos: e.Protocols}, nil
+	case "application_settings":
+		return &tls.ApplicationSettingsExtension{SupportedProtocols: e.Protocols}, nil
+	case "application_settings_new":
+		return &tls.ApplicationSettingsExtensionNew{SupportedProtocols: e.Protocols}, nil
+	case "channel_id_old":
+		return &tls.FakeChannelIDExtension{OldExtensionID: true}, nil
+	case "channel_id":
+		return &tls.FakeChannelIDExtension{}, nil
+	case "encrypted_client_hello":
+		configIDs, err := toBytes(e.ECHConfigIDs)
+		if err != nil {
+			return nil, err
+		}
+		ext := &tls.GREASEEncryptedClientHelloExtension{
+			CandidateConfigIds:   configIDs,
+			EncapsulatedKey:      e.Data,
+			CandidatePayloadLens: e.ECHPayloadLens,
+		}
+		for _, suite := range e.ECHCipherSuites {
+			ext.CandidateCipherSuites = append(ext.CandidateCipherSuites, tls.HPKESymmetricCipherSuite{KdfId: uint16(suite.KDF), AeadId: uint16(suite.AEAD)})
+		}
+		return ext, nil
+	case "renegotiation_info":
+		return &tls.RenegotiationInfoExtension{Renegotiation: tls.RenegotiationSupport(e.Renegotiation)}, nil
+	case "generic":
+		return &tls.GenericExtension{Id: uint16(e.ID), Data: e.Data}, nil
+	}
+	return nil, fmt.Errorf("unknown TLS extension name %q", e.Name)
+}
+
+func toPriorityParamJSON(p http2.PriorityParam) priorityParamJSON {
+	return priorityParamJSON{Exclusive: p.Exclusive, StreamDep: p.StreamDep, Weight: p.Weight}
+}
+
+func (p priorityParamJSON) priorityParam() http2.PriorityParam {
+	return http2.PriorityParam{Exclusive: p.Exclusive, StreamDep: p.StreamDep, Weight: p.Weight}
+}
+
+func toHexUint16s(values []uint16) []hexUint16 {
+	result := make([]hexUint16, 0, len(values))
+	for _, v := range values {
+		result = append(result, hexUint16(v))
+	}
+	return result
+}
+
+func fromHexUint16s(values []hexUint16) []uint16 {
+	result := make([]uint16, 0, len(values))
+	for _, v := range values {
+		result = append(result, uint16(v))
+	}
+	return result
+}
+
+func schemesToHex(schemes []tls.SignatureScheme) []hexUint16 {
+	result := make([]hexUint16, 0, len(schemes))
+	for _, scheme := range schemes {
+		result = append(result, hexUint16(scheme))
+	}
+	return result
+}
+
+func hexToSchemes(values []hexUint16) []tls.SignatureScheme {
+	result := make([]tls.SignatureScheme, 0, len(values))
+	for _, v := range values {
+		result = append(result, tls.SignatureScheme(v))
+	}
+	return result
+}
+
+func toUint16s(values []byte) []uint16 {
+	result := make([]uint16, 0, len(values))
+	for _, v := range values {
+		result = append(result, uint16(v))
+	}
+	return result
+}
+
+// toBytes 将数字列表转换为字节，超出 0-255 时返回错误
+func toBytes(values []uint16) ([]byte, error) {
+	result := make([]byte, 0, len(values))
+	for _, v := range values {
+		if v > 0xff {
+			return nil, fmt.Errorf("value %d out of byte range", v)
+		}
+		result = append(result, byte(v))
+	}
+	return result, nil
+}
@@ -0,0 +1,122 @@
+package profiles
+
+import (
+	"errors"
+	"fmt"
+
+	tls "github.com/bogdanfinn/utls"
+)
+
+// resolveClientHelloSpec 获取 profile 的 ClientHelloSpec
+// 使用预定义 tls.ClientHelloID（没有 SpecFactory 或使用 utls 的空 SpecFactory）的 profile 会回退到 utls 内置的 spec；
+// 不是 utls 内置的 ClientHelloID 时返回 SpecFactory 的错误
+func (c ClientProfile) resolveClientHelloSpec() (tls.ClientHelloSpec, error) {
+	var factoryErr error
+	if c.clientHelloId.SpecFactory != nil {
+		spec, err := c.clientHelloId.ToSpec()
+		if err == nil {
+			return spec, nil
+		}
+		factoryErr = err
+	}
+
+	spec, err := tls.UTLSIdToSpec(c.clientHelloId)
+	if factoryErr != nil && errors.Is(err, tls.ErrUnknownClientHelloID) {
+		return tls.ClientHelloSpec{}, fmt.Errorf("unable to build ClientHelloSpec for %s: %w", c.GetClientHelloStr(), factoryErr)
+	}
+	if err != nil {
+		return tls.ClientHelloSpec{}, fmt.Errorf("unable to resolve ClientHelloSpec for %s: %w", c.GetClientHelloStr(), err)
+	}
+	return spec, nil
+}
+
+// isGREASE 判断是否为 GREASE 值（RFC 8701，形如 0x?a?a）
+func isGREASE(v uint16) bool {
+	return v&0x0f0f == 0x0a0a && v>>8 == v&0xff
+}
+
+// extensionID 返回 TLS 扩展的编号，GREASE 扩展返回 tls.GREASE_PLACEHOLDER
+func extensionID(ext tls.TLSExtension) (uint16, error) {
+	switch e := ext.(type) {
+	case *tls.UtlsGREASEExtension:
+		if e.Value != 0 {
+			return e.Value, nil
+		}
+		return tls.GREASE_PLACEHOLDER, nil
+	case *tls.SNIExtension:
+		return tls.ExtensionServerName, nil
+	case *tls.StatusRequestExtension:
+		return tls.ExtensionStatusRequest, nil
+	case *tls.SupportedCurvesExtension:
+		return tls.ExtensionSupportedCurves, nil
+	case *tls.SupportedPointsExtension:
+		return tls.ExtensionSupportedPoints, nil
+	case *tls.SignatureAlgorithmsExtension:
+		return tls.ExtensionSignatureAlgorithms, nil
+	case *tls.ALPNExtension:
+		return tls.ExtensionALPN, nil
+	case *tls.StatusRequestV2Extension:
+		return tls.ExtensionStatusRequestV2, nil
+	case *tls.SCTExtension:
+		return tls.ExtensionSCT, nil
+	case *tls.UtlsPaddingExtension:
+		return tls.ExtensionPadding, nil
+	case *tls.ExtendedMasterSecretExtension:
+		return tls.ExtensionExtendedMasterSecret, nil
+	case *tls.FakeTokenBindingExtension:
+		return extensionTokenBinding, nil
+	case *tls.UtlsCompressCertExtension:
+		return tls.ExtensionCompressCertificate, nil
+	case *tls.FakeRecordSizeLimitExtension:
+		return tls.ExtensionRecordSizeLimit, nil
+	case *tls.FakeDelegatedCredentialsExtension:
+		return tls.ExtensionDelegatedCredentials, nil
+	case *tls.SessionTicketExtension:
+		return tls.ExtensionSessionTicket, nil
+	case *tls.UtlsPreSharedKeyExtension, *tls.FakePreSharedKeyExtension:
+		return tls.ExtensionPreSharedKey, nil
+	case *tls.SupportedVersionsExtension:
+		return tls.ExtensionSupportedVersions, nil
+	case *tls.CookieExtension:
+		return tls.ExtensionCookie, nil
+	case *tls.PSKKeyExchangeModesExtension:
+		return tls.ExtensionPSKModes, nil
+	case *tls.SignatureAlgorithmsCertExtension:
+		return tls.ExtensionSignatureAlgorithmsCert, nil
+	case *tls.KeyShareExtension:
+		return tls.ExtensionKeyShare, nil
+	case *tls.QUICTransportParametersExtension:
+		return tls.ExtensionQUICTransportParameters, nil
+	case *tls.NPNExtension:
+		return tls.ExtensionNextProtoNeg, nil
+	case *tls.ApplicationSettingsExtension:
+		return tls.ExtensionALPSOld, nil
+	case *tls.ApplicationSettingsExtensionNew:
+		return tls.ExtensionALPS, nil
+	case *tls.FakeChannelIDExtension:
+		if e.OldExtensionID {
+			return extensionChannelIDOld, nil
+		}
+		return extensionChannelID, nil
+	case *tls.GREASEEncryptedClientHelloExtension:
+		return tls.ExtensionECH, nil
+	case *tls.RenegotiationInfoExtension:
+		return tls.ExtensionRenegotiationInfo, nil
+	case *tls.GenericExtension:
+		return e.Id, nil
+	}
+
+	// 未知扩展：尝试序列化后读取前两个字节的扩展编号
+	buf := make([]byte, ext.Len())
+	if n, _ := ext.Read(buf); n >= 2 {
+		return uint16(buf[0])<<8 | uint16(buf[1]), nil
+	}
+	return 0, fmt.Errorf("unknown TLS extension type %T", ext)
+}
+
+// 没有在 utls 中导出的扩展编号
+const (
+	extensionTokenBinding uint16 = 24
+	extensionChannelID    uint16 = 30032
+	extensionChannelIDOld uint16 = 30031
+)
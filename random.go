@@ -2,139 +2,187 @@ package fingerprint
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
-	"github.com/vistone/fingerprint/internal/utils"
+	"github.com/vistone/fingerprint/profiles"
 )
 
 // GetRandomFingerprint 随机获取一个指纹和对应的 User-Agent
 // 操作系统会随机选择
 func GetRandomFingerprint() (*FingerprintResult, error) {
-	return GetRandomFingerprintWithOS(OperatingSystem(""))
+	return defaultFingerprintGenerator.GetRandomFingerprint()
 }
 
 // GetRandomFingerprintWithOS 随机获取一个指纹和对应的 User-Agent，并指定操作系统
 // 如果 os 为空字符串，则随机选择操作系统
 func GetRandomFingerprintWithOS(os OperatingSystem) (*FingerprintResult, error) {
-	// 检查 MappedTLSClients 是否为空
-	if len(MappedTLSClients) == 0 {
+	return defaultFingerprintGenerator.GetRandomFingerprintWithOS(os)
+}
+
+// GetRandomFingerprintByBrowser 根据浏览器类型随机获取指纹和 User-Agent
+// browserType: "chrome", "firefox", "safari", "opera", "edge" 等
+func GetRandomFingerprintByBrowser(browserType string) (*FingerprintResult, error) {
+	return defaultFingerprintGenerator.GetRandomFingerprintByBrowser(browserType)
+}
+
+// GetRandomFingerprintByBrowserWithOS 根据浏览器类型随机获取指纹和 User-Agent，并指定操作系统
+func GetRandomFingerprintByBrowserWithOS(browserType string, os OperatingSystem) (*FingerprintResult, error) {
+	return defaultFingerprintGenerator.GetRandomFingerprintByBrowserWithOS(browserType, os)
+}
+
+// GetRandomFingerprint 随机获取一个指纹和对应的 User-Agent
+// 操作系统会随机选择
+func (g *Generator) GetRandomFingerprint() (*FingerprintResult, error) {
+	return g.GetRandomFingerprintWithOS(OperatingSystem{})
+}
+
+// GetRandomFingerprintWithOS 随机获取一个指纹和对应的 User-Agent，并指定操作系统
+// 如果 os 为空字符串，则随机选择操作系统
+func (g *Generator) GetRandomFingerprintWithOS(os OperatingSystem) (*FingerprintResult, error) {
+	defer g.lock()()
+
+	// 使用注册表快照，避免与并发注册的 profile 竞争
+	snapshot := g.registry.Snapshot()
+	if len(snapshot) == 0 {
 		return nil, fmt.Errorf("no TLS client profiles available")
 	}
 
-	// 获取所有可用的指纹名称
-	names := make([]string, 0, len(MappedTLSClients))
-	for name := range MappedTLSClients {
+	// 获取所有可用的指纹名称，排序后选择以保证相同种子得到相同结果
+	names := make([]string, 0, len(snapshot))
+	for name := range snapshot {
 		names = append(names, name)
 	}
+	sort.Strings(names)
 
-	// 随机选择一个（线程安全）
-	randomName := utils.RandomChoiceString(names)
-	profile := MappedTLSClients[randomName]
-	if profile.GetClientHelloStr() == "" {
-		return nil, fmt.Errorf("profile %s is invalid (empty ClientHelloStr)", randomName)
+	// 指定操作系统时只在能运行于该操作系统的 profile 中选择
+	names = filterCompatible(snapshot, names, os)
+	if len(names) == 0 {
+		return nil, &ErrIncompatibleOS{OS: os}
 	}
 
-	// 获取对应的 User-Agent
-	var ua string
-	var err error
-	if os == "" {
-		ua, err = GetUserAgentByProfileName(randomName)
-	} else {
-		ua, err = GetUserAgentByProfileNameWithOS(randomName, os)
-	}
-	if err != nil {
-		return nil, err
+	if g.persona != nil {
+		return g.personaFingerprint(snapshot, names, os, "", false)
 	}
-
-	// 生成标准 HTTP Headers
-	browserTypeStr, _ := inferBrowserFromProfileName(randomName)
-	isMobile := isMobileProfile(randomName)
-	headers := GenerateHeaders(BrowserType(browserTypeStr), ua, isMobile)
-
-	return &FingerprintResult{
-		Profile:       profile,
-		UserAgent:     ua,
-		HelloClientID: profile.GetClientHelloStr(),
-		Headers:       headers,
-	}, nil
+	randomName := names[g.intn(len(names))]
+	return g.newFingerprintResult(randomName, snapshot[randomName], os)
 }
 
 // GetRandomFingerprintByBrowser 根据浏览器类型随机获取指纹和 User-Agent
-// browserType: "chrome", "firefox", "safari", "opera" 等
-func GetRandomFingerprintByBrowser(browserType string) (*FingerprintResult, error) {
-	return GetRandomFingerprintByBrowserWithOS(browserType, OperatingSystem(""))
+// browserType: "chrome", "firefox", "safari", "opera", "edge" 等
+func (g *Generator) GetRandomFingerprintByBrowser(browserType string) (*FingerprintResult, error) {
+	return g.GetRandomFingerprintByBrowserWithOS(browserType, OperatingSystem{})
 }
 
 // GetRandomFingerprintByBrowserWithOS 根据浏览器类型随机获取指纹和 User-Agent，并指定操作系统
-func GetRandomFingerprintByBrowserWithOS(browserType string, os OperatingSystem) (*FingerprintResult, error) {
+func (g *Generator) GetRandomFingerprintByBrowserWithOS(browserType string, os OperatingSystem) (*FingerprintResult, error) {
 	if browserType == "" {
 		return nil, fmt.Errorf("browser type cannot be empty")
 	}
-	if len(MappedTLSClients) == 0 {
+	defer g.lock()()
+
+	snapshot := g.registry.Snapshot()
+	if len(snapshot) == 0 {
 		return nil, fmt.Errorf("no TLS client profiles available")
 	}
 
 	browserType = strings.ToLower(browserType)
 
 	// 筛选出指定浏览器类型的指纹
-	candidates := make([]string, 0)
-	for name := range MappedTLSClients {
-		nameLower := strings.ToLower(name)
-		if strings.HasPrefix(nameLower, browserType+"_") {
-			candidates = append(candidates, name)
-		}
-	}
+	// 应用 profile（如 zalando_ios_mobile）不属于任何浏览器类型
+	candidates := selectNames(snapshot, Query{Browser: browserType, ExcludeApps: true})
 
 	if len(candidates) == 0 {
 		return nil, &ErrBrowserNotFound{Browser: browserType}
 	}
+	candidates = filterCompatible(snapshot, candidates, os)
+	if len(candidates) == 0 {
+		return nil, &ErrIncompatibleOS{Browser: browserType, OS: os}
+	}
+
+	if g.persona != nil {
+		return g.personaFingerprint(snapshot, candidates, os, "", false)
+	}
+	randomName := candidates[g.intn(len(candidates))]
+	return g.newFingerprintResult(randomName, snapshot[randomName], os)
+}
+
+// ErrBrowserNotFound 浏览器类型未找到错误
+type ErrBrowserNotFound struct {
+	Browser string
+}
+
+func (e *ErrBrowserNotFound) Error() string {
+	return "browser type not found: " + e.Browser
+}
 
-	// 随机选择一个（线程安全）
-	randomName := utils.RandomChoiceString(candidates)
-	profile := MappedTLSClients[randomName]
+// newFingerprintResult 为选中的 profile 生成 User-Agent 和标准 HTTP Headers
+// 如果 os 为零值，则按权重随机选择与 profile 兼容的操作系统；调用方需持有 g.lock()
+func (g *Generator) newFingerprintResult(name string, profile ClientProfile, os OperatingSystem) (*FingerprintResult, error) {
+	// 先确定操作系统和语言，User-Agent 和 headers 的生成过程不再使用随机数
+	if os.IsZero() {
+		os = g.randomOSFor(profile)
+	}
+	return buildFingerprintResult(g.registry, name, profile, os, g.randomLanguage())
+}
+
+// buildFingerprintResult 使用确定的操作系统和 locale 列表为 profile 生成 User-Agent 和标准 HTTP Headers
+// 相同的参数总是得到相同的结果
+func buildFingerprintResult(registry *profiles.Registry, name string, profile ClientProfile, os OperatingSystem, locales []string) (*FingerprintResult, error) {
 	if profile.GetClientHelloStr() == "" {
-		return nil, fmt.Errorf("profile %s is invalid (empty ClientHelloStr)", randomName)
+		return nil, fmt.Errorf("profile %s is invalid (empty ClientHelloStr)", name)
 	}
 
 	// 获取对应的 User-Agent
-	var ua string
-	var err error
-	if os == "" {
-		ua, err = GetUserAgentByProfileName(randomName)
-	} else {
-		ua, err = GetUserAgentByProfileNameWithOS(randomName, os)
-	}
+	ua, os, err := defaultGenerator.userAgentFor(registry, name, os)
 	if err != nil {
 		return nil, err
 	}
 
-	// 生成标准 HTTP Headers
-	browserTypeStr, _ := inferBrowserFromProfileName(randomName)
-	isMobile := isMobileProfile(randomName)
-	headers := GenerateHeaders(BrowserType(browserTypeStr), ua, isMobile)
+	// 生成标准 HTTP Headers，高熵 client hints 使用操作系统的实际版本和架构
+	headers := generateProfileHeaders(registry, name, profile, ua, locales)
+	headers.applyOS(os)
+	headers.PseudoHeaderOrder = append([]string(nil), profile.GetPseudoHeaderOrder()...)
 
 	return &FingerprintResult{
 		Profile:       profile,
 		UserAgent:     ua,
 		HelloClientID: profile.GetClientHelloStr(),
 		Headers:       headers,
+		OS:            os,
+		Locales:       append([]string(nil), locales...),
 	}, nil
 }
 
-// ErrBrowserNotFound 浏览器类型未找到错误
-type ErrBrowserNotFound struct {
-	Browser string
-}
-
-func (e *ErrBrowserNotFound) Error() string {
-	return "browser type not found: " + e.Browser
-}
+// generateProfileHeaders 根据 profile 的 metadata 生成标准 HTTP Headers
+// 应用 profile 使用其网络库的默认 headers，不使用浏览器的 header 表和 client hints
+// 通过 LoadDir/LoadFS 加载的 profile 还会使用文件中的 headers 和 header 顺序
+func generateProfileHeaders(registry *profiles.Registry, profileName string, profile ClientProfile, userAgent string, locales []string) *HTTPHeaders {
+	metadata := profile.Metadata()
+	browserType := BrowserType(metadata.Browser)
+	if browserType == "" {
+		browserType = BrowserChrome
+	}
 
-// isMobileProfile 判断是否为移动端 profile
-func isMobileProfile(profileName string) bool {
-	name := strings.ToLower(profileName)
-	return strings.Contains(name, "ios") ||
-		strings.Contains(name, "android") ||
-		strings.Contains(name, "ipad") ||
-		strings.Contains(name, "mobile")
+	var headers *HTTPHeaders
+	if metadata.App != "" {
+		headers = generateAppHeaders(userAgent, metadata.Platform, locales)
+	} else {
+		headers = generateHeaders(browserType, userAgent, metadata.Mobile(), locales)
+		headers.alignPriority(profile.GetHeaderPriority())
+	}
+	def, loaded := registry.Definition(profileName)
+	if !loaded {
+		return headers
+	}
+	if len(def.Headers) > 0 {
+		headers = headers.Merge(def.Headers)
+	}
+	if len(def.HeaderOrder) > 0 {
+		headers.HeaderOrder = make([]string, 0, len(def.HeaderOrder))
+		for _, key := range def.HeaderOrder {
+			headers.HeaderOrder = append(headers.HeaderOrder, strings.ToLower(key))
+		}
+	}
+	return headers
 }
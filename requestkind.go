@@ -0,0 +1,104 @@
+package fingerprint
+
+// RequestKind 请求类型，决定 Accept、Sec-Fetch-* 等随请求用途变化的 headers
+type RequestKind string
+
+const (
+	RequestNavigate RequestKind = "navigate" // 顶级页面导航（地址栏、书签、点击链接）
+	RequestIframe   RequestKind = "iframe"   // iframe 中的页面
+	RequestFetch    RequestKind = "fetch"    // fetch() 请求
+	RequestXHR      RequestKind = "xhr"      // XMLHttpRequest 请求
+	RequestImage    RequestKind = "image"    // <img> 图片
+	RequestScript   RequestKind = "script"   // 经典 <script> 脚本
+	RequestStyle    RequestKind = "style"    // <link rel="stylesheet"> 样式表
+)
+
+// SiteRelation 发起请求的页面与目标 URL 的关系，对应 Sec-Fetch-Site 的取值
+type SiteRelation string
+
+const (
+	SiteSameOrigin SiteRelation = "same-origin" // 同源（默认）
+	SiteSameSite   SiteRelation = "same-site"   // 同站不同源，如 www.example.com → api.example.com
+	SiteCrossSite  SiteRelation = "cross-site"  // 跨站
+)
+
+// Context 请求的发起方信息
+type Context struct {
+	Origin   string       // 发起请求的页面 origin（如 "https://example.com"），为空表示用户直接访问（Sec-Fetch-Site: none）
+	Referer  string       // Referer 头，为空时不发送
+	SameSite SiteRelation // 发起页面与目标的关系，为空时视为同源；Origin 为空的导航请求忽略该字段
+}
+
+// fetchSite 返回请求的 Sec-Fetch-Site
+func (c Context) fetchSite(kind RequestKind) string {
+	if c.Origin == "" && kind == RequestNavigate {
+		return "none"
+	}
+	if c.SameSite == "" {
+		return string(SiteSameOrigin)
+	}
+	return string(c.SameSite)
+}
+
+// GenerateHeadersFor 生成浏览器发送 kind 类型请求时的 headers，Accept-Language 按当前权重随机选择
+// 与 GenerateHeaders 相比，Accept、Sec-Fetch-*、Upgrade-Insecure-Requests 随请求类型变化，
+// 并根据 ctx 设置 Sec-Fetch-Site、Referer 和 Origin（跨源的 fetch/XHR 请求）
+func GenerateHeadersFor(browserType BrowserType, userAgent string, isMobile bool, kind RequestKind, ctx Context) *HTTPHeaders {
+	return defaultFingerprintGenerator.GenerateHeadersFor(browserType, userAgent, isMobile, kind, ctx)
+}
+
+// generateHeadersFor 生成 kind 类型请求的 headers，Accept-Language 由 locales 生成
+func generateHeadersFor(browserType BrowserType, userAgent string, isMobile bool, locales []string, kind RequestKind, ctx Context) *HTTPHeaders {
+	headers := generateHeaders(browserType, userAgent, isMobile, locales)
+	headers.applyRequestKind(browserType, kind, ctx)
+	return headers
+}
+
+// applyRequestKind 将导航请求的 headers 调整为 kind 类型请求的 headers
+// 只有 User-Agent 中的浏览器版本支持 Sec-Fetch-* 时才设置 Sec-Fetch-*，与 GenerateHeaders 保持一致
+func (h *HTTPHeaders) applyRequestKind(browserType BrowserType, kind RequestKind, ctx Context) {
+	if kind == "" {
+		kind = RequestNavigate
+	}
+	release := headerReleaseFor(browserType, h.UserAgent)
+	fetchMetadata := release.fetchMetadata
+	site := ctx.fetchSite(kind)
+	h.Priority = release.priorities[kind]
+
+	switch kind {
+	case RequestNavigate:
+		if fetchMetadata {
+			h.SecFetchSite = site
+		}
+
+	case RequestIframe:
+		// iframe 的加载不是用户激活的导航，不发送 Sec-Fetch-User
+		h.SecFetchUser = ""
+		if fetchMetadata {
+			h.SecFetchSite = site
+			h.SecFetchDest = "iframe"
+		}
+
+	default:
+		h.Accept = release.subresourceAccept(kind)
+		h.UpgradeInsecureRequests = ""
+		h.SecFetchUser = ""
+		mode, dest := "no-cors", string(kind)
+		if kind == RequestFetch || kind == RequestXHR {
+			mode, dest = "cors", "empty"
+			// CORS 请求跨源时发送 Origin，同源的 GET 请求不发送
+			if ctx.Origin != "" && site != string(SiteSameOrigin) {
+				h.Set("Origin", ctx.Origin)
+			}
+		}
+		if fetchMetadata {
+			h.SecFetchSite = site
+			h.SecFetchMode = mode
+			h.SecFetchDest = dest
+		}
+	}
+
+	if ctx.Referer != "" {
+		h.Set("Referer", ctx.Referer)
+	}
+}
@@ -0,0 +1,203 @@
+package fingerprint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/vistone/fingerprint/profiles"
+)
+
+// Query profile 筛选条件，零值字段表示不限定，所有条件同时满足才匹配
+type Query struct {
+	Browser    string   // 浏览器类型：chrome、firefox、safari、opera、edge
+	Engine     string   // 浏览器引擎：blink、gecko、webkit
+	MinVersion int      // 最低浏览器主版本号（包含）
+	MaxVersion int      // 最高浏览器主版本号（包含）
+	Platform   string   // 平台：windows、macos、linux、ios、ipados、android；不限定平台的桌面 profile 匹配任意桌面平台
+	Device     string   // 设备类型：desktop、mobile、tablet
+	Mobile     bool     // 只匹配移动设备（手机或平板）
+	Features   []string // 必须具有的 TLS 特性：psk、pq、ech
+	App        string   // 应用名称（如 okhttp4），只匹配该应用的 profile
+
+	// ExcludeApps 排除应用 profile（如 zalando_ios_mobile），只匹配真实浏览器
+	// 应用 profile 没有 Browser 和版本，但默认会被 Platform、Device 等条件匹配到
+	ExcludeApps bool
+}
+
+// ErrNoProfileMatch 没有满足筛选条件的 profile
+type ErrNoProfileMatch struct {
+	Query Query
+}
+
+func (e *ErrNoProfileMatch) Error() string {
+	return fmt.Sprintf("no profile matches query %+v", e.Query)
+}
+
+// Validate 检查筛选条件中的名称是否有效
+func (q Query) Validate() error {
+	for _, name := range q.Features {
+		if _, ok := profiles.ParseFeature(name); !ok {
+			return fmt.Errorf("unknown feature %q", name)
+		}
+	}
+	if q.MinVersion > 0 && q.MaxVersion > 0 && q.MinVersion > q.MaxVersion {
+		return fmt.Errorf("min version %d is greater than max version %d", q.MinVersion, q.MaxVersion)
+	}
+	switch profiles.BrowserFamily(strings.ToLower(q.Browser)) {
+	case "", profiles.BrowserChrome, profiles.BrowserFirefox, profiles.BrowserSafari, profiles.BrowserOpera, profiles.BrowserEdge:
+	default:
+		return fmt.Errorf("unknown browser %q", q.Browser)
+	}
+	switch profiles.Engine(strings.ToLower(q.Engine)) {
+	case "", profiles.EngineBlink, profiles.EngineGecko, profiles.EngineWebKit:
+	default:
+		return fmt.Errorf("unknown engine %q", q.Engine)
+	}
+	switch profiles.DeviceClass(strings.ToLower(q.Device)) {
+	case "", profiles.DeviceDesktop, profiles.DeviceMobile, profiles.DeviceTablet:
+	default:
+		return fmt.Errorf("unknown device class %q", q.Device)
+	}
+	switch profiles.Platform(strings.ToLower(q.Platform)) {
+	case "", profiles.PlatformWindows, profiles.PlatformMacOS, profiles.PlatformLinux,
+		profiles.PlatformIOS, profiles.PlatformIPadOS, profiles.PlatformAndroid:
+	default:
+		return fmt.Errorf("unknown platform %q", q.Platform)
+	}
+	return nil
+}
+
+// Match 判断 profile 是否满足筛选条件，可以配合 Registry.Filter 在独立注册表中筛选
+func (q Query) Match(profile ClientProfile) bool {
+	m := profile.Metadata()
+
+	if q.Browser != "" && !strings.EqualFold(string(m.Browser), q.Browser) {
+		return false
+	}
+	if q.Engine != "" && !strings.EqualFold(string(m.Engine), q.Engine) {
+		return false
+	}
+	if q.MinVersion > 0 && m.MajorVersion < q.MinVersion {
+		return false
+	}
+	if q.MaxVersion > 0 && m.MajorVersion > q.MaxVersion {
+		return false
+	}
+	if q.Platform != "" && !matchPlatform(m, profiles.Platform(strings.ToLower(q.Platform))) {
+		return false
+	}
+	if q.Device != "" && !strings.EqualFold(string(m.Device), q.Device) {
+		return false
+	}
+	if q.Mobile && !m.Mobile() {
+		return false
+	}
+	for _, name := range q.Features {
+		feature, ok := profiles.ParseFeature(name)
+		if !ok || !m.Features.Has(feature) {
+			return false
+		}
+	}
+	if q.App != "" && !strings.EqualFold(m.App, q.App) {
+		return false
+	}
+	if q.ExcludeApps && m.App != "" {
+		return false
+	}
+	return true
+}
+
+// matchPlatform 判断 profile 是否可以用于指定平台
+// 不限定平台的桌面 profile 可以搭配任意桌面操作系统
+func matchPlatform(m profiles.Metadata, platform profiles.Platform) bool {
+	if m.Platform == platform {
+		return true
+	}
+	if m.Platform != "" || m.Mobile() {
+		return false
+	}
+	switch platform {
+	case profiles.PlatformWindows, profiles.PlatformMacOS, profiles.PlatformLinux:
+		return true
+	}
+	return false
+}
+
+// Select 返回 DefaultRegistry 中满足筛选条件的 profile 名称（按名称排序）
+// 条件无效（见 Query.Validate）时返回 nil，需要区分无效条件和没有匹配时使用 SelectNames
+func Select(q Query) []string {
+	names, _ := SelectNames(q)
+	return names
+}
+
+// SelectNames 返回 DefaultRegistry 中满足筛选条件的 profile 名称（按名称排序）
+// 条件无效时返回 Query.Validate 的错误；没有满足条件的 profile 时返回空列表和 nil
+func SelectNames(q Query) ([]string, error) {
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+	return selectNames(profiles.DefaultRegistry.Snapshot(), q), nil
+}
+
+// SelectRandom 从满足筛选条件的 profile 中随机选择一个，返回指纹、User-Agent 和标准 HTTP Headers
+// 指定了桌面平台（windows、macos、linux）时，User-Agent 使用该平台的操作系统
+func SelectRandom(q Query) (*FingerprintResult, error) {
+	return defaultFingerprintGenerator.SelectRandom(q)
+}
+
+// SelectRandom 从 Generator 注册表中满足筛选条件的 profile 中随机选择一个，返回指纹、User-Agent 和标准 HTTP Headers
+// 指定了桌面平台（windows、macos、linux）时，User-Agent 使用该平台的操作系统
+func (g *Generator) SelectRandom(q Query) (*FingerprintResult, error) {
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+	defer g.lock()()
+
+	snapshot := g.registry.Snapshot()
+	names := selectNames(snapshot, q)
+	if len(names) == 0 {
+		return nil, &ErrNoProfileMatch{Query: q}
+	}
+
+	platform := profiles.Platform(strings.ToLower(q.Platform))
+	if g.persona != nil {
+		return g.personaFingerprint(snapshot, names, OperatingSystem{}, platform, false)
+	}
+	name := names[g.intn(len(names))]
+	return g.newFingerprintResult(name, snapshot[name], g.randomOSForPlatform(platform, snapshot[name]))
+}
+
+// selectNames 返回快照中满足筛选条件的 profile 名称（按名称排序）
+func selectNames(snapshot map[string]ClientProfile, q Query) []string {
+	names := make([]string, 0)
+	for name, profile := range snapshot {
+		if q.Match(profile) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// platformOperatingSystems 桌面平台对应的操作系统
+var platformOperatingSystems = map[profiles.Platform][]OperatingSystem{
+	profiles.PlatformWindows: {OSWindows10, OSWindows11},
+	profiles.PlatformMacOS:   {OSMacOS12, OSMacOS13, OSMacOS14, OSMacOS15},
+	profiles.PlatformLinux:   {OSLinux, OSLinuxUbuntu, OSLinuxDebian},
+}
+
+// randomOSForPlatform 随机选择桌面平台对应且与 profile 兼容的操作系统（见 CompatibleOS）
+// 其他平台或没有兼容的操作系统时返回零值（由 User-Agent 生成逻辑决定）；调用方需持有 g.lock()
+func (g *Generator) randomOSForPlatform(platform profiles.Platform, profile ClientProfile) OperatingSystem {
+	systems := make([]OperatingSystem, 0, len(platformOperatingSystems[platform]))
+	for _, os := range platformOperatingSystems[platform] {
+		if CompatibleOS(profile, os) {
+			systems = append(systems, os)
+		}
+	}
+	if len(systems) == 0 {
+		return OperatingSystem{}
+	}
+	return systems[g.intn(len(systems))]
+}
@@ -0,0 +1,55 @@
+package fingerprint_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vistone/fingerprint"
+)
+
+// TestAkamaiFingerprintGolden 测试已知浏览器的 Akamai HTTP/2 指纹
+func TestAkamaiFingerprintGolden(t *testing.T) {
+	cases := map[string]struct {
+		fingerprint string
+		hash        string
+	}{
+		"chrome_133": {
+			fingerprint: "1:65536;2:0;4:6291456;6:262144|15663105|0|m,a,s,p",
+			hash:        "52d84b11737d980aef856699f885ca86",
+		},
+		"firefox_117": {
+			fingerprint: "1:65536;4:131072;5:16384|12517377|3:0:0:201,5:0:0:101,7:0:0:1,9:0:7:1,11:0:3:1,13:0:0:241|m,p,a,s",
+			hash:        "3d9132023bf26a71d40fe766e5c24c9d",
+		},
+		"safari_ios_18_0": {
+			fingerprint: "2:0;3:100;4:2097152;8:1;9:1|10420225|0|m,s,a,p",
+			hash:        "d4a2dcbfde511b5040ed5a5190a8d78b",
+		},
+	}
+
+	for name, expected := range cases {
+		t.Run(name, func(t *testing.T) {
+			profile := fingerprint.MappedTLSClients[name]
+			if got := profile.AkamaiFingerprint(); got != expected.fingerprint {
+				t.Errorf("Akamai 指纹不匹配\n期望: %s\n实际: %s", expected.fingerprint, got)
+			}
+			if got := profile.AkamaiFingerprintHash(); got != expected.hash {
+				t.Errorf("Akamai 指纹哈希应为 %s，实际为 %s", expected.hash, got)
+			}
+		})
+	}
+}
+
+// TestAkamaiFingerprintAllProfiles 测试所有 profile 的 Akamai 指纹格式
+func TestAkamaiFingerprintAllProfiles(t *testing.T) {
+	for name, profile := range fingerprint.MappedTLSClients {
+		fields := strings.Split(profile.AkamaiFingerprint(), "|")
+		if len(fields) != 4 {
+			t.Errorf("%s: Akamai 指纹应包含 4 个字段: %v", name, fields)
+			continue
+		}
+		if len(strings.Split(fields[3], ",")) != len(profile.GetPseudoHeaderOrder()) {
+			t.Errorf("%s: 伪头部数量与 profile 不一致: %s", name, fields[3])
+		}
+	}
+}
@@ -0,0 +1,297 @@
+package fingerprint_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	http "github.com/bogdanfinn/fhttp"
+	"github.com/bogdanfinn/fhttp/http2"
+	"github.com/bogdanfinn/fhttp/http2/hpack"
+	"github.com/bogdanfinn/fhttp/httptest"
+	"github.com/vistone/fingerprint"
+)
+
+// newTLSTestServer 启动本地 TLS 测试服务器，响应内容为请求使用的协议版本
+func newTLSTestServer(t *testing.T, enableHTTP2 bool) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.Proto)
+	}))
+	server.EnableHTTP2 = enableHTTP2
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// TestNewHTTPClientHTTP2 测试使用 profile 创建的客户端通过 ALPN 协商 HTTP/2
+func TestNewHTTPClientHTTP2(t *testing.T) {
+	server := newTLSTestServer(t, true)
+
+	testProfiles := []string{"chrome_133", "chrome_120", "firefox_135", "safari_ios_18_0"}
+	for _, name := range testProfiles {
+		t.Run(name, func(t *testing.T) {
+			profile := fingerprint.MappedTLSClients[name]
+			client, err := fingerprint.NewHTTPClient(profile, fingerprint.WithInsecureSkipVerify())
+			if err != nil {
+				t.Fatalf("创建 HTTP 客户端失败: %v", err)
+			}
+
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				t.Fatalf("请求失败: %v", err)
+			}
+			defer resp.Body.Close()
+
+			body, _ := io.ReadAll(resp.Body)
+			if string(body) != "HTTP/2.0" {
+				t.Errorf("期望服务端收到 HTTP/2.0 请求，实际为 %s", body)
+			}
+		})
+	}
+}
+
+// TestNewHTTPClientHTTP1 测试服务端不支持 HTTP/2 或强制 HTTP/1.1 时回退到 HTTP/1.1
+func TestNewHTTPClientHTTP1(t *testing.T) {
+	cases := []struct {
+		name        string
+		enableHTTP2 bool
+		opts        []fingerprint.ClientOption
+	}{
+		{"server_http1", false, nil},
+		{"force_http1", true, []fingerprint.ClientOption{fingerprint.WithForceHTTP1()}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newTLSTestServer(t, tc.enableHTTP2)
+
+			opts := append([]fingerprint.ClientOption{fingerprint.WithInsecureSkipVerify()}, tc.opts...)
+			client, err := fingerprint.NewHTTPClient(fingerprint.DefaultClientProfile, opts...)
+			if err != nil {
+				t.Fatalf("创建 HTTP 客户端失败: %v", err)
+			}
+
+			for i := 0; i < 2; i++ {
+				resp, err := client.Get(server.URL)
+				if err != nil {
+					t.Fatalf("第 %d 次请求失败: %v", i+1, err)
+				}
+				body, _ := io.ReadAll(resp.Body)
+				resp.Body.Close()
+
+				if string(body) != "HTTP/1.1" {
+					t.Errorf("期望服务端收到 HTTP/1.1 请求，实际为 %s", body)
+				}
+			}
+		})
+	}
+}
+
+// TestNewHTTPClientVerifiesCertificate 测试默认会校验服务端证书
+func TestNewHTTPClientVerifiesCertificate(t *testing.T) {
+	server := newTLSTestServer(t, true)
+
+	client, err := fingerprint.NewHTTPClient(fingerprint.DefaultClientProfile)
+	if err != nil {
+		t.Fatalf("创建 HTTP 客户端失败: %v", err)
+	}
+
+	if _, err := client.Get(server.URL); err == nil {
+		t.Error("自签名证书应当校验失败")
+	}
+}
+
+// TestNewHTTPClientAkamaiFingerprint 测试服务端收到的 HTTP/2 SETTINGS（含顺序）、WINDOW_UPDATE、PRIORITY 帧
+// 和伪头部顺序与 profile 的 Akamai 指纹一致
+func TestNewHTTPClientAkamaiFingerprint(t *testing.T) {
+	for _, name := range []string{"chrome_133", "firefox_135", "safari_ios_18_0"} {
+		t.Run(name, func(t *testing.T) {
+			addr, fingerprints := newAkamaiTestServer(t)
+			profile := fingerprint.MappedTLSClients[name]
+			client, err := fingerprint.NewHTTPClient(profile, fingerprint.WithInsecureSkipVerify())
+			if err != nil {
+				t.Fatalf("创建 HTTP 客户端失败: %v", err)
+			}
+
+			resp, err := client.Get("https://" + addr + "/")
+			if err != nil {
+				t.Fatalf("请求失败: %v", err)
+			}
+			resp.Body.Close()
+
+			if actual, expected := <-fingerprints, profile.AkamaiFingerprint(); actual != expected {
+				t.Errorf("服务端看到的 Akamai 指纹为 %s，期望 %s", actual, expected)
+			}
+		})
+	}
+}
+
+// newAkamaiTestServer 启动只处理一个连接的 HTTP/2 服务器，从收到的帧计算 Akamai 指纹并写入返回的 channel
+func newAkamaiTestServer(t *testing.T) (string, <-chan string) {
+	t.Helper()
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{selfSignedCertificate(t)},
+		NextProtos:   []string{"h2"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	fingerprints := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		fp, err := serveAkamai(conn)
+		if err != nil {
+			fp = "error: " + err.Error()
+		}
+		fingerprints <- fp
+	}()
+	return listener.Addr().String(), fingerprints
+}
+
+// serveAkamai 读取客户端前言到第一个 HEADERS 帧，返回 Akamai 指纹，并以 200 响应该请求
+func serveAkamai(conn net.Conn) (string, error) {
+	preface := make([]byte, len(http2.ClientPreface))
+	if _, err := io.ReadFull(conn, preface); err != nil || string(preface) != http2.ClientPreface {
+		return "", fmt.Errorf("invalid preface: %q %v", preface, err)
+	}
+
+	framer := http2.NewFramer(conn, conn)
+	framer.ReadMetaHeaders = hpack.NewDecoder(4096, nil)
+	var settings, priorities, pseudoHeaders []string
+	windowUpdate := "0"
+	for {
+		frame, err := framer.ReadFrame()
+		if err != nil {
+			return "", err
+		}
+		switch f := frame.(type) {
+		case *http2.SettingsFrame:
+			if !f.IsAck() {
+				_ = f.ForeachSetting(func(s http2.Setting) error {
+					settings = append(settings, fmt.Sprintf("%d:%d", uint16(s.ID), s.Val))
+					return nil
+				})
+			}
+		case *http2.WindowUpdateFrame:
+			if f.StreamID == 0 {
+				windowUpdate = fmt.Sprint(f.Increment)
+			}
+		case *http2.PriorityFrame:
+			exclusive := 0
+			if f.Exclusive {
+				exclusive = 1
+			}
+			priorities = append(priorities, fmt.Sprintf("%d:%d:%d:%d", f.StreamID, exclusive, f.StreamDep, int(f.Weight)+1))
+		case *http2.MetaHeadersFrame:
+			for _, field := range f.Fields {
+				if strings.HasPrefix(field.Name, ":") {
+					pseudoHeaders = append(pseudoHeaders, field.Name[1:2])
+				}
+			}
+
+			var block bytes.Buffer
+			_ = hpack.NewEncoder(&block).WriteField(hpack.HeaderField{Name: ":status", Value: "200"})
+			_ = framer.WriteSettings()
+			_ = framer.WriteSettingsAck()
+			_ = framer.WriteHeaders(http2.HeadersFrameParam{StreamID: f.StreamID, BlockFragment: block.Bytes(), EndStream: true, EndHeaders: true})
+
+			priority := strings.Join(priorities, ",")
+			if priority == "" {
+				priority = "0"
+			}
+			return strings.Join([]string{strings.Join(settings, ";"), windowUpdate, priority, strings.Join(pseudoHeaders, ",")}, "|"), nil
+		}
+	}
+}
+
+// selfSignedCertificate 生成 127.0.0.1 的自签名证书
+func selfSignedCertificate(t *testing.T) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+// TestNewHTTPClientSlowDial 测试一个地址拨号阻塞时不影响其他地址的请求，且拨号遵守请求的 context
+func TestNewHTTPClientSlowDial(t *testing.T) {
+	server := newTLSTestServer(t, true)
+	blocked := "192.0.2.1:443"
+
+	dialer := &net.Dialer{}
+	client, err := fingerprint.NewHTTPClient(fingerprint.DefaultClientProfile,
+		fingerprint.WithInsecureSkipVerify(),
+		fingerprint.WithDialContext(func(ctx context.Context, network, addr string) (net.Conn, error) {
+			if addr == blocked {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}
+			return dialer.DialContext(ctx, network, addr)
+		}))
+	if err != nil {
+		t.Fatalf("创建 HTTP 客户端失败: %v", err)
+	}
+
+	slow := make(chan error, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	go func() {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "https://"+blocked+"/", nil)
+		_, err := client.Do(req)
+		slow <- err
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	start := time.Now()
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	resp.Body.Close()
+	if elapsed := time.Since(start); elapsed > 400*time.Millisecond {
+		t.Errorf("其他地址的请求被阻塞了 %v", elapsed)
+	}
+
+	select {
+	case err := <-slow:
+		if err == nil {
+			t.Errorf("拨号被取消的请求应返回错误")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("拨号没有遵守请求的 context")
+	}
+}
@@ -0,0 +1,211 @@
+package fingerprint_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vistone/fingerprint"
+)
+
+const allAcceptCH = "Sec-CH-UA-Full-Version-List, sec-ch-ua-platform-version, Sec-CH-UA-Arch, Sec-CH-UA-Bitness, Sec-CH-UA-Model, Sec-CH-UA-WoW64, Sec-CH-UA-Form-Factors"
+
+// TestClientHintsDesktop 测试桌面 Chrome 的高熵 client hints 只在 Accept-CH 请求后发送
+func TestClientHintsDesktop(t *testing.T) {
+	ua := "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/133.0.0.0 Safari/537.36"
+	headers := fingerprint.GenerateHeaders(fingerprint.BrowserChrome, ua, false)
+
+	if headers.SecCHUA != `"Not(A:Brand";v="99", "Google Chrome";v="133", "Chromium";v="133"` {
+		t.Errorf("Sec-CH-UA 不正确: %s", headers.SecCHUA)
+	}
+	if _, ok := headers.ToMap()["Sec-CH-UA-Full-Version-List"]; ok {
+		t.Fatalf("未请求时不应发送高熵 client hints")
+	}
+
+	headers.ApplyAcceptCH("Sec-CH-UA-Full-Version-List, Sec-CH-UA-Model")
+	m := headers.ToMap()
+	if m["Sec-CH-UA-Full-Version-List"] != `"Not(A:Brand";v="99.0.0.0", "Google Chrome";v="133.0.6943.142", "Chromium";v="133.0.6943.142"` {
+		t.Errorf("Sec-CH-UA-Full-Version-List 不正确: %s", m["Sec-CH-UA-Full-Version-List"])
+	}
+	if m["Sec-CH-UA-Model"] != `""` {
+		t.Errorf("桌面 Sec-CH-UA-Model 应为空字符串: %s", m["Sec-CH-UA-Model"])
+	}
+	if _, ok := m["Sec-CH-UA-Arch"]; ok {
+		t.Errorf("只应发送请求的 client hints")
+	}
+
+	headers.ApplyAcceptCH(allAcceptCH)
+	expected := map[string]string{
+		"Sec-CH-UA-Platform-Version": `"10.0.0"`,
+		"Sec-CH-UA-Arch":             `"x86"`,
+		"Sec-CH-UA-Bitness":          `"64"`,
+		"Sec-CH-UA-WoW64":            "?0",
+		"Sec-CH-UA-Form-Factors":     `"Desktop"`,
+	}
+	m = headers.ToMap()
+	for name, value := range expected {
+		if m[name] != value {
+			t.Errorf("%s 应为 %s，实际为 %s", name, value, m[name])
+		}
+	}
+
+	// client hints 按 Chrome 的顺序排列在 sec-ch-ua 之后
+	names := orderedNames(headers)
+	if indexOf(names, "sec-ch-ua") != 0 || indexOf(names, "sec-ch-ua-full-version-list") > indexOf(names, "sec-ch-ua-mobile") ||
+		indexOf(names, "sec-ch-ua-platform-version") != indexOf(names, "sec-ch-ua-platform")+1 {
+		t.Errorf("client hints 顺序不正确: %v", names)
+	}
+
+	// Clone 后仍然可以应用 Accept-CH
+	cloned := fingerprint.GenerateHeaders(fingerprint.BrowserChrome, ua, false).Clone()
+	cloned.ApplyAcceptCH("sec-ch-ua-bitness")
+	if cloned.SecCHUABitness != `"64"` {
+		t.Errorf("Clone 后应保留 client hints: %q", cloned.SecCHUABitness)
+	}
+}
+
+// TestClientHintsConsistentWithUserAgent 测试 client hints 与 User-Agent 一致
+func TestClientHintsConsistentWithUserAgent(t *testing.T) {
+	cases := []struct {
+		name      string
+		browser   fingerprint.BrowserType
+		ua        string
+		mobile    bool
+		expected  map[string]string
+		forbidden []string
+	}{
+		{
+			name:    "Android Chrome 使用 User-Agent 中的版本",
+			browser: fingerprint.BrowserChrome,
+			ua:      "Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Mobile Safari/537.36",
+			mobile:  true,
+			expected: map[string]string{
+				"Sec-CH-UA":                  `"Google Chrome";v="131", "Chromium";v="131", "Not_A Brand";v="24"`,
+				"Sec-CH-UA-Mobile":           "?1",
+				"Sec-CH-UA-Platform":         `"Android"`,
+				"Sec-CH-UA-Platform-Version": `"10.0.0"`,
+				"Sec-CH-UA-Model":            `"SM-G973F"`,
+				"Sec-CH-UA-Form-Factors":     `"Mobile"`,
+			},
+		},
+		{
+			name:    "macOS 的平台版本和架构",
+			browser: fingerprint.BrowserChrome,
+			ua:      "Mozilla/5.0 (Macintosh; Intel Mac OS X 14_0_0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
+			expected: map[string]string{
+				"Sec-CH-UA-Platform":         `"macOS"`,
+				"Sec-CH-UA-Platform-Version": `"14.0.0"`,
+				"Sec-CH-UA-Arch":             `"arm"`,
+			},
+		},
+		{
+			name:      "Chrome 124 之前不支持 Form-Factors",
+			browser:   fingerprint.BrowserChrome,
+			ua:        "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			expected:  map[string]string{"Sec-CH-UA-Platform": `"Linux"`, "Sec-CH-UA-Full-Version-List": `"Not_A Brand";v="8.0.0.0", "Chromium";v="120.0.6099.225", "Google Chrome";v="120.0.6099.225"`},
+			forbidden: []string{"Sec-CH-UA-Form-Factors"},
+		},
+		{
+			name:    "Opera 的品牌版本",
+			browser: fingerprint.BrowserOpera,
+			ua:      "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/104.0.0.0 Safari/537.36 OPR/90.0.0.0",
+			expected: map[string]string{
+				"Sec-CH-UA":                   `"Chromium";v="104", " Not A;Brand";v="99", "Opera";v="90"`,
+				"Sec-CH-UA-Full-Version-List": `"Chromium";v="104.0.5112.102", " Not A;Brand";v="99.0.0.0", "Opera";v="90.0.4480.84"`,
+			},
+		},
+		{
+			name:      "Firefox 不支持 client hints",
+			browser:   fingerprint.BrowserFirefox,
+			ua:        "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:135.0) Gecko/20100101 Firefox/135.0",
+			forbidden: []string{"Sec-CH-UA", "Sec-CH-UA-Full-Version-List", "Sec-CH-UA-Model"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			headers := fingerprint.GenerateHeaders(tc.browser, tc.ua, tc.mobile)
+			headers.ApplyAcceptCH(allAcceptCH)
+			m := headers.ToMap()
+			for name, value := range tc.expected {
+				if m[name] != value {
+					t.Errorf("%s 应为 %s，实际为 %s", name, value, m[name])
+				}
+			}
+			for _, name := range tc.forbidden {
+				if _, ok := m[name]; ok {
+					t.Errorf("不应发送 %s", name)
+				}
+			}
+		})
+	}
+}
+
+// TestClientHintsAndroidModel 测试 Sec-CH-UA-Model 使用 User-Agent 中的设备型号，精简 User-Agent 使用默认型号
+func TestClientHintsAndroidModel(t *testing.T) {
+	cases := map[string]string{
+		"Mozilla/5.0 (Linux; Android 13; Pixel 7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36":                        `"Pixel 7"`,
+		"Mozilla/5.0 (Linux; Android 13; SM-S908B Build/TP1A.220624.014) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36": `"SM-S908B"`,
+		"Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Mobile Safari/537.36":                              `"SM-G973F"`,
+	}
+	for ua, model := range cases {
+		headers := fingerprint.GenerateHeaders(fingerprint.BrowserChrome, ua, true)
+		headers.ApplyAcceptCH("Sec-CH-UA-Model")
+		if headers.SecCHUAModel != model {
+			t.Errorf("Sec-CH-UA-Model 应为 %s，实际为 %s: %s", model, headers.SecCHUAModel, ua)
+		}
+	}
+}
+
+// TestClientHintsFromFingerprint 测试随机指纹的 client hints 与 User-Agent 一致
+func TestClientHintsFromFingerprint(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		result, err := fingerprint.GetRandomFingerprintByBrowser("chrome")
+		if err != nil {
+			t.Fatalf("获取指纹失败: %v", err)
+		}
+		result.Headers.ApplyAcceptCH(allAcceptCH)
+		major := strings.SplitN(strings.SplitN(result.UserAgent, "Chrome/", 2)[1], ".", 2)[0]
+		if !strings.Contains(result.Headers.SecCHUAFullVersionList, `"Google Chrome";v="`+major+".") {
+			t.Errorf("Full-Version-List 与 User-Agent 版本不一致: %s / %s", result.Headers.SecCHUAFullVersionList, result.UserAgent)
+		}
+		if result.Headers.SecCHUAPlatformVersion == "" {
+			t.Errorf("应设置 Sec-CH-UA-Platform-Version: %s", result.UserAgent)
+		}
+	}
+}
+
+// TestGreaseBrands 测试 Sec-CH-UA 的 GREASE 品牌和品牌顺序与各版本浏览器实际发送的一致
+func TestGreaseBrands(t *testing.T) {
+	cases := []struct {
+		browser  fingerprint.BrowserType
+		ua       string
+		expected string
+	}{
+		{fingerprint.BrowserChrome, "Chrome/102.0.0.0", `" Not A;Brand";v="99", "Chromium";v="102", "Google Chrome";v="102"`},
+		{fingerprint.BrowserChrome, "Chrome/103.0.0.0", `".Not/A)Brand";v="99", "Google Chrome";v="103", "Chromium";v="103"`},
+		{fingerprint.BrowserChrome, "Chrome/104.0.0.0", `"Chromium";v="104", " Not A;Brand";v="99", "Google Chrome";v="104"`},
+		{fingerprint.BrowserChrome, "Chrome/105.0.0.0", `"Google Chrome";v="105", "Not)A;Brand";v="8", "Chromium";v="105"`},
+		{fingerprint.BrowserChrome, "Chrome/106.0.0.0", `"Chromium";v="106", "Google Chrome";v="106", "Not;A=Brand";v="99"`},
+		{fingerprint.BrowserChrome, "Chrome/107.0.0.0", `"Google Chrome";v="107", "Chromium";v="107", "Not=A?Brand";v="24"`},
+		{fingerprint.BrowserChrome, "Chrome/108.0.0.0", `"Not?A_Brand";v="8", "Chromium";v="108", "Google Chrome";v="108"`},
+		{fingerprint.BrowserChrome, "Chrome/109.0.0.0", `"Not_A Brand";v="99", "Google Chrome";v="109", "Chromium";v="109"`},
+		{fingerprint.BrowserChrome, "Chrome/110.0.0.0", `"Chromium";v="110", "Not A(Brand";v="24", "Google Chrome";v="110"`},
+		{fingerprint.BrowserChrome, "Chrome/116.0.0.0", `"Chromium";v="116", "Not)A;Brand";v="24", "Google Chrome";v="116"`},
+		{fingerprint.BrowserChrome, "Chrome/120.0.0.0", `"Not_A Brand";v="8", "Chromium";v="120", "Google Chrome";v="120"`},
+		{fingerprint.BrowserChrome, "Chrome/124.0.0.0", `"Chromium";v="124", "Google Chrome";v="124", "Not-A.Brand";v="99"`},
+		{fingerprint.BrowserChrome, "Chrome/130.0.0.0", `"Chromium";v="130", "Google Chrome";v="130", "Not?A_Brand";v="99"`},
+		{fingerprint.BrowserChrome, "Chrome/131.0.0.0", `"Google Chrome";v="131", "Chromium";v="131", "Not_A Brand";v="24"`},
+		{fingerprint.BrowserChrome, "Chrome/133.0.0.0", `"Not(A:Brand";v="99", "Google Chrome";v="133", "Chromium";v="133"`},
+		{fingerprint.BrowserOpera, "Chrome/105.0.0.0 Safari/537.36 OPR/91.0.0.0", `"Opera";v="91", "Not)A;Brand";v="8", "Chromium";v="105"`},
+		{fingerprint.BrowserEdge, "Chrome/131.0.0.0 Safari/537.36 Edg/131.0.0.0", `"Microsoft Edge";v="131", "Chromium";v="131", "Not_A Brand";v="24"`},
+	}
+
+	for _, tc := range cases {
+		t.Run(string(tc.browser)+" "+tc.ua, func(t *testing.T) {
+			headers := fingerprint.GenerateHeaders(tc.browser, "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) "+tc.ua, false)
+			if headers.SecCHUA != tc.expected {
+				t.Errorf("Sec-CH-UA 应为 %s，实际为 %s", tc.expected, headers.SecCHUA)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package fingerprint_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/vistone/fingerprint"
+	"github.com/vistone/fingerprint/profiles"
+)
+
+// TestCompatibleOS 测试浏览器与操作系统的兼容矩阵
+func TestCompatibleOS(t *testing.T) {
+	windows7 := fingerprint.NewOperatingSystem(profiles.PlatformWindows, "7", "x86", 64)
+	mojave := fingerprint.NewOperatingSystem(profiles.PlatformMacOS, "10.14.6", "x86", 64)
+	tests := []struct {
+		profile  string
+		os       fingerprint.OperatingSystem
+		expected bool
+	}{
+		{"safari_16_0", fingerprint.OSWindows10, false},
+		{"safari_16_0", fingerprint.OSLinux, false},
+		{"safari_16_0", fingerprint.OSMacOS12, true},
+		{"safari_16_0", fingerprint.OSMacOS13, true},
+		{"safari_16_0", fingerprint.OSMacOS14, false},
+		{"safari_15_6_1", fingerprint.OSMacOS12, true},
+		{"safari_15_6_1", fingerprint.OSMacOS13, false},
+		{"chrome_133", fingerprint.OSWindows11, true},
+		{"chrome_133", fingerprint.OSMacOS15, true},
+		{"chrome_133", fingerprint.OSLinux, true},
+		{"chrome_133", fingerprint.OSLinuxARM64, false},
+		{"chrome_133", windows7, false},
+		{"chrome_109", windows7, true},
+		{"chrome_133", mojave, false},
+		{"firefox_135", fingerprint.OSLinuxARM64, true},
+		{"firefox_102", windows7, true},
+		{"firefox_135", windows7, false},
+		{"safari_ios_18_0", fingerprint.OSWindows10, false},
+		{"safari_ios_18_0", fingerprint.OperatingSystem{Family: profiles.PlatformIOS}, true},
+		{"edge_android_131", fingerprint.OperatingSystem{Family: profiles.PlatformAndroid}, true},
+		{"edge_android_131", fingerprint.OSLinux, false},
+		{"chrome_133", fingerprint.OperatingSystem{}, true},
+	}
+	for _, tt := range tests {
+		profile := fingerprint.MappedTLSClients[tt.profile]
+		if actual := fingerprint.CompatibleOS(profile, tt.os); actual != tt.expected {
+			t.Errorf("CompatibleOS(%s, %q) = %v, 期望 %v", tt.profile, tt.os, actual, tt.expected)
+		}
+	}
+
+	systems := fingerprint.CompatibleOperatingSystems(fingerprint.MappedTLSClients["safari_16_0"])
+	if !reflect.DeepEqual(systems, []fingerprint.OperatingSystem{fingerprint.OSMacOS12, fingerprint.OSMacOS13}) {
+		t.Errorf("safari_16_0 兼容的操作系统: %v", systems)
+	}
+	if systems := fingerprint.CompatibleOperatingSystems(fingerprint.MappedTLSClients["safari_ios_18_0"]); len(systems) != 0 {
+		t.Errorf("移动端 profile 不使用操作系统: %v", systems)
+	}
+}
+
+// TestIncompatibleOSError 测试显式指定不可能的组合时返回 ErrIncompatibleOS
+func TestIncompatibleOSError(t *testing.T) {
+	var incompatible *fingerprint.ErrIncompatibleOS
+
+	_, err := fingerprint.GetUserAgentByProfileNameWithOS("safari_16_0", fingerprint.OSWindows10)
+	if !errors.As(err, &incompatible) || incompatible.Profile != "safari_16_0" || incompatible.OS != fingerprint.OSWindows10 {
+		t.Errorf("safari_16_0 搭配 Windows 应返回 ErrIncompatibleOS: %v", err)
+	}
+	if _, err := fingerprint.GetUserAgentByProfileNameWithOS("safari_ios_18_0", fingerprint.OSLinux); !errors.As(err, &incompatible) {
+		t.Errorf("iOS Safari 搭配 Linux 应返回 ErrIncompatibleOS: %v", err)
+	}
+	if _, err := fingerprint.GetRandomFingerprintByBrowserWithOS("safari", fingerprint.OSLinux); !errors.As(err, &incompatible) || incompatible.Browser != "safari" {
+		t.Errorf("Safari 搭配 Linux 应返回 ErrIncompatibleOS: %v", err)
+	}
+	if ua, err := fingerprint.GetUserAgentByProfileNameWithOS("safari_16_0", fingerprint.OSMacOS13); err != nil || !strings.Contains(ua, "Version/16.0") {
+		t.Errorf("safari_16_0 搭配 macOS 13 应成功: %s %v", ua, err)
+	}
+}
+
+// TestRandomSelectionCompatible 测试随机选择只产生兼容的浏览器和操作系统组合
+func TestRandomSelectionCompatible(t *testing.T) {
+	g := fingerprint.NewGenerator(3)
+	check := func(result *fingerprint.FingerprintResult) {
+		t.Helper()
+		metadata := result.Profile.Metadata()
+		if metadata.Browser == "safari" && !metadata.Mobile() && !strings.Contains(result.UserAgent, "Macintosh") {
+			t.Fatalf("桌面 Safari 只能搭配 macOS: %s", result.UserAgent)
+		}
+		if metadata.Browser == "safari" && metadata.MajorVersion == 15 && !metadata.Mobile() && result.OS != fingerprint.OSMacOS12 {
+			t.Fatalf("Safari 15 只能搭配 macOS 12 及以下: %v", result.OS)
+		}
+	}
+	for i := 0; i < 500; i++ {
+		result, err := g.GetRandomFingerprint()
+		if err != nil {
+			t.Fatal(err)
+		}
+		check(result)
+
+		result, err = g.GetRandomFingerprintByBrowser("safari")
+		if err != nil {
+			t.Fatal(err)
+		}
+		check(result)
+
+		result, err = g.SelectRandom(fingerprint.Query{Browser: "safari", Platform: "macos"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		check(result)
+	}
+
+	for i := 0; i < 100; i++ {
+		result, err := g.GetRandomFingerprintWithOS(fingerprint.OSLinux)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if metadata := result.Profile.Metadata(); metadata.Mobile() || metadata.Browser == "safari" {
+			t.Fatalf("Linux 上不应选中 %s", result.HelloClientID)
+		}
+	}
+
+	// 只给 Windows 权重时，桌面 Safari 仍然得到兼容的 macOS
+	weights := &fingerprint.Weights{
+		Profiles:         map[string]float64{"safari_16_0": 1},
+		OperatingSystems: map[fingerprint.OperatingSystem]float64{fingerprint.OSWindows10: 1},
+	}
+	g = fingerprint.NewGenerator(5, fingerprint.WithWeights(weights))
+	for i := 0; i < 20; i++ {
+		result, err := g.GetWeightedRandomFingerprint()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.OS != fingerprint.OSMacOS12 && result.OS != fingerprint.OSMacOS13 {
+			t.Fatalf("safari_16_0 应搭配 macOS 12 或 13: %v", result.OS)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package fingerprint_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/vistone/fingerprint"
+)
+
+// TestEdgeProfiles 测试 Edge profile 使用与同版本 Chrome 相同的 TLS 和 HTTP/2 指纹
+func TestEdgeProfiles(t *testing.T) {
+	if names := fingerprint.Select(fingerprint.Query{Browser: "edge"}); !reflect.DeepEqual(names, []string{"edge_131", "edge_133", "edge_android_131"}) {
+		t.Fatalf("Edge profile 不正确: %v", names)
+	}
+
+	for edge, chrome := range map[string]string{"edge_131": "chrome_131", "edge_133": "chrome_133", "edge_android_131": "chrome_131"} {
+		edgeProfile, chromeProfile := fingerprint.MappedTLSClients[edge], fingerprint.MappedTLSClients[chrome]
+		edgeJA3, err := edgeProfile.JA3()
+		if err != nil {
+			t.Fatalf("%s 计算 JA3 失败: %v", edge, err)
+		}
+		chromeJA3, _ := chromeProfile.JA3()
+		if edgeJA3 != chromeJA3 {
+			t.Errorf("%s 的 JA3 应与 %s 相同", edge, chrome)
+		}
+		if edgeProfile.AkamaiFingerprint() != chromeProfile.AkamaiFingerprint() {
+			t.Errorf("%s 的 HTTP/2 指纹应与 %s 相同: %s", edge, chrome, edgeProfile.AkamaiFingerprint())
+		}
+		if edgeProfile.GetClientHelloStr() == chromeProfile.GetClientHelloStr() {
+			t.Errorf("%s 应有独立的 ClientHelloStr", edge)
+		}
+	}
+}
+
+// TestEdgeFingerprint 测试 Edge 指纹的 User-Agent 和 Sec-CH-UA
+func TestEdgeFingerprint(t *testing.T) {
+	for i := 0; i < 30; i++ {
+		result, err := fingerprint.GetRandomFingerprintByBrowser("edge")
+		if err != nil {
+			t.Fatalf("GetRandomFingerprintByBrowser(edge) 失败: %v", err)
+		}
+		metadata := result.Profile.Metadata()
+		version := metadata.Version()
+		if !strings.Contains(result.UserAgent, "Edg/"+version+".0.0.0") && !strings.Contains(result.UserAgent, "EdgA/"+version+".0.0.0") {
+			t.Errorf("User-Agent 应包含 Edge 版本: %s", result.UserAgent)
+		}
+		if !strings.Contains(result.Headers.SecCHUA, `"Microsoft Edge";v="`+version+`"`) || !strings.Contains(result.Headers.SecCHUA, `"Chromium";v="`+version+`"`) {
+			t.Errorf("Sec-CH-UA 应包含 Microsoft Edge 品牌: %s", result.Headers.SecCHUA)
+		}
+		if metadata.Mobile() != (result.Headers.SecCHUAMobile == "?1") {
+			t.Errorf("Sec-CH-UA-Mobile 与设备类型不一致: %s", result.Headers.SecCHUAMobile)
+		}
+	}
+
+	ua, err := fingerprint.GetUserAgentByProfileName("edge_android_131")
+	if err != nil {
+		t.Fatal(err)
+	}
+	headers := fingerprint.GenerateHeaders(fingerprint.BrowserEdge, ua, true)
+	headers.ApplyAcceptCH("Sec-CH-UA-Full-Version-List")
+	if headers.SecCHUAPlatform != `"Android"` || !strings.Contains(headers.SecCHUAFullVersionList, `"Microsoft Edge";v="131.0.2903.112"`) {
+		t.Errorf("Android Edge 的 client hints 不正确: %s / %s", headers.SecCHUAPlatform, headers.SecCHUAFullVersionList)
+	}
+}
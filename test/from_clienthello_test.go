@@ -0,0 +1,130 @@
+package fingerprint_test
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+
+	tls "github.com/bogdanfinn/utls"
+	"github.com/vistone/fingerprint"
+	"github.com/vistone/fingerprint/profiles"
+)
+
+// captureClientHello 使用 profile 构造 ClientHello 并返回握手消息的原始字节（不含 TLS 记录头）
+func captureClientHello(t *testing.T, profile fingerprint.ClientProfile) []byte {
+	t.Helper()
+	spec, err := profile.GetClientHelloSpec()
+	if err != nil {
+		t.Fatalf("获取 ClientHelloSpec 失败: %v", err)
+	}
+
+	conn, peer := net.Pipe()
+	defer conn.Close()
+	defer peer.Close()
+
+	uconn := tls.UClient(conn, &tls.Config{ServerName: "example.com", OmitEmptyPsk: true}, tls.HelloCustom, false, false, false)
+	if err := uconn.ApplyPreset(&spec); err != nil {
+		t.Fatalf("应用 ClientHelloSpec 失败: %v", err)
+	}
+	if err := uconn.BuildHandshakeState(); err != nil {
+		t.Fatalf("构造 ClientHello 失败: %v", err)
+	}
+	return uconn.HandshakeState.Hello.Raw
+}
+
+// TestFromClientHelloBytesRoundTrip 测试由 ClientHello 字节构造的 profile 与原 profile 指纹一致
+func TestFromClientHelloBytesRoundTrip(t *testing.T) {
+	for _, name := range []string{"chrome_133", "chrome_120", "firefox_135", "safari_ios_18_0"} {
+		t.Run(name, func(t *testing.T) {
+			original := fingerprint.MappedTLSClients[name]
+			raw := captureClientHello(t, original)
+
+			profile, err := profiles.FromClientHelloBytes(raw, profiles.WithClientHelloHTTP2(original.AkamaiFingerprint()))
+			if err != nil {
+				t.Fatalf("FromClientHelloBytes 失败: %v", err)
+			}
+
+			expectedJA3, _ := original.JA3()
+			if got, _ := profile.JA3(); got != expectedJA3 {
+				t.Errorf("JA3 不一致\n期望: %s\n实际: %s", expectedJA3, got)
+			}
+			expectedJA4, _ := original.JA4R()
+			if got, _ := profile.JA4R(); got != expectedJA4 {
+				t.Errorf("JA4_r 不一致\n期望: %s\n实际: %s", expectedJA4, got)
+			}
+			if got := profile.AkamaiFingerprint(); got != original.AkamaiFingerprint() {
+				t.Errorf("Akamai 指纹不一致\n期望: %s\n实际: %s", original.AkamaiFingerprint(), got)
+			}
+		})
+	}
+}
+
+// TestFromClientHelloBytesExtensionContents 测试 JA3 不包含的扩展内容被完整保留
+func TestFromClientHelloBytesExtensionContents(t *testing.T) {
+	original := fingerprint.MappedTLSClients["chrome_133"]
+	raw := captureClientHello(t, original)
+
+	// 完整的 TLS 记录也应被接受
+	record := append([]byte{0x16, 0x03, 0x01, byte(len(raw) >> 8), byte(len(raw))}, raw...)
+	profile, err := profiles.FromClientHelloBytes(record, profiles.WithClientHelloName("Chrome", "133-captured"))
+	if err != nil {
+		t.Fatalf("FromClientHelloBytes 失败: %v", err)
+	}
+	if profile.GetClientHelloStr() != "Chrome-133-captured" {
+		t.Errorf("ClientHelloID 应为 Chrome-133-captured，实际为 %s", profile.GetClientHelloStr())
+	}
+
+	spec, err := profile.GetClientHelloSpec()
+	if err != nil {
+		t.Fatalf("SpecFactory 失败: %v", err)
+	}
+
+	var alps []string
+	var groups []tls.CurveID
+	var compression []tls.CertCompressionAlgo
+	for _, ext := range spec.Extensions {
+		switch e := ext.(type) {
+		case *tls.ApplicationSettingsExtensionNew:
+			alps = e.SupportedProtocols
+		case *tls.KeyShareExtension:
+			for _, share := range e.KeyShares {
+				groups = append(groups, share.Group)
+			}
+		case *tls.UtlsCompressCertExtension:
+			compression = e.Algorithms
+		}
+	}
+
+	if !reflect.DeepEqual(alps, []string{"h3", "h2"}) {
+		t.Errorf("ALPS 协议应为 [h3 h2]，实际为 %v", alps)
+	}
+	expectedGroups := []tls.CurveID{tls.CurveID(tls.GREASE_PLACEHOLDER), tls.X25519MLKEM768, tls.X25519}
+	if !reflect.DeepEqual(groups, expectedGroups) {
+		t.Errorf("key_share 曲线应为 %v，实际为 %v", expectedGroups, groups)
+	}
+	if !reflect.DeepEqual(compression, []tls.CertCompressionAlgo{tls.CertCompressionBrotli}) {
+		t.Errorf("证书压缩算法应为 brotli，实际为 %v", compression)
+	}
+}
+
+// TestFromClientHelloBytesMalformed 测试格式错误的输入
+func TestFromClientHelloBytesMalformed(t *testing.T) {
+	raw := captureClientHello(t, fingerprint.MappedTLSClients["chrome_133"])
+	cases := map[string][]byte{
+		"空数据":           nil,
+		"非握手记录":         {0x17, 0x03, 0x03, 0x00, 0x01, 0x00},
+		"数据被截断":         raw[:60],
+		"非 ClientHello": append([]byte{0x02}, raw[1:]...),
+	}
+
+	for name, input := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := profiles.FromClientHelloBytes(input)
+			var invalid *profiles.ErrInvalidFingerprint
+			if !errors.As(err, &invalid) {
+				t.Fatalf("应返回 *ErrInvalidFingerprint，实际为 %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package fingerprint_test
+
+import (
+	"errors"
+	"testing"
+
+	tls "github.com/bogdanfinn/utls"
+	"github.com/vistone/fingerprint"
+	"github.com/vistone/fingerprint/profiles"
+)
+
+// TestFromJA3RoundTrip 测试由已有 profile 的 JA3/Akamai 指纹构造的 profile 能得到相同的指纹
+func TestFromJA3RoundTrip(t *testing.T) {
+	for _, name := range []string{"chrome_133", "firefox_117", "safari_ios_18_0", "chrome_103"} {
+		t.Run(name, func(t *testing.T) {
+			original := fingerprint.MappedTLSClients[name]
+			ja3, err := original.JA3()
+			if err != nil {
+				t.Fatalf("计算 JA3 失败: %v", err)
+			}
+
+			profile, err := fingerprint.FromJA3(ja3, original.AkamaiFingerprint(), profiles.WithJA3GREASE())
+			if err != nil {
+				t.Fatalf("FromJA3 失败: %v", err)
+			}
+
+			got, err := profile.JA3()
+			if err != nil {
+				t.Fatalf("计算新 profile 的 JA3 失败: %v", err)
+			}
+			if got != ja3 {
+				t.Errorf("JA3 不一致\n期望: %s\n实际: %s", ja3, got)
+			}
+			if got := profile.AkamaiFingerprint(); got != original.AkamaiFingerprint() {
+				t.Errorf("Akamai 指纹不一致\n期望: %s\n实际: %s", original.AkamaiFingerprint(), got)
+			}
+			if _, err := profile.GetClientHelloSpec(); err != nil {
+				t.Errorf("SpecFactory 失败: %v", err)
+			}
+		})
+	}
+}
+
+// TestFromJA3UnknownExtension 测试未知扩展编号被保留为通用扩展
+func TestFromJA3UnknownExtension(t *testing.T) {
+	ja3 := "771,4865-4866,0-4660-10-11-43-51,29-23,0"
+	profile, err := profiles.FromJA3(ja3, "", profiles.WithJA3ClientHelloID("Capture", "1"))
+	if err != nil {
+		t.Fatalf("FromJA3 失败: %v", err)
+	}
+	if got, _ := profile.JA3(); got != ja3 {
+		t.Errorf("JA3 不一致\n期望: %s\n实际: %s", ja3, got)
+	}
+	if profile.GetClientHelloStr() != "Capture-1" {
+		t.Errorf("ClientHelloID 应为 Capture-1，实际为 %s", profile.GetClientHelloStr())
+	}
+	if len(profile.GetSettings()) != 0 || len(profile.GetPseudoHeaderOrder()) != 0 {
+		t.Errorf("h2 为空时不应设置 HTTP/2 参数")
+	}
+}
+
+// TestFromJA3Malformed 测试格式错误的输入
+func TestFromJA3Malformed(t *testing.T) {
+	validJA3 := "771,4865-4866,0-10-11,29-23,0"
+	cases := map[string][2]string{
+		"字段数量错误":    {"771,4865-4866,0-10-11,29-23", ""},
+		"版本非数字":     {"tls,4865,0,29,0", ""},
+		"密码套件非数字":   {"771,4865-abc,0,29,0", ""},
+		"扩展超出范围":    {"771,4865,70000,29,0", ""},
+		"密码套件为空":    {"771,,0,29,0", ""},
+		"点格式超出范围":   {"771,4865,0,29,300", ""},
+		"h2 字段数量错误": {validJA3, "1:65536|0|m,a,s,p"},
+		"h2 设置错误":   {validJA3, "1=65536|0|0|m,a,s,p"},
+		"h2 优先级错误":  {validJA3, "1:65536|0|3:0:0|m,a,s,p"},
+		"h2 伪头部错误":  {validJA3, "1:65536|0|0|m,x,s,p"},
+	}
+
+	for name, input := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := profiles.FromJA3(input[0], input[1])
+			var invalid *profiles.ErrInvalidFingerprint
+			if !errors.As(err, &invalid) {
+				t.Fatalf("应返回 *ErrInvalidFingerprint，实际为 %v", err)
+			}
+		})
+	}
+}
+
+// TestFromJA3TrailingGREASE 测试 Chrome 120 之后打乱扩展顺序、没有 padding 的 JA3 也包含首尾两个 GREASE 扩展
+func TestFromJA3TrailingGREASE(t *testing.T) {
+	ja3 := "771,4865-4866-4867-49195-49199-49196-49200-52393-52392-49171-49172-156-157-47-53,27-16-65037-11-13-0-51-45-17513-35-18-43-65281-10-5-23,4588-29-23-24,0"
+	profile, err := profiles.FromJA3(ja3, "", profiles.WithJA3GREASE())
+	if err != nil {
+		t.Fatalf("FromJA3 失败: %v", err)
+	}
+	spec, err := profile.GetClientHelloSpec()
+	if err != nil {
+		t.Fatalf("SpecFactory 失败: %v", err)
+	}
+
+	greaseCount := 0
+	for _, ext := range spec.Extensions {
+		if _, ok := ext.(*tls.UtlsGREASEExtension); ok {
+			greaseCount++
+		}
+	}
+	_, first := spec.Extensions[0].(*tls.UtlsGREASEExtension)
+	_, last := spec.Extensions[len(spec.Extensions)-1].(*tls.UtlsGREASEExtension)
+	if greaseCount != 2 || !first || !last {
+		t.Errorf("应在首尾各有一个 GREASE 扩展: 共 %d 个，首 %v，尾 %v", greaseCount, first, last)
+	}
+	if got, _ := profile.JA3(); got != ja3 {
+		t.Errorf("JA3 不一致\n期望: %s\n实际: %s", ja3, got)
+	}
+}
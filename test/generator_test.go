@@ -0,0 +1,143 @@
+package fingerprint_test
+
+import (
+	"math/rand"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/vistone/fingerprint"
+)
+
+// generateSession 使用 Generator 依次生成一组指纹
+func generateSession(t *testing.T, g *fingerprint.Generator) []*fingerprint.FingerprintResult {
+	t.Helper()
+	var results []*fingerprint.FingerprintResult
+	for i := 0; i < 10; i++ {
+		for _, generate := range []func() (*fingerprint.FingerprintResult, error){
+			g.GetRandomFingerprint,
+			g.GetWeightedRandomFingerprint,
+			func() (*fingerprint.FingerprintResult, error) { return g.GetRandomFingerprintByBrowser("firefox") },
+			func() (*fingerprint.FingerprintResult, error) {
+				return g.SelectRandom(fingerprint.Query{Browser: "chrome", Platform: "linux"})
+			},
+		} {
+			result, err := generate()
+			if err != nil {
+				t.Fatalf("生成指纹失败: %v", err)
+			}
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+// TestGeneratorDeterministic 测试相同种子得到相同的 FingerprintResult 序列
+func TestGeneratorDeterministic(t *testing.T) {
+	registry := fingerprint.DefaultRegistry.Clone()
+	weights := fingerprint.DefaultWeights()
+
+	first := generateSession(t, fingerprint.NewGenerator(42, fingerprint.WithRegistry(registry), fingerprint.WithWeights(weights)))
+	second := generateSession(t, fingerprint.NewGeneratorFromSource(rand.NewSource(42), fingerprint.WithRegistry(registry), fingerprint.WithWeights(weights)))
+	for i := range first {
+		a, b := first[i], second[i]
+		if a.HelloClientID != b.HelloClientID || a.UserAgent != b.UserAgent {
+			t.Fatalf("第 %d 个指纹不一致: %s %q != %s %q", i, a.HelloClientID, a.UserAgent, b.HelloClientID, b.UserAgent)
+		}
+		if !reflect.DeepEqual(a.Headers, b.Headers) {
+			t.Fatalf("第 %d 个指纹的 headers 不一致: %+v != %+v", i, a.Headers, b.Headers)
+		}
+	}
+
+	other := generateSession(t, fingerprint.NewGenerator(43, fingerprint.WithRegistry(registry), fingerprint.WithWeights(weights)))
+	same := true
+	for i := range first {
+		if first[i].UserAgent != other[i].UserAgent || first[i].Headers.AcceptLanguage != other[i].Headers.AcceptLanguage {
+			same = false
+			break
+		}
+	}
+	if same {
+		t.Errorf("不同种子应得到不同的指纹序列")
+	}
+}
+
+// TestGeneratorRandomOSAndLanguage 测试 RandomOS、RandomLanguage 和 GenerateHeaders 可复现
+func TestGeneratorRandomOSAndLanguage(t *testing.T) {
+	weights := fingerprint.DefaultWeights()
+	a := fingerprint.NewGenerator(7, fingerprint.WithWeights(weights))
+	b := fingerprint.NewGenerator(7, fingerprint.WithWeights(weights))
+
+	for i := 0; i < 50; i++ {
+		if osA, osB := a.RandomOS(), b.RandomOS(); osA != osB {
+			t.Fatalf("第 %d 次 RandomOS 不一致: %s != %s", i, osA, osB)
+		}
+		if langA, langB := a.RandomLanguage(), b.RandomLanguage(); !reflect.DeepEqual(langA, langB) {
+			t.Fatalf("第 %d 次 RandomLanguage 不一致: %s != %s", i, langA, langB)
+		}
+		headersA := a.GenerateHeaders(fingerprint.BrowserChrome, "", false)
+		headersB := b.GenerateHeaders(fingerprint.BrowserChrome, "", false)
+		if !reflect.DeepEqual(headersA, headersB) {
+			t.Fatalf("第 %d 次 GenerateHeaders 不一致", i)
+		}
+	}
+
+	// 只有一个语言有权重时总是选择该语言
+	g := fingerprint.NewGenerator(1, fingerprint.WithWeights(&fingerprint.Weights{
+		OperatingSystems: map[fingerprint.OperatingSystem]float64{fingerprint.OSLinux: 1},
+		Languages:        map[string]float64{"ja-JP,en": 1},
+	}))
+	if language := g.RandomLanguage(); !reflect.DeepEqual(language, []string{"ja-JP", "en"}) {
+		t.Errorf("RandomLanguage 应使用 Generator 的权重: %s", language)
+	}
+	if os := g.RandomOS(); os != fingerprint.OSLinux {
+		t.Errorf("RandomOS 应使用 Generator 的权重: %s", os)
+	}
+}
+
+// TestGeneratorRegistry 测试 Generator 只从指定注册表中选择 profile
+func TestGeneratorRegistry(t *testing.T) {
+	registry := fingerprint.NewRegistry()
+	if err := registry.Register("firefox_135", fingerprint.MappedTLSClients["firefox_135"]); err != nil {
+		t.Fatal(err)
+	}
+
+	g := fingerprint.NewGenerator(1, fingerprint.WithRegistry(registry))
+	for i := 0; i < 10; i++ {
+		result, err := g.GetRandomFingerprint()
+		if err != nil {
+			t.Fatalf("GetRandomFingerprint 失败: %v", err)
+		}
+		if result.HelloClientID != fingerprint.MappedTLSClients["firefox_135"].GetClientHelloStr() {
+			t.Fatalf("应只选择注册表中的 firefox_135，实际为 %s", result.HelloClientID)
+		}
+	}
+	if _, err := g.GetRandomFingerprintByBrowser("chrome"); err == nil {
+		t.Errorf("注册表中没有 Chrome profile 时应返回错误")
+	}
+	if _, err := fingerprint.NewGenerator(1, fingerprint.WithRegistry(fingerprint.NewRegistry())).GetRandomFingerprint(); err == nil {
+		t.Errorf("空注册表应返回错误")
+	}
+}
+
+// TestGeneratorConcurrent 测试多个 goroutine 同时使用同一个 Generator
+func TestGeneratorConcurrent(t *testing.T) {
+	g := fingerprint.NewGenerator(99)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				if _, err := g.GetRandomFingerprint(); err != nil {
+					t.Errorf("GetRandomFingerprint 失败: %v", err)
+					return
+				}
+				g.RandomOS()
+				g.RandomLanguage()
+			}
+		}()
+	}
+	wg.Wait()
+}
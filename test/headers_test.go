@@ -0,0 +1,114 @@
+package fingerprint_test
+
+import (
+	"strings"
+	"testing"
+
+	http "github.com/bogdanfinn/fhttp"
+	"github.com/vistone/fingerprint"
+)
+
+// orderedNames 返回 ToOrderedSlice 结果中的小写 header 名称
+func orderedNames(headers *fingerprint.HTTPHeaders) []string {
+	names := make([]string, 0)
+	for _, kv := range headers.ToOrderedSlice() {
+		names = append(names, strings.ToLower(kv[0]))
+	}
+	return names
+}
+
+// indexOf 返回 name 在 names 中的位置，不存在返回 -1
+func indexOf(names []string, name string) int {
+	for i, n := range names {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// TestHeaderOrderPerBrowser 测试各浏览器的 header 顺序
+func TestHeaderOrderPerBrowser(t *testing.T) {
+	chromeUA := "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/133.0.0.0 Safari/537.36"
+	chrome := orderedNames(fingerprint.GenerateHeaders(fingerprint.BrowserChrome, chromeUA, false))
+	if chrome[0] != "sec-ch-ua" {
+		t.Errorf("Chrome 第一个 header 应为 sec-ch-ua，实际为 %s", chrome[0])
+	}
+	if indexOf(chrome, "user-agent") > indexOf(chrome, "accept") {
+		t.Error("Chrome 的 user-agent 应在 accept 之前")
+	}
+	if chrome[len(chrome)-2] != "accept-language" || chrome[len(chrome)-1] != "priority" {
+		t.Errorf("Chrome 最后两个 header 应为 accept-language 和 priority，实际为 %v", chrome[len(chrome)-2:])
+	}
+
+	firefoxUA := "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:135.0) Gecko/20100101 Firefox/135.0"
+	firefox := orderedNames(fingerprint.GenerateHeaders(fingerprint.BrowserFirefox, firefoxUA, false))
+	if firefox[0] != "user-agent" {
+		t.Errorf("Firefox 第一个 header 应为 user-agent，实际为 %s", firefox[0])
+	}
+}
+
+// TestCustomHeaderPosition 测试自定义 header 的插入位置
+func TestCustomHeaderPosition(t *testing.T) {
+	ua := "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/133.0.0.0 Safari/537.36"
+	headers := fingerprint.GenerateHeaders(fingerprint.BrowserChrome, ua, false)
+	headers.Set("X-B", "b")
+	headers.Set("Cookie", "a=1")
+	headers.Set("X-A", "a")
+
+	names := orderedNames(headers)
+	// Cookie 在浏览器顺序表中，放在 accept-language 之后
+	if indexOf(names, "cookie") != indexOf(names, "accept-language")+1 {
+		t.Errorf("Cookie 应紧跟 accept-language，实际顺序: %v", names)
+	}
+	// 其他自定义 header 默认按设置顺序追加到末尾
+	if names[len(names)-2] != "x-b" || names[len(names)-1] != "x-a" {
+		t.Errorf("自定义 header 应按设置顺序追加到末尾，实际顺序: %v", names)
+	}
+
+	headers.CustomPosition = "User-Agent"
+	names = orderedNames(headers)
+	ua0 := indexOf(names, "user-agent")
+	if names[ua0+1] != "x-b" || names[ua0+2] != "x-a" {
+		t.Errorf("自定义 header 应插入到 user-agent 之后，实际顺序: %v", names)
+	}
+
+	// 删除后不再出现
+	headers.Set("X-B", "")
+	if indexOf(orderedNames(headers), "x-b") != -1 {
+		t.Error("删除的自定义 header 不应出现")
+	}
+}
+
+// TestApplyTo 测试将 headers 写入 fhttp 请求
+func TestApplyTo(t *testing.T) {
+	result, err := fingerprint.GetRandomFingerprintByBrowser("chrome")
+	if err != nil {
+		t.Fatalf("获取指纹失败: %v", err)
+	}
+	result.Headers.Set("Cookie", "session=1")
+
+	req, err := http.NewRequest(http.MethodGet, "https://example.com", nil)
+	if err != nil {
+		t.Fatalf("创建请求失败: %v", err)
+	}
+	result.Headers.ApplyTo(req)
+
+	if req.Header.Get("User-Agent") != result.UserAgent {
+		t.Error("User-Agent 未正确写入")
+	}
+	if req.Header.Get("Cookie") != "session=1" {
+		t.Error("Cookie 未正确写入")
+	}
+
+	order := req.Header[http.HeaderOrderKey]
+	if len(order) != len(result.Headers.ToOrderedSlice()) {
+		t.Errorf("HeaderOrderKey 长度不正确: %v", order)
+	}
+
+	pseudo := req.Header[http.PHeaderOrderKey]
+	expected := result.Profile.GetPseudoHeaderOrder()
+	if strings.Join(pseudo, ",") != strings.Join(expected, ",") {
+		t.Errorf("PHeaderOrderKey 应为 %v，实际为 %v", expected, pseudo)
+	}
+}
@@ -0,0 +1,93 @@
+package fingerprint_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vistone/fingerprint"
+)
+
+// profileHeaders 返回指定 profile 生成的指纹的 headers
+func profileHeaders(t *testing.T, name string) *fingerprint.HTTPHeaders {
+	t.Helper()
+	result, err := fingerprint.NewGenerator(1, fingerprint.WithRegistry(registryWith(t, name))).GetRandomFingerprint()
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	return result.Headers
+}
+
+// TestHeaderTablesPerVersion 测试 headers 与 profile 对应的浏览器版本一致
+func TestHeaderTablesPerVersion(t *testing.T) {
+	tests := []struct {
+		profile        string
+		acceptEncoding string
+		accept         string // 为空时不检查
+		fetchMode      string
+		fetchUser      string
+		upgrade        string
+		te             string
+	}{
+		{"chrome_103", "gzip, deflate, br", "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,image/apng,*/*;q=0.8,application/signed-exchange;v=b3;q=0.9", "navigate", "?1", "1", ""},
+		{"chrome_110", "gzip, deflate, br", "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,image/apng,*/*;q=0.8,application/signed-exchange;v=b3;q=0.7", "navigate", "?1", "1", ""},
+		{"chrome_120", "gzip, deflate, br", "", "navigate", "?1", "1", ""},
+		{"chrome_124", "gzip, deflate, br, zstd", "", "navigate", "?1", "1", ""},
+		{"opera_91", "gzip, deflate, br", "", "navigate", "?1", "1", ""},
+		{"edge_133", "gzip, deflate, br, zstd", "", "navigate", "?1", "1", ""},
+		{"firefox_102", "gzip, deflate, br", "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,*/*;q=0.8", "navigate", "?1", "1", "trailers"},
+		{"firefox_123", "gzip, deflate, br", "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,*/*;q=0.8", "navigate", "?1", "1", "trailers"},
+		{"firefox_135", "gzip, deflate, br, zstd", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", "navigate", "?1", "1", "trailers"},
+		{"safari_15_6_1", "gzip, deflate, br", "", "", "", "", ""},
+		{"safari_16_0", "gzip, deflate, br", "", "", "", "", ""},
+		{"safari_ios_17_0", "gzip, deflate, br", "", "navigate", "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.profile, func(t *testing.T) {
+			h := profileHeaders(t, tt.profile)
+			if h.AcceptEncoding != tt.acceptEncoding {
+				t.Errorf("Accept-Encoding = %q, 期望 %q", h.AcceptEncoding, tt.acceptEncoding)
+			}
+			if tt.accept != "" && h.Accept != tt.accept {
+				t.Errorf("Accept = %q, 期望 %q", h.Accept, tt.accept)
+			}
+			if h.SecFetchMode != tt.fetchMode || h.SecFetchUser != tt.fetchUser {
+				t.Errorf("Sec-Fetch-Mode = %q, Sec-Fetch-User = %q", h.SecFetchMode, h.SecFetchUser)
+			}
+			if h.UpgradeInsecureRequests != tt.upgrade || h.TE != tt.te {
+				t.Errorf("Upgrade-Insecure-Requests = %q, TE = %q", h.UpgradeInsecureRequests, h.TE)
+			}
+		})
+	}
+
+	// Firefox 的 TE 在最后发送
+	names := orderedNames(profileHeaders(t, "firefox_135"))
+	if names[len(names)-1] != "te" {
+		t.Errorf("Firefox 最后一个 header 应为 te: %v", names)
+	}
+}
+
+// TestHeaderTablesAllProfiles 测试所有 profile 只声明对应版本支持的编码
+func TestHeaderTablesAllProfiles(t *testing.T) {
+	for name, profile := range fingerprint.MappedTLSClients {
+		metadata := profile.Metadata()
+		h := profileHeaders(t, name)
+		if h.Accept == "" || h.AcceptEncoding == "" {
+			t.Errorf("%s 缺少 Accept 或 Accept-Encoding", name)
+		}
+		zstd := strings.Contains(h.AcceptEncoding, "zstd")
+		switch metadata.Browser {
+		case "chrome", "edge":
+			if zstd != (metadata.MajorVersion >= 123) {
+				t.Errorf("%s (Chrome %d) 的 Accept-Encoding 不正确: %s", name, metadata.MajorVersion, h.AcceptEncoding)
+			}
+		case "firefox":
+			if zstd != (metadata.MajorVersion >= 126) {
+				t.Errorf("%s (Firefox %d) 的 Accept-Encoding 不正确: %s", name, metadata.MajorVersion, h.AcceptEncoding)
+			}
+		case "safari", "opera":
+			if zstd {
+				t.Errorf("%s 不应声明 zstd: %s", name, h.AcceptEncoding)
+			}
+		}
+	}
+}
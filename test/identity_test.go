@@ -0,0 +1,263 @@
+package fingerprint_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vistone/fingerprint"
+)
+
+// fakeClock 可手动调整的时钟
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// getIdentity 返回 key 对应的指纹，出错时测试失败
+func getIdentity(t *testing.T, store *fingerprint.IdentityStore, key string) *fingerprint.FingerprintResult {
+	t.Helper()
+	result, err := store.Get(key)
+	if err != nil {
+		t.Fatalf("Get(%q) 失败: %v", key, err)
+	}
+	return result
+}
+
+// assertSameIdentity 检查两个指纹是否为同一个身份
+func assertSameIdentity(t *testing.T, a, b *fingerprint.FingerprintResult) {
+	t.Helper()
+	if a == nil || b == nil {
+		t.Fatalf("指纹不应为 nil")
+	}
+	if a.HelloClientID != b.HelloClientID || a.UserAgent != b.UserAgent || !reflect.DeepEqual(a.Headers, b.Headers) {
+		t.Fatalf("应为同一个身份: %s %q != %s %q", a.HelloClientID, a.UserAgent, b.HelloClientID, b.UserAgent)
+	}
+	if a.Country != b.Country || !reflect.DeepEqual(a.Timezone, b.Timezone) {
+		t.Fatalf("应为同一个身份: 国家/地区 %q %v != %q %v", a.Country, a.Timezone, b.Country, b.Timezone)
+	}
+}
+
+// TestIdentityStoreStable 测试同一个 key 总是得到相同的指纹，且推导结果与存储无关
+func TestIdentityStoreStable(t *testing.T) {
+	weights := fingerprint.DefaultWeights()
+	store := fingerprint.NewIdentityStore(fingerprint.WithIdentityWeights(weights))
+
+	first := getIdentity(t, store, "account-1")
+	for i := 0; i < 5; i++ {
+		assertSameIdentity(t, first, getIdentity(t, store, "account-1"))
+	}
+	if first.Headers.AcceptLanguage == "" || first.Headers.SecFetchMode == "" {
+		t.Errorf("指纹应包含完整的 headers: %+v", first.Headers)
+	}
+
+	// 新的存储中相同 key 推导出相同的身份
+	assertSameIdentity(t, first, getIdentity(t, fingerprint.NewIdentityStore(fingerprint.WithIdentityWeights(weights)), "account-1"))
+
+	// 不同 key 或不同种子得到不同的身份
+	distinct := make(map[string]bool)
+	for _, key := range []string{"account-1", "account-2", "account-3", "account-4", "account-5"} {
+		result := getIdentity(t, store, key)
+		distinct[result.HelloClientID+result.UserAgent+result.Headers.AcceptLanguage] = true
+	}
+	if len(distinct) < 2 {
+		t.Errorf("不同 key 应得到不同的身份")
+	}
+	seeded := fingerprint.NewIdentityStore(fingerprint.WithIdentityWeights(weights), fingerprint.WithIdentitySeed(1))
+	different := false
+	for _, key := range []string{"account-1", "account-2", "account-3", "account-4", "account-5"} {
+		a, b := getIdentity(t, store, key), getIdentity(t, seeded, key)
+		if a.UserAgent != b.UserAgent || a.Headers.AcceptLanguage != b.Headers.AcceptLanguage {
+			different = true
+		}
+	}
+	if !different {
+		t.Errorf("不同种子应得到不同的身份")
+	}
+
+	if result, err := store.Get(""); err == nil || result != nil {
+		t.Errorf("key 为空时应返回错误")
+	}
+
+	// 没有可选择的 profile 时返回错误，而不是 nil 指纹
+	empty := fingerprint.NewIdentityStore(fingerprint.WithIdentityRegistry(fingerprint.NewRegistry()))
+	if result, err := empty.Get("account-1"); err == nil || result != nil {
+		t.Errorf("无法推导身份时应返回错误，实际为 %v, %v", result, err)
+	}
+}
+
+// TestIdentityStoreTTL 测试身份过期后轮换
+func TestIdentityStoreTTL(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	store := fingerprint.NewIdentityStore(
+		fingerprint.WithIdentityTTL(time.Hour),
+		fingerprint.WithIdentityClock(clock.Now),
+		fingerprint.WithIdentityWeights(fingerprint.DefaultWeights()),
+	)
+
+	first := getIdentity(t, store, "account")
+	clock.Advance(59 * time.Minute)
+	assertSameIdentity(t, first, getIdentity(t, store, "account"))
+
+	clock.Advance(time.Minute)
+	getIdentity(t, store, "account")
+	identity, ok, err := store.Identity("account")
+	if err != nil || !ok {
+		t.Fatalf("应保存身份: %v", err)
+	}
+	if identity.Generation != 1 || !identity.CreatedAt.Equal(clock.Now()) {
+		t.Errorf("过期后应轮换为第 1 代身份: %+v", identity)
+	}
+
+	if _, err := store.Rotate("account"); err != nil {
+		t.Fatalf("Rotate 失败: %v", err)
+	}
+	if identity, _, _ := store.Identity("account"); identity.Generation != 2 {
+		t.Errorf("Rotate 后应为第 2 代身份: %+v", identity)
+	}
+
+	// 删除后重新推导第一代身份
+	if err := store.Delete("account"); err != nil {
+		t.Fatal(err)
+	}
+	assertSameIdentity(t, first, getIdentity(t, store, "account"))
+}
+
+// TestIdentityStoreRemovedProfile 测试保存的 profile 不在注册表中时重新推导
+func TestIdentityStoreRemovedProfile(t *testing.T) {
+	registry := fingerprint.DefaultRegistry.Clone()
+	store := fingerprint.NewIdentityStore(fingerprint.WithIdentityRegistry(registry))
+
+	first := getIdentity(t, store, "account")
+	identity, _, _ := store.Identity("account")
+	registry.Unregister(identity.Profile)
+
+	second := getIdentity(t, store, "account")
+	if second.HelloClientID == first.HelloClientID {
+		t.Fatalf("profile 被移除后应选择其他 profile")
+	}
+	if renewed, _, _ := store.Identity("account"); renewed.Profile == identity.Profile {
+		t.Errorf("应保存新的身份: %+v", renewed)
+	}
+}
+
+// TestFileIdentityStorage 测试身份保存到 JSON 文件并在重新打开后保持不变
+func TestFileIdentityStorage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "identities.json")
+	storage, err := fingerprint.NewFileIdentityStorage(path)
+	if err != nil {
+		t.Fatalf("NewFileIdentityStorage 失败: %v", err)
+	}
+
+	// 使用只有一个 profile 权重的存储生成身份，重新打开后改用默认权重也应保持不变
+	store := fingerprint.NewIdentityStore(
+		fingerprint.WithIdentityStorage(storage),
+		fingerprint.WithIdentityWeights(&fingerprint.Weights{Profiles: map[string]float64{"safari_ios_18_0": 1}}),
+	)
+	first := getIdentity(t, store, "account")
+	if first.HelloClientID != fingerprint.MappedTLSClients["safari_ios_18_0"].GetClientHelloStr() {
+		t.Fatalf("应选择 safari_ios_18_0，实际为 %s", first.HelloClientID)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("应创建身份文件: %v", err)
+	}
+
+	reopened, err := fingerprint.NewFileIdentityStorage(path)
+	if err != nil {
+		t.Fatalf("重新打开失败: %v", err)
+	}
+	assertSameIdentity(t, first, getIdentity(t, fingerprint.NewIdentityStore(fingerprint.WithIdentityStorage(reopened)), "account"))
+
+	if err := reopened.Delete("account"); err != nil {
+		t.Fatal(err)
+	}
+	again, err := fingerprint.NewFileIdentityStorage(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, _ := again.Load("account"); ok {
+		t.Errorf("删除后文件中不应再有该身份")
+	}
+
+	if err := os.WriteFile(path, []byte("{invalid"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fingerprint.NewFileIdentityStorage(path); err == nil {
+		t.Errorf("文件格式错误时应返回错误")
+	}
+}
+
+// TestIdentityStorePersona 测试按 Persona 推导的国家/地区和时区与身份一起保存，重新打开存储后保持不变
+func TestIdentityStorePersona(t *testing.T) {
+	persona, err := fingerprint.NewPersona("DE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "identities.json")
+	storage, err := fingerprint.NewFileIdentityStorage(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	weights := fingerprint.DefaultWeights()
+	store := fingerprint.NewIdentityStore(
+		fingerprint.WithIdentityStorage(storage),
+		fingerprint.WithIdentityWeights(weights),
+		fingerprint.WithIdentityPersona(persona),
+	)
+
+	first := getIdentity(t, store, "account")
+	if first.Country != "DE" || first.Timezone == nil || first.Timezone.Name != "Europe/Berlin" {
+		t.Fatalf("应使用 Persona 的国家/地区和时区: %q %v", first.Country, first.Timezone)
+	}
+	identity, _, _ := store.Identity("account")
+	if identity.Country != first.Country || identity.Timezone == nil || *identity.Timezone != *first.Timezone {
+		t.Errorf("国家/地区和时区应与身份一起保存: %+v", identity)
+	}
+	assertSameIdentity(t, first, getIdentity(t, store, "account"))
+
+	// 重新打开的存储不使用 Persona，仍返回保存的国家/地区和时区
+	reopened, err := fingerprint.NewFileIdentityStorage(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSameIdentity(t, first, getIdentity(t, fingerprint.NewIdentityStore(fingerprint.WithIdentityStorage(reopened)), "account"))
+
+	// 新的存储中相同 key 推导出相同的身份
+	assertSameIdentity(t, first, getIdentity(t, fingerprint.NewIdentityStore(fingerprint.WithIdentityWeights(weights), fingerprint.WithIdentityPersona(persona)), "account"))
+}
+
+// TestIdentityStoreConcurrent 测试并发访问同一个 key
+func TestIdentityStoreConcurrent(t *testing.T) {
+	store := fingerprint.NewIdentityStore(fingerprint.WithIdentityWeights(fingerprint.DefaultWeights()))
+	expected := getIdentity(t, store, "shared")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				if result, err := store.Get("shared"); err != nil || result.UserAgent != expected.UserAgent {
+					t.Errorf("并发访问应得到相同的身份")
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -85,7 +85,7 @@ func TestGetRandomFingerprintWithOSIntegration(t *testing.T) {
 	}
 
 	for _, os := range oses {
-		t.Run(string(os), func(t *testing.T) {
+		t.Run(os.String(), func(t *testing.T) {
 			result, err := fingerprint.GetRandomFingerprintWithOS(os)
 			if err != nil {
 				t.Fatalf("获取指纹失败: %v", err)
@@ -372,8 +372,8 @@ func TestAllProfilesWithUserAgent(t *testing.T) {
 				return
 			}
 
-			// 验证 User-Agent 格式
-			if len(ua) < 20 {
+			// 验证 User-Agent 格式，应用 profile 使用网络库的短 User-Agent（如 okhttp/4.12.0），见 TestAppProfileUserAgent
+			if len(ua) < 20 && fingerprint.MappedTLSClients[name].Metadata().App == "" {
 				t.Errorf("Profile %s: User-Agent 格式可能不正确: %s", name, ua)
 				failCount++
 				return
@@ -0,0 +1,115 @@
+package fingerprint_test
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	tls "github.com/bogdanfinn/utls"
+	"github.com/vistone/fingerprint"
+	"github.com/vistone/fingerprint/profiles"
+)
+
+// TestJA3Golden 测试已知浏览器的 JA3 值
+func TestJA3Golden(t *testing.T) {
+	cases := map[string]struct {
+		ja3  string
+		hash string
+	}{
+		"chrome_103": {
+			ja3:  "771,4865-4866-4867-49195-49199-49196-49200-52393-52392-49171-49172-156-157-47-53,0-23-65281-10-11-35-16-5-13-18-51-45-43-27-17513-21,29-23-24,0",
+			hash: "cd08e31494f9531f560d64c695473da9",
+		},
+		"firefox_102": {
+			ja3:  "771,4865-4867-4866-49195-49199-52393-52392-49196-49200-49162-49161-49171-49172-156-157-47-53,0-23-65281-10-11-35-16-5-34-51-43-13-45-28-21,29-23-24-25-256-257,0",
+			hash: "579ccef312d18482fc42e2b822ca2430",
+		},
+		"chrome_133": {
+			ja3:  "771,4865-4866-4867-49195-49199-49196-49200-52393-52392-49171-49172-156-157-47-53,35-13-17613-51-18-11-43-5-16-0-65037-27-10-45-23-65281,4588-29-23-24,0",
+			hash: "74e530e488a43fddd78be75918be78c7",
+		},
+	}
+
+	for name, expected := range cases {
+		t.Run(name, func(t *testing.T) {
+			profile := fingerprint.MappedTLSClients[name]
+			ja3, err := profile.JA3()
+			if err != nil {
+				t.Fatalf("计算 JA3 失败: %v", err)
+			}
+			if ja3 != expected.ja3 {
+				t.Errorf("JA3 不匹配\n期望: %s\n实际: %s", expected.ja3, ja3)
+			}
+
+			hash, err := profile.JA3Hash()
+			if err != nil {
+				t.Fatalf("计算 JA3 哈希失败: %v", err)
+			}
+			if hash != expected.hash {
+				t.Errorf("JA3 哈希应为 %s，实际为 %s", expected.hash, hash)
+			}
+		})
+	}
+}
+
+// TestJA3AllProfiles 测试所有 profile 都能计算 JA3，且不包含 GREASE 值
+func TestJA3AllProfiles(t *testing.T) {
+	for name, profile := range fingerprint.MappedTLSClients {
+		t.Run(name, func(t *testing.T) {
+			ja3, err := profile.JA3()
+			if err != nil {
+				t.Fatalf("计算 JA3 失败: %v", err)
+			}
+
+			fields := strings.Split(ja3, ",")
+			if len(fields) != 5 {
+				t.Fatalf("JA3 应包含 5 个字段: %s", ja3)
+			}
+			for _, field := range fields[1:4] {
+				if field == "" {
+					continue
+				}
+				for _, value := range strings.Split(field, "-") {
+					v, err := strconv.Atoi(value)
+					if err != nil {
+						t.Fatalf("JA3 字段包含非数字值 %q: %s", value, ja3)
+					}
+					if v&0x0f0f == 0x0a0a && v>>8 == v&0xff {
+						t.Errorf("JA3 不应包含 GREASE 值 %d: %s", v, ja3)
+					}
+				}
+			}
+
+			hash, _ := profile.JA3Hash()
+			sum := md5.Sum([]byte(ja3))
+			if hash != hex.EncodeToString(sum[:]) {
+				t.Errorf("JA3Hash 与 JA3 的 MD5 不一致")
+			}
+		})
+	}
+}
+
+// TestSpecFactoryError 测试 SpecFactory 的错误会返回给 JA3、JA4 和序列化，而不是得到空结果
+func TestSpecFactoryError(t *testing.T) {
+	errFactory := errors.New("spec factory failed")
+	id := tls.ClientHelloID{
+		Client:      "Broken",
+		Version:     "1",
+		SpecFactory: func() (tls.ClientHelloSpec, error) { return tls.ClientHelloSpec{}, errFactory },
+	}
+	profile := profiles.NewClientProfile(id, nil, nil, nil, 0, nil, nil)
+
+	if ja3, err := profile.JA3(); !errors.Is(err, errFactory) || ja3 != "" {
+		t.Errorf("JA3 应返回 SpecFactory 的错误，实际为 %q, %v", ja3, err)
+	}
+	if ja4, err := profile.JA4(); !errors.Is(err, errFactory) || ja4 != "" {
+		t.Errorf("JA4 应返回 SpecFactory 的错误，实际为 %q, %v", ja4, err)
+	}
+	if _, err := json.Marshal(profile); !errors.Is(err, errFactory) {
+		t.Errorf("序列化应返回 SpecFactory 的错误，实际为 %v", err)
+	}
+}
@@ -0,0 +1,143 @@
+package fingerprint_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vistone/fingerprint"
+)
+
+// ja4Golden 所有 profile 的 JA4 值
+// Chrome 120/131、Firefox 102、Safari 等与真实浏览器抓包的 JA4 一致
+var ja4Golden = map[string]string{
+	"chrome_103":             "t13d1516h2_8daaf6152771_e5627efa2ab1",
+	"chrome_104":             "t13d1516h2_8daaf6152771_e5627efa2ab1",
+	"chrome_105":             "t13d1516h2_8daaf6152771_e5627efa2ab1",
+	"chrome_106":             "t13d1516h2_8daaf6152771_e5627efa2ab1",
+	"chrome_107":             "t13d1516h2_8daaf6152771_e5627efa2ab1",
+	"chrome_108":             "t13d1516h2_8daaf6152771_e5627efa2ab1",
+	"chrome_109":             "t13d1516h2_8daaf6152771_e5627efa2ab1",
+	"chrome_110":             "t13d1516h2_8daaf6152771_e5627efa2ab1",
+	"chrome_111":             "t13d1516h2_8daaf6152771_e5627efa2ab1",
+	"chrome_112":             "t13d1516h2_8daaf6152771_e5627efa2ab1",
+	"chrome_116_PSK":         "t13d1517h2_8daaf6152771_6cdcb247c39b",
+	"chrome_116_PSK_PQ":      "t13d1517h2_8daaf6152771_6cdcb247c39b",
+	"chrome_117":             "t13d1516h2_8daaf6152771_e5627efa2ab1",
+	"chrome_120":             "t13d1516h2_8daaf6152771_02713d6af862",
+	"chrome_124":             "t13d1516h2_8daaf6152771_02713d6af862",
+	"chrome_130_PSK":         "t13d1517h2_8daaf6152771_b0da82dd1658",
+	"chrome_131":             "t13d1516h2_8daaf6152771_02713d6af862",
+	"chrome_131_PSK":         "t13d1517h2_8daaf6152771_b0da82dd1658",
+	"chrome_133":             "t13d1516h3_8daaf6152771_d8a2da3f94cd",
+	"chrome_133_PSK":         "t13d1517h3_8daaf6152771_b6f405a00624",
+	"cloudflare_custom":      "t12d0508h1_be41622fbb2f_3c5a66c06c35",
+	"confirmed_android":      "t12d1209h2_d34a8e72043a_b39be8c56a14",
+	"confirmed_android_2":    "t12d1210h2_d34a8e72043a_f88f2b2eb673",
+	"confirmed_ios":          "t13d1314h2_f57a46bbacb6_14788d8d241b",
+	"edge_131":               "t13d1516h2_8daaf6152771_02713d6af862",
+	"edge_133":               "t13d1516h3_8daaf6152771_d8a2da3f94cd",
+	"edge_android_131":       "t13d1516h2_8daaf6152771_02713d6af862",
+	"firefox_102":            "t13d1715h2_5b57614c22b0_3d5424432f57",
+	"firefox_104":            "t13d1715h2_5b57614c22b0_3d5424432f57",
+	"firefox_105":            "t13d1715h2_5b57614c22b0_3d5424432f57",
+	"firefox_106":            "t13d1715h2_5b57614c22b0_3d5424432f57",
+	"firefox_108":            "t13d1715h2_5b57614c22b0_3d5424432f57",
+	"firefox_110":            "t13d1713h2_5b57614c22b0_f81080dfc557",
+	"firefox_117":            "t13d1715h2_5b57614c22b0_3d5424432f57",
+	"firefox_120":            "t13d1713h2_5b57614c22b0_748f4c70de1c",
+	"firefox_123":            "t13d1715h2_5b57614c22b0_5c2c66f702b0",
+	"firefox_132":            "t13d1714h2_5b57614c22b0_3dd24b5ebec4",
+	"firefox_133":            "t13d1714h2_5b57614c22b0_3dd24b5ebec4",
+	"firefox_135":            "t13d1715h2_5b57614c22b0_a54fffd0eb61",
+	"mesh_android":           "t13d1516h2_8daaf6152771_e5627efa2ab1",
+	"mesh_android_2":         "t12d1409h1_c866b44c5a26_b39be8c56a14",
+	"mesh_ios":               "t13d1314h2_f57a46bbacb6_14788d8d241b",
+	"mesh_ios_2":             "t13d1714h2_0633f72d41ca_14788d8d241b",
+	"mms_ios":                "t13d181000_e8a523a41297_78e6aca7449b",
+	"mms_ios_2":              "t13d181000_e8a523a41297_78e6aca7449b",
+	"mms_ios_3":              "t13d1314h2_f57a46bbacb6_2a6581477f52",
+	"nike_android_mobile":    "t13d1513h2_8daaf6152771_eca864cca44a",
+	"nike_ios_mobile":        "t13d1314h2_f57a46bbacb6_14788d8d241b",
+	"okhttp4_android_10":     "t13d1513h2_8daaf6152771_eca864cca44a",
+	"okhttp4_android_11":     "t13d1513h2_8daaf6152771_eca864cca44a",
+	"okhttp4_android_12":     "t13d1513h2_8daaf6152771_eca864cca44a",
+	"okhttp4_android_13":     "t13d1513h2_8daaf6152771_eca864cca44a",
+	"okhttp4_android_7":      "t12d1209h2_d34a8e72043a_17c8ccb8ce8c",
+	"okhttp4_android_8":      "t12d1210h2_d34a8e72043a_6d297d35caae",
+	"okhttp4_android_9":      "t12d1210h2_d34a8e72043a_f88f2b2eb673",
+	"opera_89":               "t13d1516h2_8daaf6152771_e5627efa2ab1",
+	"opera_90":               "t13d1516h2_8daaf6152771_e5627efa2ab1",
+	"opera_91":               "t13d1516h2_8daaf6152771_e5627efa2ab1",
+	"safari_15_6_1":          "t13d2014h2_a09f3c656075_14788d8d241b",
+	"safari_16_0":            "t13d2014h2_a09f3c656075_14788d8d241b",
+	"safari_ios_15_5":        "t13d2014h2_a09f3c656075_14788d8d241b",
+	"safari_ios_15_6":        "t13d2014h2_a09f3c656075_14788d8d241b",
+	"safari_ios_16_0":        "t13d2014h2_a09f3c656075_14788d8d241b",
+	"safari_ios_17_0":        "t13d2014h2_a09f3c656075_14788d8d241b",
+	"safari_ios_18_0":        "t13d2014h2_a09f3c656075_14788d8d241b",
+	"safari_ios_18_5":        "t13d2014h2_a09f3c656075_e42f34c56612",
+	"safari_ipad_15_6":       "t13d2014h2_a09f3c656075_14788d8d241b",
+	"zalando_android_mobile": "t13d1514h2_8daaf6152771_43ade6aba3df",
+	"zalando_ios_mobile":     "t13d1314h2_f57a46bbacb6_14788d8d241b",
+}
+
+// TestJA4Golden 测试 MappedTLSClients 中每个 profile 的 JA4 值
+func TestJA4Golden(t *testing.T) {
+	for name, profile := range fingerprint.MappedTLSClients {
+		t.Run(name, func(t *testing.T) {
+			expected, ok := ja4Golden[name]
+			if !ok {
+				t.Fatalf("profile %s 缺少 JA4 golden 值", name)
+			}
+			ja4, err := profile.JA4()
+			if err != nil {
+				t.Fatalf("计算 JA4 失败: %v", err)
+			}
+			if ja4 != expected {
+				t.Errorf("JA4 应为 %s，实际为 %s", expected, ja4)
+			}
+		})
+	}
+}
+
+// TestJA4R 测试 JA4_r 原始格式
+func TestJA4R(t *testing.T) {
+	raw, err := fingerprint.MappedTLSClients["chrome_133"].JA4R()
+	if err != nil {
+		t.Fatalf("计算 JA4_r 失败: %v", err)
+	}
+
+	expected := "t13d1516h3_002f,0035,009c,009d,1301,1302,1303,c013,c014,c02b,c02c,c02f,c030,cca8,cca9_" +
+		"0005,000a,000b,000d,0012,0017,001b,0023,002b,002d,0033,44cd,fe0d,ff01_" +
+		"0403,0804,0401,0503,0805,0501,0806,0601"
+	if raw != expected {
+		t.Errorf("JA4_r 不匹配\n期望: %s\n实际: %s", expected, raw)
+	}
+
+	// JA4_r 中不应出现 SNI（0000）和 ALPN（0010）
+	extensions := strings.Split(raw, "_")[2]
+	for _, ext := range strings.Split(extensions, ",") {
+		if ext == "0000" || ext == "0010" {
+			t.Errorf("JA4_r 扩展列表不应包含 %s", ext)
+		}
+	}
+}
+
+// TestJA4PSK 测试 PSK 扩展计入扩展数量，GREASE 与 ECH 的处理
+func TestJA4PSK(t *testing.T) {
+	plain, _ := fingerprint.MappedTLSClients["chrome_133"].JA4R()
+	psk, _ := fingerprint.MappedTLSClients["chrome_133_PSK"].JA4R()
+
+	if !strings.HasPrefix(plain, "t13d1516") || !strings.HasPrefix(psk, "t13d1517") {
+		t.Errorf("PSK profile 应多计入一个扩展: %s / %s", plain[:10], psk[:10])
+	}
+	if !strings.Contains(psk, ",0029,") {
+		t.Error("PSK profile 的 JA4_r 应包含 0029 扩展")
+	}
+	if !strings.Contains(psk, ",fe0d,") {
+		t.Error("BoringGREASEECH 应作为 ECH 扩展（fe0d）计入")
+	}
+	if strings.Contains(psk, "0a0a") {
+		t.Error("JA4_r 不应包含 GREASE 值")
+	}
+}
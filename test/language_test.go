@@ -0,0 +1,97 @@
+package fingerprint_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/vistone/fingerprint"
+)
+
+// TestFormatAcceptLanguage 测试各浏览器的 Accept-Language 格式
+func TestFormatAcceptLanguage(t *testing.T) {
+	firefoxUA := "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:135.0) Gecko/20100101 Firefox/135.0"
+	safariUA := "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/18.0 Safari/605.1.15"
+	tests := []struct {
+		browser  fingerprint.BrowserType
+		ua       string
+		locales  []string
+		expected string
+	}{
+		{fingerprint.BrowserChrome, requestKindChromeUA, []string{"en-US"}, "en-US,en;q=0.9"},
+		{fingerprint.BrowserChrome, requestKindChromeUA, []string{"zh-CN", "en"}, "zh-CN,zh;q=0.9,en;q=0.8"},
+		{fingerprint.BrowserChrome, requestKindChromeUA, []string{"de-CH", "fr", "en"}, "de-CH,de;q=0.9,fr;q=0.8,en;q=0.7"},
+		{fingerprint.BrowserChrome, requestKindChromeUA, []string{"en-US", "en-GB"}, "en-US,en-GB;q=0.9,en;q=0.8"},
+		{fingerprint.BrowserChrome, requestKindChromeUA, []string{"uk-UA", "ru", "en-US"}, "uk-UA,uk;q=0.9,ru;q=0.8,en-US;q=0.7,en;q=0.6"},
+		{fingerprint.BrowserEdge, requestKindChromeUA + " Edg/133.0.0.0", []string{"fr-CA", "en-CA"}, "fr-CA,fr;q=0.9,en-CA;q=0.8,en;q=0.7"},
+		{fingerprint.BrowserFirefox, firefoxUA, []string{"en-US", "en"}, "en-US,en;q=0.5"},
+		{fingerprint.BrowserFirefox, firefoxUA, []string{"de", "en-US", "en"}, "de,en-US;q=0.7,en;q=0.3"},
+		{fingerprint.BrowserFirefox, firefoxUA, []string{"fr-FR", "fr", "en-US", "en"}, "fr-FR,fr;q=0.8,en-US;q=0.5,en;q=0.3"},
+		{fingerprint.BrowserSafari, safariUA, []string{"de-DE", "en"}, "de-DE,de;q=0.9"},
+		{fingerprint.BrowserSafari, safariUA, []string{"ja"}, "ja"},
+		{fingerprint.BrowserChrome, requestKindChromeUA, nil, "en-US,en;q=0.9"},
+	}
+	for _, tt := range tests {
+		if actual := fingerprint.FormatAcceptLanguage(tt.browser, tt.ua, tt.locales); actual != tt.expected {
+			t.Errorf("%s %v: %q, 期望 %q", tt.browser, tt.locales, actual, tt.expected)
+		}
+	}
+
+	// 超过 10 种语言时 Chrome 的 q 值最低为 0.1，Firefox 保留两位小数
+	many := strings.Split("en-US,fr,de,es,it,pt,nl,sv,da,fi,no,pl", ",")
+	if chrome := fingerprint.FormatAcceptLanguage(fingerprint.BrowserChrome, requestKindChromeUA, many); !strings.HasSuffix(chrome, "pl;q=0.1") {
+		t.Errorf("Chrome 的最低 q 值应为 0.1: %s", chrome)
+	}
+	if firefox := fingerprint.FormatAcceptLanguage(fingerprint.BrowserFirefox, firefoxUA, many); !strings.Contains(firefox, "fr;q=0.92") {
+		t.Errorf("Firefox 10 种以上语言时应保留两位小数: %s", firefox)
+	}
+}
+
+// TestParseLocales 测试解析 locale 列表和 Accept-Language
+func TestParseLocales(t *testing.T) {
+	tests := map[string][]string{
+		"de-CH,fr,en":                {"de-CH", "fr", "en"},
+		"zh-CN,zh;q=0.9,en;q=0.8":    {"zh-CN", "zh", "en"},
+		" en-US , EN-us, *;q=0.1 ,,": {"en-US"},
+		"":                           {},
+	}
+	for input, expected := range tests {
+		if actual := fingerprint.ParseLocales(input); !reflect.DeepEqual(actual, expected) {
+			t.Errorf("ParseLocales(%q) = %v, 期望 %v", input, actual, expected)
+		}
+	}
+
+	if _, err := fingerprint.ParseWeights([]byte(`{"languages": {";q=0.5": 1}}`)); err == nil {
+		t.Errorf("空的 locale 列表应返回错误")
+	}
+}
+
+// TestRandomLanguageLocales 测试 RandomLanguage 返回 locale 列表，指纹的 Accept-Language 按浏览器格式生成
+func TestRandomLanguageLocales(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		locales := fingerprint.RandomLanguage()
+		if len(locales) == 0 || strings.Contains(strings.Join(locales, ","), ";") {
+			t.Fatalf("RandomLanguage 应返回 locale 列表: %v", locales)
+		}
+	}
+
+	weights := &fingerprint.Weights{
+		Profiles:  map[string]float64{"firefox_135": 1, "chrome_133": 1, "safari_ios_18_0": 1},
+		Languages: map[string]float64{"de-CH,fr,en": 1},
+	}
+	g := fingerprint.NewGenerator(5, fingerprint.WithWeights(weights))
+	expected := map[string]string{
+		fingerprint.MappedTLSClients["chrome_133"].GetClientHelloStr():      "de-CH,de;q=0.9,fr;q=0.8,en;q=0.7",
+		fingerprint.MappedTLSClients["firefox_135"].GetClientHelloStr():     "de-CH,fr;q=0.7,en;q=0.3",
+		fingerprint.MappedTLSClients["safari_ios_18_0"].GetClientHelloStr(): "de-CH,de;q=0.9",
+	}
+	for i := 0; i < 30; i++ {
+		result, err := g.GetWeightedRandomFingerprint()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Headers.AcceptLanguage != expected[result.HelloClientID] {
+			t.Errorf("%s 的 Accept-Language = %q, 期望 %q", result.HelloClientID, result.Headers.AcceptLanguage, expected[result.HelloClientID])
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package fingerprint_test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/vistone/fingerprint"
+	"github.com/vistone/fingerprint/profiles"
+	"gopkg.in/yaml.v3"
+)
+
+const acmeUserAgent = "Mozilla/5.0 (Android 14; Mobile; rv:140.0) Gecko/140.0 Firefox/140.0 AcmeBot/1.0"
+
+// profileFiles 返回用于测试的 profile 文件：一个 JSON（chrome_140）和一个 YAML（acme_bot）
+func profileFiles(t *testing.T) fstest.MapFS {
+	t.Helper()
+
+	chrome, err := json.Marshal(map[string]interface{}{
+		"name":       "chrome_140",
+		"user_agent": "Mozilla/5.0 (%s) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/140.0.0.0 Safari/537.36",
+		"profile":    fingerprint.MappedTLSClients["chrome_133"],
+	})
+	if err != nil {
+		t.Fatalf("序列化 profile 失败: %v", err)
+	}
+
+	acme, err := yaml.Marshal(map[string]interface{}{
+		"browser":      "firefox",
+		"mobile":       true,
+		"user_agent":   acmeUserAgent,
+		"headers":      map[string]string{"Accept-Encoding": "gzip", "X-Acme": "1"},
+		"header_order": []string{"User-Agent", "X-Acme", "Accept", "Accept-Encoding"},
+		"profile":      fingerprint.MappedTLSClients["firefox_135"],
+	})
+	if err != nil {
+		t.Fatalf("序列化 profile 失败: %v", err)
+	}
+
+	return fstest.MapFS{
+		"chrome/chrome_140.json": {Data: chrome},
+		"acme_bot.yaml":          {Data: acme},
+		"README.md":              {Data: []byte("not a profile")},
+	}
+}
+
+// TestLoadFS 测试加载的 profile 对随机指纹和 User-Agent 函数可见
+func TestLoadFS(t *testing.T) {
+	names, err := profiles.LoadFS(profileFiles(t))
+	if err != nil {
+		t.Fatalf("LoadFS 失败: %v", err)
+	}
+	t.Cleanup(func() { profiles.Unload(names...) })
+
+	if strings.Join(names, ",") != "acme_bot,chrome_140" {
+		t.Fatalf("加载的 profile 应为 acme_bot,chrome_140，实际为 %v", names)
+	}
+
+	profile, ok := fingerprint.DefaultRegistry.Get("chrome_140")
+	if !ok {
+		t.Fatalf("chrome_140 未注册到 DefaultRegistry")
+	}
+	expectedJA3, _ := fingerprint.MappedTLSClients["chrome_133"].JA3()
+	if ja3, _ := profile.JA3(); ja3 != expectedJA3 {
+		t.Errorf("加载的 profile JA3 不一致: %s", ja3)
+	}
+
+	ua, err := fingerprint.GetUserAgentByProfileNameWithOS("chrome_140", fingerprint.OSMacOS14)
+	if err != nil {
+		t.Fatalf("获取 User-Agent 失败: %v", err)
+	}
+	if !strings.Contains(ua, "Chrome/140.0.0.0") || !strings.Contains(ua, "Intel Mac OS X 10_15_7") {
+		t.Errorf("User-Agent 应使用文件中的模板和指定操作系统: %s", ua)
+	}
+
+	if ua, _ := fingerprint.GetUserAgentByProfileName("acme_bot"); ua != acmeUserAgent {
+		t.Errorf("不含 %%s 的 User-Agent 模板应原样使用，实际为 %s", ua)
+	}
+
+	// acme_bot 的名称没有浏览器前缀，应通过文件中的 browser 字段被筛选到
+	var result *fingerprint.FingerprintResult
+	for i := 0; i < 500 && (result == nil || result.UserAgent != acmeUserAgent); i++ {
+		result, err = fingerprint.GetRandomFingerprintByBrowser("firefox")
+		if err != nil {
+			t.Fatalf("GetRandomFingerprintByBrowser 失败: %v", err)
+		}
+	}
+	if result.UserAgent != acmeUserAgent {
+		t.Fatalf("GetRandomFingerprintByBrowser 没有返回加载的 acme_bot")
+	}
+
+	ordered := orderedNames(result.Headers)
+	if strings.Join(ordered[:4], ",") != "user-agent,x-acme,accept,accept-encoding" {
+		t.Errorf("header 顺序应使用文件中的 header_order，实际为 %v", ordered)
+	}
+	if result.Headers.AcceptEncoding != "gzip" {
+		t.Errorf("Accept-Encoding 应被文件中的 headers 覆盖，实际为 %s", result.Headers.AcceptEncoding)
+	}
+}
+
+// TestLoadFSConflict 测试名称冲突会被报告且不注册任何 profile
+func TestLoadFSConflict(t *testing.T) {
+	files := profileFiles(t)
+	files["chrome_133.json"] = &fstest.MapFile{Data: files["chrome/chrome_140.json"].Data}
+	files["duplicate.json"] = &fstest.MapFile{Data: files["chrome/chrome_140.json"].Data}
+	// chrome_133.json 与 duplicate.json 的 name 字段都是 chrome_140
+	files["builtin.yaml"] = &fstest.MapFile{Data: []byte("name: chrome_133\nprofile: {client_hello_id: {client: X, version: '1'}, tls: {cipher_suites: ['0x1301'], compression_methods: [0], extensions: []}}\n")}
+
+	names, err := profiles.LoadFS(files)
+	if err == nil {
+		profiles.Unload(names...)
+		t.Fatalf("名称冲突时应返回错误")
+	}
+
+	var conflict *profiles.ErrProfileConflict
+	if !errors.As(err, &conflict) {
+		t.Fatalf("应返回 *ErrProfileConflict，实际为 %v", err)
+	}
+	for _, expected := range []string{`"chrome_133" in builtin.yaml`, `"chrome_140" in chrome_133.json`, `"chrome_140" in duplicate.json`} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("错误中应包含 %s: %v", expected, err)
+		}
+	}
+	if _, ok := fingerprint.MappedTLSClients["acme_bot"]; ok {
+		t.Errorf("存在冲突时不应注册任何 profile")
+	}
+}
+
+// TestLoadDir 测试从目录加载以及重复加载的冲突
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+	for name, file := range profileFiles(t) {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, file.Data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	names, err := profiles.LoadDir(dir)
+	if err != nil {
+		t.Fatalf("LoadDir 失败: %v", err)
+	}
+	t.Cleanup(func() { profiles.Unload(names...) })
+
+	def, ok := profiles.Definition("chrome_140")
+	if !ok || def.Source != filepath.Join(dir, "chrome", "chrome_140.json") {
+		t.Errorf("Definition 的来源不正确: %+v", def.Source)
+	}
+
+	// 再次加载同一目录应报告冲突，而不是覆盖
+	if _, err := profiles.LoadDir(dir); err == nil {
+		t.Errorf("重复加载应返回冲突错误")
+	}
+}
+
+// TestLoadFSInvalid 测试格式错误的文件
+func TestLoadFSInvalid(t *testing.T) {
+	cases := map[string]string{
+		"bad.json":   `{"name": "bad",`,
+		"empty.yaml": "name: empty\n",
+		"ext.yaml":   "profile: {client_hello_id: {client: X, version: '1'}, tls: {cipher_suites: ['0x1301'], compression_methods: [0], extensions: [{name: nope}]}}\n",
+	}
+	for file, data := range cases {
+		t.Run(file, func(t *testing.T) {
+			names, err := profiles.LoadFS(fstest.MapFS{file: {Data: []byte(data)}})
+			if err == nil {
+				profiles.Unload(names...)
+				t.Fatalf("应返回错误")
+			}
+			if !strings.Contains(err.Error(), file) {
+				t.Errorf("错误中应包含文件名 %s: %v", file, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package fingerprint_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	tls "github.com/bogdanfinn/utls"
+	"github.com/vistone/fingerprint"
+	"github.com/vistone/fingerprint/profiles"
+)
+
+// specFeatures 根据 ClientHelloSpec 计算 profile 实际具有的 TLS 特性
+func specFeatures(t *testing.T, profile fingerprint.ClientProfile) profiles.Features {
+	t.Helper()
+
+	// 预定义 ClientHelloID 没有 SpecFactory，序列化时会展开为完整的 spec
+	data, err := json.Marshal(profile)
+	if err != nil {
+		t.Fatalf("序列化失败: %v", err)
+	}
+	var decoded fingerprint.ClientProfile
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("反序列化失败: %v", err)
+	}
+	spec, err := decoded.GetClientHelloSpec()
+	if err != nil {
+		t.Fatalf("获取 ClientHelloSpec 失败: %v", err)
+	}
+
+	var features profiles.Features
+	for _, ext := range spec.Extensions {
+		switch e := ext.(type) {
+		case *tls.UtlsPreSharedKeyExtension, *tls.FakePreSharedKeyExtension:
+			features |= profiles.FeaturePSK
+		case tls.EncryptedClientHelloExtension:
+			features |= profiles.FeatureECH
+		case *tls.KeyShareExtension:
+			for _, share := range e.KeyShares {
+				if share.Group == tls.X25519MLKEM768 || share.Group == tls.X25519Kyber768Draft00 {
+					features |= profiles.FeaturePQ
+				}
+			}
+		}
+	}
+	return features
+}
+
+// TestBuiltinMetadata 测试所有内置 profile 都带有完整的 metadata，且 TLS 特性与 spec 一致
+func TestBuiltinMetadata(t *testing.T) {
+	for name, profile := range profiles.MappedTLSClients {
+		t.Run(name, func(t *testing.T) {
+			metadata := profile.Metadata()
+			if metadata.App != "" {
+				// 应用 profile 的 ClientHello 来自应用的网络库，不声称任何浏览器
+				if metadata.Browser != "" || metadata.Engine != "" || metadata.MajorVersion != 0 || metadata.Platform == "" || metadata.Device == "" {
+					t.Errorf("应用 profile 的 metadata 不正确: %+v", metadata)
+				}
+			} else if metadata.Browser == "" || metadata.Engine == "" || metadata.MajorVersion == 0 || metadata.Device == "" {
+				t.Errorf("metadata 不完整: %+v", metadata)
+			} else if metadata.ReleaseDate.IsZero() {
+				t.Errorf("浏览器 profile 应包含发布日期")
+			}
+			if features := specFeatures(t, profile); features != metadata.Features {
+				t.Errorf("TLS 特性应为 %q，metadata 中为 %q", features, metadata.Features)
+			}
+		})
+	}
+}
+
+// TestMetadataValues 测试典型 profile 的 metadata
+func TestMetadataValues(t *testing.T) {
+	chrome := fingerprint.MappedTLSClients["chrome_133_PSK"].Metadata()
+	if chrome.Browser != profiles.BrowserChrome || chrome.Engine != profiles.EngineBlink || chrome.MajorVersion != 133 ||
+		chrome.Device != profiles.DeviceDesktop || chrome.Platform != "" || chrome.Mobile() {
+		t.Errorf("chrome_133_PSK 的 metadata 不正确: %+v", chrome)
+	}
+	if !chrome.Features.Has(profiles.FeaturePSK|profiles.FeaturePQ|profiles.FeatureECH) || chrome.Features.String() != "psk,pq,ech" {
+		t.Errorf("chrome_133_PSK 应包含 psk,pq,ech，实际为 %s", chrome.Features)
+	}
+	if chrome.ReleaseDate.Format("2006-01-02") != "2025-02-04" {
+		t.Errorf("chrome_133_PSK 的发布日期不正确: %s", chrome.ReleaseDate)
+	}
+
+	ipad := fingerprint.MappedTLSClients["safari_ipad_15_6"].Metadata()
+	if ipad.Platform != profiles.PlatformIPadOS || ipad.Device != profiles.DeviceTablet || !ipad.Mobile() || ipad.Version() != "15.6" {
+		t.Errorf("safari_ipad_15_6 的 metadata 不正确: %+v", ipad)
+	}
+
+	app := fingerprint.MappedTLSClients["okhttp4_android_13"].Metadata()
+	if app.App != "okhttp4" || app.Platform != profiles.PlatformAndroid || !app.Mobile() {
+		t.Errorf("okhttp4_android_13 的 metadata 不正确: %+v", app)
+	}
+}
+
+// TestMetadataSerialization 测试 metadata 的 JSON 格式与往返
+func TestMetadataSerialization(t *testing.T) {
+	profile := fingerprint.MappedTLSClients["safari_ios_18_5"]
+	data, err := json.Marshal(profile)
+	if err != nil {
+		t.Fatalf("序列化失败: %v", err)
+	}
+	expected := `"metadata":{"browser":"safari","engine":"webkit","major_version":18,"minor_version":5,"platform":"ios","device":"mobile","release_date":"2025-05-12"}`
+	if !strings.Contains(string(data), expected) {
+		t.Errorf("JSON 中缺少 %s\n%s", expected, data)
+	}
+
+	var decoded fingerprint.ClientProfile
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("反序列化失败: %v", err)
+	}
+	if decoded.Metadata() != profile.Metadata() {
+		t.Errorf("metadata 往返不一致: %+v != %+v", decoded.Metadata(), profile.Metadata())
+	}
+
+	for _, input := range []string{
+		`{"metadata":{"features":["quic"]},"client_hello_id":{"client":"X","version":"1"},"tls":{"cipher_suites":["0x1301"],"compression_methods":[0],"extensions":[]},"http2":{}}`,
+		`{"metadata":{"release_date":"2025/01/01"},"client_hello_id":{"client":"X","version":"1"},"tls":{"cipher_suites":["0x1301"],"compression_methods":[0],"extensions":[]},"http2":{}}`,
+		`{"metadata":{"device":"watch"},"client_hello_id":{"client":"X","version":"1"},"tls":{"cipher_suites":["0x1301"],"compression_methods":[0],"extensions":[]},"http2":{}}`,
+	} {
+		if err := json.Unmarshal([]byte(input), &decoded); err == nil {
+			t.Errorf("无效的 metadata 应返回错误: %s", input)
+		}
+	}
+}
+
+// TestMetadataDrivesSelection 测试按浏览器选择、User-Agent 和 headers 使用 metadata 而不是名称
+func TestMetadataDrivesSelection(t *testing.T) {
+	// 名称中没有浏览器前缀，只能通过 metadata 识别
+	profile := fingerprint.MappedTLSClients["firefox_135"].WithMetadata(profiles.Metadata{
+		Browser:      profiles.BrowserEdge,
+		Engine:       profiles.EngineBlink,
+		MajorVersion: 139,
+		Device:       profiles.DeviceDesktop,
+	})
+	// 使用独立注册表，使 custom_browser 成为唯一的 Edge profile
+	registry := fingerprint.NewRegistry()
+	if err := registry.Register("custom_browser", profile); err != nil {
+		t.Fatalf("注册失败: %v", err)
+	}
+
+	result, err := fingerprint.NewGenerator(1, fingerprint.WithRegistry(registry)).GetRandomFingerprintByBrowserWithOS("edge", fingerprint.OSWindows10)
+	if err != nil {
+		t.Fatalf("GetRandomFingerprintByBrowser 失败: %v", err)
+	}
+	if !strings.Contains(result.UserAgent, "Chrome/139.0.0.0") || !strings.Contains(result.UserAgent, "Edg/139.0.0.0") {
+		t.Errorf("User-Agent 应根据 metadata 生成: %s", result.UserAgent)
+	}
+
+	// 应用 profile 使用 Safari 的 User-Agent，但不属于 safari 浏览器类型
+	for i := 0; i < 100; i++ {
+		result, err := fingerprint.GetRandomFingerprintByBrowser("safari")
+		if err != nil {
+			t.Fatalf("GetRandomFingerprintByBrowser 失败: %v", err)
+		}
+		if result.Profile.Metadata().Browser != profiles.BrowserSafari || result.Profile.Metadata().App != "" {
+			t.Fatalf("safari 筛选结果不正确: %+v", result.Profile.Metadata())
+		}
+	}
+
+	// 未注册的 profile 也可以根据 metadata 获取 User-Agent
+	unregistered := fingerprint.NewClientProfile(tls.ClientHelloID{Client: "Unregistered", Version: "1"}, nil, nil, nil, 0, nil, nil)
+	ua, err := fingerprint.GetUserAgentFromProfile(unregistered.WithMetadata(profiles.Metadata{
+		Browser:      profiles.BrowserSafari,
+		MajorVersion: 26,
+		MinorVersion: 1,
+		Platform:     profiles.PlatformIOS,
+		Device:       profiles.DeviceMobile,
+	}))
+	if err != nil {
+		t.Fatalf("GetUserAgentFromProfile 失败: %v", err)
+	}
+	if !strings.Contains(ua, "iPhone OS 26_1") || !strings.Contains(ua, "Version/26.1") {
+		t.Errorf("User-Agent 应根据 metadata 生成: %s", ua)
+	}
+}
+
+// TestAppProfileHeaders 测试应用 profile 使用网络库的默认 headers，不发送浏览器的 client hints 和 Sec-Fetch-*
+func TestAppProfileHeaders(t *testing.T) {
+	tests := []struct {
+		app            string
+		platform       string
+		acceptEncoding string
+	}{
+		{"okhttp4", "android", "gzip"},
+		{"zalando", "ios", "gzip, deflate, br"},
+	}
+	for _, tt := range tests {
+		result, err := fingerprint.SelectRandom(fingerprint.Query{App: tt.app, Platform: tt.platform})
+		if err != nil {
+			t.Fatalf("SelectRandom 失败: %v", err)
+		}
+		headers := result.Headers
+		headers.ApplyAcceptCH(allAcceptCH)
+		if headers.SecCHUA != "" || headers.SecCHUAMobile != "" || headers.SecCHUAModel != "" {
+			t.Errorf("%s 不应发送 client hints: %+v", tt.app, headers)
+		}
+		if headers.SecFetchSite != "" || headers.SecFetchMode != "" || headers.UpgradeInsecureRequests != "" || headers.Priority != "" {
+			t.Errorf("%s 不应发送浏览器导航 headers: %+v", tt.app, headers)
+		}
+		if headers.Accept != "*/*" || headers.AcceptEncoding != tt.acceptEncoding {
+			t.Errorf("%s 的 Accept=%q Accept-Encoding=%q", tt.app, headers.Accept, headers.AcceptEncoding)
+		}
+	}
+}
+
+// TestAppProfileUserAgent 测试应用 profile 的 User-Agent 与 headers 来自同一个网络库，不使用浏览器的 User-Agent
+func TestAppProfileUserAgent(t *testing.T) {
+	for _, name := range fingerprint.DefaultRegistry.List() {
+		profile, _ := fingerprint.DefaultRegistry.Get(name)
+		metadata := profile.Metadata()
+		if metadata.App == "" {
+			continue
+		}
+		result, err := fingerprint.NewGenerator(1, fingerprint.WithRegistry(registryWith(t, name))).GetRandomFingerprint()
+		if err != nil {
+			t.Fatalf("%s: 生成指纹失败: %v", name, err)
+		}
+		ua, err := fingerprint.GetUserAgentByProfileName(name)
+		if err != nil || ua != result.UserAgent || result.Headers.UserAgent != ua {
+			t.Errorf("%s: User-Agent 不一致: %q %q %v", name, ua, result.Headers.UserAgent, err)
+		}
+		if strings.HasPrefix(ua, "Mozilla/") {
+			t.Errorf("%s: 应用 profile 不应使用浏览器的 User-Agent: %s", name, ua)
+		}
+
+		var library, acceptEncoding string
+		switch metadata.Platform {
+		case profiles.PlatformIOS, profiles.PlatformIPadOS:
+			library, acceptEncoding = " CFNetwork/", "gzip, deflate, br"
+		case profiles.PlatformAndroid:
+			library, acceptEncoding = "okhttp/", "gzip"
+		default:
+			library, acceptEncoding = "python-requests/", "gzip, deflate"
+		}
+		if !strings.Contains(ua, library) || result.Headers.AcceptEncoding != acceptEncoding {
+			t.Errorf("%s: User-Agent %q 与 Accept-Encoding %q 不是同一个网络库", name, ua, result.Headers.AcceptEncoding)
+		}
+		if library == " CFNetwork/" && !strings.Contains(ua, " Darwin/") {
+			t.Errorf("%s: CFNetwork User-Agent 应包含 Darwin 版本: %s", name, ua)
+		}
+	}
+
+	if ua, _ := fingerprint.GetUserAgentByProfileName("okhttp4_android_13"); ua != "okhttp/4.12.0" {
+		t.Errorf("okhttp4_android_13 应使用 OkHttp 的默认 User-Agent，实际为 %s", ua)
+	}
+}
@@ -0,0 +1,128 @@
+package fingerprint_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/vistone/fingerprint"
+	"github.com/vistone/fingerprint/profiles"
+	"gopkg.in/yaml.v3"
+)
+
+// TestUserAgentFrozenOS 测试 User-Agent 中的操作系统部分按各浏览器的冻结规则生成
+func TestUserAgentFrozenOS(t *testing.T) {
+	tests := []struct {
+		profile  string
+		os       fingerprint.OperatingSystem
+		expected string
+	}{
+		{"chrome_133", fingerprint.OSWindows11, "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36"},
+		{"chrome_133", fingerprint.OSMacOS15, "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36"},
+		{"chrome_133", fingerprint.OSLinux, "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36"},
+		{"edge_133", fingerprint.OSMacOS14, "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36"},
+		{"safari_16_0", fingerprint.OSMacOS13, "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15"},
+		{"firefox_135", fingerprint.OSMacOS14, "Mozilla/5.0 (Macintosh; Intel Mac OS X 10.15; rv:135.0)"},
+		{"firefox_135", fingerprint.OSLinuxARM64, "Mozilla/5.0 (X11; Linux aarch64; rv:135.0)"},
+		{"firefox_135", fingerprint.OSWindows11, "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:135.0)"},
+		{"firefox_102", fingerprint.NewOperatingSystem(profiles.PlatformWindows, "7", "x86", 32), "Mozilla/5.0 (Windows NT 6.1; rv:102.0)"},
+		{"chrome_103", fingerprint.NewOperatingSystem(profiles.PlatformMacOS, "10.14.6", "x86", 64), "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_14_6) AppleWebKit/537.36"},
+	}
+	for _, tt := range tests {
+		ua, err := fingerprint.GetUserAgentByProfileNameWithOS(tt.profile, tt.os)
+		if err != nil {
+			t.Fatalf("%s %v: %v", tt.profile, tt.os, err)
+		}
+		if !strings.HasPrefix(ua, tt.expected) {
+			t.Errorf("%s %v 的 User-Agent 应以 %q 开头: %s", tt.profile, tt.os, tt.expected, ua)
+		}
+	}
+}
+
+// TestPlatformVersionHints 测试高熵 client hints 使用操作系统的实际版本和架构
+func TestPlatformVersionHints(t *testing.T) {
+	tests := []struct {
+		os              fingerprint.OperatingSystem
+		platformVersion string
+		arch            string
+	}{
+		{fingerprint.OSWindows10, `"10.0.0"`, `"x86"`},
+		{fingerprint.OSWindows11, `"15.0.0"`, `"x86"`},
+		{fingerprint.OSMacOS14, `"14.7.1"`, `"arm"`},
+		{fingerprint.OSLinux, `"6.8.0"`, `"x86"`},
+	}
+	for _, tt := range tests {
+		g := fingerprint.NewGenerator(1, fingerprint.WithWeights(&fingerprint.Weights{
+			Profiles:         map[string]float64{"chrome_133": 1},
+			OperatingSystems: map[fingerprint.OperatingSystem]float64{tt.os: 1},
+		}))
+		result, err := g.GetWeightedRandomFingerprint()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.OS != tt.os {
+			t.Errorf("FingerprintResult.OS = %v，期望 %v", result.OS, tt.os)
+		}
+		result.Headers.ApplyAcceptCH(allAcceptCH)
+		if result.Headers.SecCHUAPlatformVersion != tt.platformVersion || result.Headers.SecCHUAArch != tt.arch || result.Headers.SecCHUABitness != `"64"` {
+			t.Errorf("%v: Platform-Version=%s Arch=%s Bitness=%s", tt.os, result.Headers.SecCHUAPlatformVersion, result.Headers.SecCHUAArch, result.Headers.SecCHUABitness)
+		}
+	}
+
+	// Windows 10 和 11 的 User-Agent 相同，只能通过 Sec-CH-UA-Platform-Version 区分
+	win10, _ := fingerprint.GetUserAgentByProfileNameWithOS("chrome_133", fingerprint.OSWindows10)
+	win11, _ := fingerprint.GetUserAgentByProfileNameWithOS("chrome_133", fingerprint.OSWindows11)
+	if win10 != win11 || fingerprint.OSWindows10 == fingerprint.OSWindows11 {
+		t.Errorf("Windows 10/11 的 User-Agent 应相同而操作系统不同: %s / %s", win10, win11)
+	}
+}
+
+// TestOperatingSystemText 测试操作系统名称的解析和序列化，以及旧版本 User-Agent 片段的兼容
+func TestOperatingSystemText(t *testing.T) {
+	for _, os := range append(fingerprint.OperatingSystems, fingerprint.OSLinuxARM64) {
+		parsed, err := fingerprint.ParseOperatingSystem(os.String())
+		if err != nil || parsed != os {
+			t.Errorf("ParseOperatingSystem(%q) = %v, %v", os.String(), parsed, err)
+		}
+	}
+
+	custom := fingerprint.OSWindows11
+	custom.PlatformVersion = "19.0.0"
+	if custom.String() != "windows-11-x86_64+19.0.0" {
+		t.Errorf("自定义 PlatformVersion 的名称不正确: %s", custom)
+	}
+	if parsed, _ := fingerprint.ParseOperatingSystem(custom.String()); parsed != custom {
+		t.Errorf("自定义 PlatformVersion 应能还原: %v", parsed)
+	}
+
+	legacy := map[string]fingerprint.OperatingSystem{
+		"Windows NT 10.0; Win64; x64":       fingerprint.OSWindows10,
+		"Macintosh; Intel Mac OS X 15_0_0":  fingerprint.NewOperatingSystem(profiles.PlatformMacOS, "15.0.0", "x86", 64),
+		"Macintosh; Intel Mac OS X 13_0_0":  fingerprint.NewOperatingSystem(profiles.PlatformMacOS, "13.0.0", "x86", 64),
+		"Macintosh; Intel Mac OS X 10_15_7": fingerprint.NewOperatingSystem(profiles.PlatformMacOS, "10.15.7", "x86", 64),
+		"Macintosh; Mac OS X 12_0_0":        fingerprint.OSMacOS12,
+		"X11; Linux x86_64":                 fingerprint.OSLinux,
+		"X11; Linux aarch64":                fingerprint.OSLinuxARM64,
+	}
+	for s, expected := range legacy {
+		if parsed, err := fingerprint.ParseOperatingSystem(s); err != nil || parsed != expected {
+			t.Errorf("ParseOperatingSystem(%q) = %v, %v，期望 %v", s, parsed, err, expected)
+		}
+	}
+	for _, s := range []string{"beos", "windows-11-x86_64-extra", "Custom OS"} {
+		if _, err := fingerprint.ParseOperatingSystem(s); err == nil {
+			t.Errorf("ParseOperatingSystem(%q) 应返回错误", s)
+		}
+	}
+
+	// 作为 map 键时 JSON 和 YAML 都使用名称
+	weights := map[fingerprint.OperatingSystem]float64{fingerprint.OSWindows11: 3, fingerprint.OSMacOS14: 1}
+	data, err := json.Marshal(weights)
+	if err != nil || string(data) != `{"macos-14.7.1-arm64":1,"windows-11-x86_64":3}` {
+		t.Errorf("JSON = %s, %v", data, err)
+	}
+	var fromYAML map[fingerprint.OperatingSystem]float64
+	if err := yaml.Unmarshal([]byte("windows-11-x86_64: 3\nmacos-14.7.1-arm64: 1\n"), &fromYAML); err != nil || len(fromYAML) != 2 || fromYAML[fingerprint.OSWindows11] != 3 {
+		t.Errorf("YAML = %v, %v", fromYAML, err)
+	}
+}
@@ -0,0 +1,180 @@
+package fingerprint_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vistone/fingerprint"
+)
+
+// TestPersonaDataset 测试内置数据集中每个国家/地区都能生成指纹，时区的标准时间偏移正确
+func TestPersonaDataset(t *testing.T) {
+	countries := fingerprint.PersonaCountries()
+	if len(countries) < 30 {
+		t.Fatalf("内置数据集只有 %d 个国家/地区", len(countries))
+	}
+	for _, country := range countries {
+		persona, err := fingerprint.NewPersona(strings.ToLower(country))
+		if err != nil {
+			t.Fatalf("NewPersona(%s): %v", country, err)
+		}
+		g := fingerprint.NewGenerator(1, fingerprint.WithPersona(persona))
+		for i := 0; i < 20; i++ {
+			result, err := g.GetWeightedRandomFingerprint()
+			if err != nil {
+				t.Fatalf("%s: %v", country, err)
+			}
+			if result.Country != country || result.Timezone == nil || len(result.Locales) == 0 {
+				t.Fatalf("%s: Country=%q Timezone=%v Locales=%v", country, result.Country, result.Timezone, result.Locales)
+			}
+			checkStandardOffset(t, *result.Timezone)
+		}
+	}
+}
+
+// checkStandardOffset 系统有时区数据时，检查 Offset 是否为标准时间（一月和七月中较小的）偏移
+func checkStandardOffset(t *testing.T, tz fingerprint.Timezone) {
+	t.Helper()
+	loc, err := time.LoadLocation(tz.Name)
+	if err != nil {
+		return
+	}
+	_, january := time.Date(2025, time.January, 15, 12, 0, 0, 0, loc).Zone()
+	_, july := time.Date(2025, time.July, 15, 12, 0, 0, 0, loc).Zone()
+	standard := january
+	if july < standard {
+		standard = july
+	}
+	if standard != tz.Offset*60 {
+		t.Errorf("%s 的标准时间偏移为 %d 分钟，数据集中为 %d", tz.Name, standard/60, tz.Offset)
+	}
+}
+
+// TestPersonaCoherence 测试 Persona 生成的 Accept-Language、User-Agent 和时区与市场一致
+func TestPersonaCoherence(t *testing.T) {
+	persona, err := fingerprint.NewPersona("DE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := fingerprint.NewGenerator(7, fingerprint.WithPersona(persona))
+	platforms := make(map[string]int)
+	for i := 0; i < 300; i++ {
+		result, err := g.GetWeightedRandomFingerprint()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Timezone.Name != "Europe/Berlin" {
+			t.Fatalf("德国的时区应为 Europe/Berlin: %s", result.Timezone.Name)
+		}
+		if strings.HasPrefix(result.Locales[0], "ja") || strings.HasPrefix(result.Locales[0], "zh") {
+			t.Fatalf("德国不应选中 %v", result.Locales)
+		}
+		metadata := result.Profile.Metadata()
+		expected := fingerprint.FormatAcceptLanguage(fingerprint.BrowserType(metadata.Browser), result.UserAgent, result.Locales)
+		if result.Headers.AcceptLanguage != expected {
+			t.Fatalf("Accept-Language = %q, 期望 %q", result.Headers.AcceptLanguage, expected)
+		}
+		switch {
+		case strings.Contains(result.UserAgent, "iPhone"):
+			platforms["ios"]++
+		case strings.Contains(result.UserAgent, "Android"):
+			platforms["android"]++
+		case strings.Contains(result.UserAgent, "Windows"):
+			platforms["windows"]++
+		case strings.Contains(result.UserAgent, "Macintosh"):
+			platforms["macos"]++
+		case strings.Contains(result.UserAgent, "Linux"):
+			platforms["linux"]++
+		}
+	}
+	// 德国的桌面 Windows 和 Android 份额都远高于 Linux
+	if platforms["windows"] < platforms["linux"] || platforms["android"] < platforms["linux"] || platforms["ios"] == 0 || platforms["macos"] == 0 {
+		t.Errorf("平台分布不符合德国市场: %v", platforms)
+	}
+
+	jp, _ := fingerprint.NewPersona("JP")
+	g = fingerprint.NewGenerator(3, fingerprint.WithPersona(jp))
+	for i := 0; i < 50; i++ {
+		if locales := g.RandomLanguage(); locales[0] != "en-US" && !strings.HasPrefix(locales[0], "ja") {
+			t.Fatalf("日本的 RandomLanguage 不应返回 %v", locales)
+		}
+	}
+}
+
+// TestPersonaMix 测试按权重组合多个国家/地区
+func TestPersonaMix(t *testing.T) {
+	persona, err := fingerprint.NewPersonaMix(map[string]float64{"de": 3, "AT": 1, "FR": 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if countries := persona.Countries(); !reflect.DeepEqual(countries, []string{"AT", "DE", "FR"}) {
+		t.Errorf("Countries() = %v", countries)
+	}
+	counts := make(map[string]int)
+	for i := 0; i < 200; i++ {
+		result, err := fingerprint.GetWeightedRandomFingerprintWithPersona(persona)
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts[result.Country]++
+	}
+	if counts["DE"] <= counts["AT"] || counts["AT"] == 0 || counts["FR"] != 0 {
+		t.Errorf("国家分布不符合权重: %v", counts)
+	}
+
+	var unknown *fingerprint.ErrUnknownCountry
+	if _, err := fingerprint.NewPersona("XX"); !errors.As(err, &unknown) || unknown.Country != "XX" {
+		t.Errorf("未知国家应返回 ErrUnknownCountry: %v", err)
+	}
+	if _, err := fingerprint.NewPersonaMix(map[string]float64{"DE": 0}); err == nil {
+		t.Errorf("没有正权重的国家时应返回错误")
+	}
+	if _, err := fingerprint.NewPersonaMix(map[string]float64{"DE": -1, "FR": 2}); err == nil {
+		t.Errorf("负权重应返回错误")
+	}
+}
+
+// TestPersonaSelection 测试 Persona 与按浏览器、按条件选择的组合，以及相同种子的可复现性
+func TestPersonaSelection(t *testing.T) {
+	persona, _ := fingerprint.NewPersona("US")
+
+	g := fingerprint.NewGenerator(11, fingerprint.WithPersona(persona))
+	for i := 0; i < 30; i++ {
+		result, err := g.GetRandomFingerprintByBrowser("safari")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Profile.Metadata().Browser != "safari" || result.Country != "US" {
+			t.Fatalf("应选中美国的 Safari: %s %s", result.HelloClientID, result.Country)
+		}
+
+		result, err = g.SelectRandom(fingerprint.Query{Browser: "chrome", Platform: "windows", ExcludeApps: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(result.UserAgent, "Windows") || result.Headers.SecCHUAPlatform != `"Windows"` {
+			t.Fatalf("指定 windows 平台时应使用 Windows: %s %s", result.UserAgent, result.Headers.SecCHUAPlatform)
+		}
+	}
+
+	a := fingerprint.NewGenerator(42, fingerprint.WithPersona(persona))
+	b := fingerprint.NewGenerator(42, fingerprint.WithPersona(persona))
+	for i := 0; i < 20; i++ {
+		ra, err := a.GetRandomFingerprint()
+		if err != nil {
+			t.Fatal(err)
+		}
+		rb, _ := b.GetRandomFingerprint()
+		if ra.UserAgent != rb.UserAgent || !reflect.DeepEqual(ra.Timezone, rb.Timezone) || ra.Headers.AcceptLanguage != rb.Headers.AcceptLanguage {
+			t.Fatalf("相同种子应得到相同结果: %s / %s", ra.UserAgent, rb.UserAgent)
+		}
+	}
+
+	tz := fingerprint.Timezone{Name: "Nowhere/Invalid", Offset: 330}
+	if _, offset := time.Date(2025, time.January, 1, 0, 0, 0, 0, tz.Location()).Zone(); offset != 330*60 {
+		t.Errorf("没有时区数据时应使用标准时间偏移: %d", offset)
+	}
+}
@@ -0,0 +1,88 @@
+package fingerprint_test
+
+import (
+	"testing"
+
+	"github.com/vistone/fingerprint"
+)
+
+// TestPriorityHeader 测试各浏览器和版本的 Priority 头
+func TestPriorityHeader(t *testing.T) {
+	tests := []struct {
+		name     string
+		browser  fingerprint.BrowserType
+		ua       string
+		kind     fingerprint.RequestKind
+		expected string
+	}{
+		{"chrome_133 导航", fingerprint.BrowserChrome, requestKindChromeUA, fingerprint.RequestNavigate, "u=0, i"},
+		{"chrome_133 fetch", fingerprint.BrowserChrome, requestKindChromeUA, fingerprint.RequestFetch, "u=1, i"},
+		{"chrome_133 样式表", fingerprint.BrowserChrome, requestKindChromeUA, fingerprint.RequestStyle, "u=0"},
+		{"chrome_133 图片", fingerprint.BrowserChrome, requestKindChromeUA, fingerprint.RequestImage, "u=2, i"},
+		{"chrome_120 不发送", fingerprint.BrowserChrome, "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", fingerprint.RequestNavigate, ""},
+		{"edge_133 脚本", fingerprint.BrowserEdge, requestKindChromeUA + " Edg/133.0.0.0", fingerprint.RequestScript, "u=1"},
+		{"firefox_135 导航", fingerprint.BrowserFirefox, "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:135.0) Gecko/20100101 Firefox/135.0", fingerprint.RequestNavigate, "u=0, i"},
+		{"firefox_135 图片", fingerprint.BrowserFirefox, "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:135.0) Gecko/20100101 Firefox/135.0", fingerprint.RequestImage, "u=5, i"},
+		{"firefox_123 不发送", fingerprint.BrowserFirefox, "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:123.0) Gecko/20100101 Firefox/123.0", fingerprint.RequestNavigate, ""},
+		{"safari_18 导航", fingerprint.BrowserSafari, "Mozilla/5.0 (iPhone; CPU iPhone OS 18_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/18.0 Mobile/15E148 Safari/604.1", fingerprint.RequestNavigate, "u=0, i"},
+		{"safari_16 不发送", fingerprint.BrowserSafari, "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.0 Safari/605.1.15", fingerprint.RequestNavigate, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := fingerprint.GenerateHeadersFor(tt.browser, tt.ua, false, tt.kind, fingerprint.Context{Origin: "https://example.com"})
+			if h.Priority != tt.expected {
+				t.Errorf("Priority = %q, 期望 %q", h.Priority, tt.expected)
+			}
+			if value, ok := h.ToMap()["Priority"]; ok != (tt.expected != "") || value != tt.expected {
+				t.Errorf("ToMap 中的 Priority = %q", value)
+			}
+		})
+	}
+
+	navigate := fingerprint.GenerateHeaders(fingerprint.BrowserChrome, requestKindChromeUA, false)
+	if navigate.Priority != "u=0, i" {
+		t.Errorf("GenerateHeaders 应生成导航请求的 Priority: %q", navigate.Priority)
+	}
+	if merged := navigate.Merge(map[string]string{"Priority": "u=3"}); merged.Priority != "u=3" || merged.Clone().Priority != "u=3" {
+		t.Errorf("Merge 和 Clone 应保留 Priority")
+	}
+}
+
+// TestPriorityMatchesProfile 测试 Priority 头与 profile 的 HEADERS 帧优先级一致
+func TestPriorityMatchesProfile(t *testing.T) {
+	for name, profile := range fingerprint.MappedTLSClients {
+		registry := fingerprint.NewRegistry()
+		if err := registry.Register(name, profile); err != nil {
+			t.Fatal(err)
+		}
+		result, err := fingerprint.NewGenerator(1, fingerprint.WithRegistry(registry)).GetRandomFingerprint()
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		param := profile.GetHeaderPriority()
+		if param != nil && param.StreamDep != 0 && result.Headers.Priority != "" {
+			t.Errorf("%s 使用 PRIORITY 帧依赖树，不应发送 Priority 头: %q", name, result.Headers.Priority)
+		}
+	}
+
+	result, err := fingerprint.NewGenerator(1, fingerprint.WithRegistry(registryWith(t, "safari_ios_18_5"))).GetRandomFingerprint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// HEADERS 帧权重为 256，对应最高的紧急程度
+	if result.Headers.Priority != "u=0, i" {
+		t.Errorf("safari_ios_18_5 的 Priority = %q", result.Headers.Priority)
+	}
+}
+
+// registryWith 返回只包含指定 profile 的注册表
+func registryWith(t *testing.T, names ...string) *fingerprint.Registry {
+	t.Helper()
+	registry := fingerprint.NewRegistry()
+	for _, name := range names {
+		if err := registry.Register(name, fingerprint.MappedTLSClients[name]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return registry
+}
@@ -0,0 +1,269 @@
+package fingerprint_test
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/vistone/fingerprint"
+	"github.com/vistone/fingerprint/profiles"
+)
+
+// TestRegistry 测试独立注册表的基本操作
+func TestRegistry(t *testing.T) {
+	registry := fingerprint.NewRegistry()
+	if registry.Len() != 0 {
+		t.Fatalf("新建的注册表应为空，实际有 %d 个 profile", registry.Len())
+	}
+
+	chrome := fingerprint.MappedTLSClients["chrome_133"]
+	firefox := fingerprint.MappedTLSClients["firefox_135"]
+	if err := registry.Register("firefox_135", firefox); err != nil {
+		t.Fatalf("注册失败: %v", err)
+	}
+	if err := registry.Register("chrome_133", chrome); err != nil {
+		t.Fatalf("注册失败: %v", err)
+	}
+
+	var exists *profiles.ErrProfileExists
+	if err := registry.Register("chrome_133", firefox); !errors.As(err, &exists) || exists.Name != "chrome_133" {
+		t.Errorf("重复注册应返回 *ErrProfileExists，实际为 %v", err)
+	}
+	if err := registry.Register("", chrome); err == nil {
+		t.Errorf("空名称应返回错误")
+	}
+
+	if got, ok := registry.Get("chrome_133"); !ok || got.GetClientHelloStr() != chrome.GetClientHelloStr() {
+		t.Errorf("Get 返回的 profile 不正确: %s", got.GetClientHelloStr())
+	}
+	if names := registry.List(); strings.Join(names, ",") != "chrome_133,firefox_135" {
+		t.Errorf("List 应按名称排序，实际为 %v", names)
+	}
+
+	filtered := registry.Filter(func(name string, _ fingerprint.ClientProfile) bool {
+		return strings.HasPrefix(name, "firefox_")
+	})
+	if len(filtered) != 1 || filtered["firefox_135"].GetClientHelloStr() != firefox.GetClientHelloStr() {
+		t.Errorf("Filter 结果不正确: %v", filtered)
+	}
+
+	snapshot := registry.Snapshot()
+	delete(snapshot, "chrome_133")
+	if _, ok := registry.Get("chrome_133"); !ok {
+		t.Errorf("修改快照不应影响注册表")
+	}
+
+	// Range 中可以修改注册表，遍历的是调用时的快照
+	visited := make([]string, 0)
+	registry.Range(func(name string, _ fingerprint.ClientProfile) bool {
+		visited = append(visited, name)
+		registry.Unregister(name)
+		return true
+	})
+	if strings.Join(visited, ",") != "chrome_133,firefox_135" || registry.Len() != 0 {
+		t.Errorf("Range 遍历结果不正确: %v，剩余 %d", visited, registry.Len())
+	}
+	if registry.Unregister("chrome_133") {
+		t.Errorf("移除不存在的 profile 应返回 false")
+	}
+
+	if _, ok := fingerprint.MappedTLSClients["chrome_133"]; !ok {
+		t.Errorf("独立注册表不应影响 MappedTLSClients")
+	}
+}
+
+// TestRegistryClone 测试复制的注册表与默认注册表相互隔离
+func TestRegistryClone(t *testing.T) {
+	clone := fingerprint.DefaultRegistry.Clone()
+	if clone.Len() != fingerprint.DefaultRegistry.Len() {
+		t.Fatalf("复制的注册表应包含 %d 个 profile，实际为 %d", fingerprint.DefaultRegistry.Len(), clone.Len())
+	}
+
+	if !clone.Unregister("chrome_133") {
+		t.Fatalf("复制的注册表应包含内置 profile")
+	}
+	if _, ok := fingerprint.DefaultRegistry.Get("chrome_133"); !ok {
+		t.Errorf("修改复制的注册表不应影响 DefaultRegistry")
+	}
+
+	names, err := clone.LoadFS(profileFiles(t))
+	if err != nil {
+		t.Fatalf("LoadFS 失败: %v", err)
+	}
+	if strings.Join(names, ",") != "acme_bot,chrome_140" {
+		t.Errorf("加载的 profile 应为 acme_bot,chrome_140，实际为 %v", names)
+	}
+	if _, ok := clone.Definition("acme_bot"); !ok {
+		t.Errorf("复制的注册表中应能获取加载的定义")
+	}
+	if _, ok := fingerprint.MappedTLSClients["acme_bot"]; ok {
+		t.Errorf("加载到复制的注册表不应影响 MappedTLSClients")
+	}
+	if _, ok := profiles.Definition("acme_bot"); ok {
+		t.Errorf("加载到复制的注册表不应影响 DefaultRegistry")
+	}
+
+	// Unload 只移除加载的 profile
+	clone.Unload("acme_bot", "firefox_135")
+	if _, ok := clone.Get("acme_bot"); ok {
+		t.Errorf("Unload 后 acme_bot 应被移除")
+	}
+	if _, ok := clone.Get("firefox_135"); !ok {
+		t.Errorf("Unload 不应移除内置 profile")
+	}
+}
+
+// TestMappedTLSClientsView 测试 MappedTLSClients 与 DefaultRegistry 保持一致：
+// 直接写入该表的 profile 可以被选择，注册、加载的 profile 也会出现在该表中
+func TestMappedTLSClientsView(t *testing.T) {
+	for name := range fingerprint.MappedTLSClients {
+		if _, ok := fingerprint.DefaultRegistry.Get(name); !ok {
+			t.Fatalf("DefaultRegistry 应包含 profile %s", name)
+		}
+	}
+
+	// 旧代码直接写入 MappedTLSClients
+	profile := fingerprint.MappedTLSClients["firefox_135"].WithMetadata(profiles.Metadata{
+		Browser:      profiles.BrowserFirefox,
+		Engine:       profiles.EngineGecko,
+		MajorVersion: 135,
+		Device:       profiles.DeviceDesktop,
+		Features:     fingerprint.MappedTLSClients["firefox_135"].Metadata().Features,
+	})
+	fingerprint.MappedTLSClients["legacy_map_entry"] = profile
+	t.Cleanup(func() { fingerprint.DefaultRegistry.Unregister("legacy_map_entry") })
+
+	if _, ok := fingerprint.DefaultRegistry.Get("legacy_map_entry"); !ok {
+		t.Fatalf("写入 MappedTLSClients 的 profile 应在 DefaultRegistry 中")
+	}
+	names := fingerprint.Select(fingerprint.Query{Browser: "firefox", MinVersion: 135})
+	if !slices.Contains(names, "legacy_map_entry") {
+		t.Errorf("写入 MappedTLSClients 的 profile 应能被 Select 选中: %v", names)
+	}
+	if ua, err := fingerprint.GetUserAgentByProfileName("legacy_map_entry"); err != nil || !strings.Contains(ua, "Firefox/135.0") {
+		t.Errorf("写入 MappedTLSClients 的 profile 应能生成 User-Agent: %s, %v", ua, err)
+	}
+
+	fingerprint.DefaultRegistry.Unregister("legacy_map_entry")
+	if _, ok := fingerprint.MappedTLSClients["legacy_map_entry"]; ok {
+		t.Errorf("Unregister 后 profile 应从 MappedTLSClients 中移除")
+	}
+
+	loaded, err := fingerprint.LoadFS(fstest.MapFS{"view_test.json": profileFiles(t)["chrome/chrome_140.json"]})
+	if err != nil {
+		t.Fatalf("LoadFS 失败: %v", err)
+	}
+	t.Cleanup(func() { profiles.Unload(loaded...) })
+	if _, ok := fingerprint.MappedTLSClients["chrome_140"]; !ok {
+		t.Errorf("加载的 profile 应出现在 MappedTLSClients 中")
+	}
+}
+
+// TestDefaultRegistryConcurrentWithGeneration 测试运行时注册、移除 profile 与包级随机函数并发（配合 go test -race）
+func TestDefaultRegistryConcurrentWithGeneration(t *testing.T) {
+	profile := fingerprint.MappedTLSClients["chrome_133"]
+	t.Cleanup(func() { fingerprint.DefaultRegistry.Unregister("concurrent_generation") })
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			_ = fingerprint.DefaultRegistry.Register("concurrent_generation", profile)
+			fingerprint.DefaultRegistry.Unregister("concurrent_generation")
+		}
+		close(done)
+	}()
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				if _, err := fingerprint.GetRandomFingerprint(); err != nil {
+					t.Error(err)
+					return
+				}
+				if _, err := fingerprint.GetWeightedRandomFingerprint(); err != nil {
+					t.Error(err)
+					return
+				}
+				_ = fingerprint.DefaultWeights()
+				_, _ = fingerprint.GetUserAgentByProfileName("chrome_133")
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// TestRegistryConcurrent 测试并发注册与读取（配合 go test -race）
+func TestRegistryConcurrent(t *testing.T) {
+	registry := fingerprint.DefaultRegistry.Clone()
+	profile := fingerprint.MappedTLSClients["chrome_133"]
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				name := fmt.Sprintf("custom_%d_%d", i, j)
+				if err := registry.Register(name, profile); err != nil {
+					t.Errorf("注册 %s 失败: %v", name, err)
+				}
+				registry.Unregister(name)
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				registry.Range(func(string, fingerprint.ClientProfile) bool { return true })
+				registry.Filter(func(name string, _ fingerprint.ClientProfile) bool {
+					return strings.HasPrefix(name, "custom_")
+				})
+				registry.Get("chrome_133")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if registry.Len() != fingerprint.DefaultRegistry.Len() {
+		t.Errorf("并发注册和移除后 profile 数量应恢复为 %d，实际为 %d", fingerprint.DefaultRegistry.Len(), registry.Len())
+	}
+}
+
+// TestRandomFingerprintConcurrentRegister 测试随机指纹与默认注册表的并发注册不会竞争
+func TestRandomFingerprintConcurrentRegister(t *testing.T) {
+	profile := fingerprint.MappedTLSClients["chrome_133"]
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			name := fmt.Sprintf("chrome_concurrent_%d", i)
+			if err := fingerprint.DefaultRegistry.Register(name, profile); err != nil {
+				t.Errorf("注册 %s 失败: %v", name, err)
+			}
+			fingerprint.DefaultRegistry.Unregister(name)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			if _, err := fingerprint.GetRandomFingerprint(); err != nil {
+				t.Errorf("GetRandomFingerprint 失败: %v", err)
+			}
+		}
+	}()
+	wg.Wait()
+}
@@ -0,0 +1,130 @@
+package fingerprint_test
+
+import (
+	"testing"
+
+	"github.com/vistone/fingerprint"
+)
+
+const requestKindChromeUA = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/133.0.0.0 Safari/537.36"
+
+// TestGenerateHeadersForKinds 测试 Chrome 各请求类型的 Accept 和 Sec-Fetch-*
+func TestGenerateHeadersForKinds(t *testing.T) {
+	ctx := fingerprint.Context{Origin: "https://example.com", Referer: "https://example.com/page"}
+	tests := []struct {
+		kind        fingerprint.RequestKind
+		accept      string
+		mode, dest  string
+		user, upgrd string
+	}{
+		{fingerprint.RequestNavigate, "", "navigate", "document", "?1", "1"},
+		{fingerprint.RequestIframe, "", "navigate", "iframe", "", "1"},
+		{fingerprint.RequestFetch, "*/*", "cors", "empty", "", ""},
+		{fingerprint.RequestXHR, "*/*", "cors", "empty", "", ""},
+		{fingerprint.RequestImage, "image/avif,image/webp,image/apng,image/svg+xml,image/*,*/*;q=0.8", "no-cors", "image", "", ""},
+		{fingerprint.RequestScript, "*/*", "no-cors", "script", "", ""},
+		{fingerprint.RequestStyle, "text/css,*/*;q=0.1", "no-cors", "style", "", ""},
+	}
+	navigate := fingerprint.GenerateHeaders(fingerprint.BrowserChrome, requestKindChromeUA, false)
+
+	for _, tt := range tests {
+		t.Run(string(tt.kind), func(t *testing.T) {
+			h := fingerprint.GenerateHeadersFor(fingerprint.BrowserChrome, requestKindChromeUA, false, tt.kind, ctx)
+			accept := tt.accept
+			if accept == "" {
+				accept = navigate.Accept
+			}
+			if h.Accept != accept {
+				t.Errorf("Accept = %q, 期望 %q", h.Accept, accept)
+			}
+			if h.SecFetchSite != "same-origin" || h.SecFetchMode != tt.mode || h.SecFetchDest != tt.dest || h.SecFetchUser != tt.user {
+				t.Errorf("Sec-Fetch-* = %s/%s/%s/%q", h.SecFetchSite, h.SecFetchMode, h.SecFetchDest, h.SecFetchUser)
+			}
+			if h.UpgradeInsecureRequests != tt.upgrd {
+				t.Errorf("Upgrade-Insecure-Requests = %q, 期望 %q", h.UpgradeInsecureRequests, tt.upgrd)
+			}
+			m := h.ToMap()
+			if m["Referer"] != ctx.Referer {
+				t.Errorf("Referer = %q", m["Referer"])
+			}
+			if _, ok := m["Origin"]; ok {
+				t.Errorf("同源请求不应发送 Origin")
+			}
+			if h.SecCHUA != navigate.SecCHUA {
+				t.Errorf("所有请求都应发送相同的 Sec-CH-UA")
+			}
+		})
+	}
+}
+
+// TestGenerateHeadersForContext 测试 Sec-Fetch-Site、Origin 和 Referer 随发起方变化
+func TestGenerateHeadersForContext(t *testing.T) {
+	direct := fingerprint.GenerateHeadersFor(fingerprint.BrowserChrome, requestKindChromeUA, false, fingerprint.RequestNavigate, fingerprint.Context{})
+	if direct.SecFetchSite != "none" || direct.ToMap()["Referer"] != "" {
+		t.Errorf("直接访问应为 Sec-Fetch-Site: none 且不发送 Referer: %+v", direct)
+	}
+
+	link := fingerprint.GenerateHeadersFor(fingerprint.BrowserChrome, requestKindChromeUA, false, fingerprint.RequestNavigate, fingerprint.Context{
+		Origin:   "https://search.example",
+		Referer:  "https://search.example/",
+		SameSite: fingerprint.SiteCrossSite,
+	})
+	if link.SecFetchSite != "cross-site" || link.SecFetchUser != "?1" {
+		t.Errorf("跨站链接导航的 Sec-Fetch-* 不正确: %s %q", link.SecFetchSite, link.SecFetchUser)
+	}
+	if _, ok := link.ToMap()["Origin"]; ok {
+		t.Errorf("GET 导航不应发送 Origin")
+	}
+	names := orderedNames(link)
+	if indexOf(names, "referer") != indexOf(names, "sec-fetch-dest")+1 {
+		t.Errorf("Chrome 的 referer 应紧跟 sec-fetch-dest: %v", names)
+	}
+
+	api := fingerprint.GenerateHeadersFor(fingerprint.BrowserChrome, requestKindChromeUA, false, fingerprint.RequestFetch, fingerprint.Context{
+		Origin:   "https://www.example.com",
+		Referer:  "https://www.example.com/",
+		SameSite: fingerprint.SiteSameSite,
+	})
+	if api.SecFetchSite != "same-site" || api.ToMap()["Origin"] != "https://www.example.com" {
+		t.Errorf("同站跨源的 fetch 应发送 Origin: %s %v", api.SecFetchSite, api.ToMap())
+	}
+	image := fingerprint.GenerateHeadersFor(fingerprint.BrowserChrome, requestKindChromeUA, false, fingerprint.RequestImage, fingerprint.Context{
+		Origin:   "https://www.example.com",
+		SameSite: fingerprint.SiteCrossSite,
+	})
+	if _, ok := image.ToMap()["Origin"]; ok || image.SecFetchSite != "cross-site" {
+		t.Errorf("no-cors 请求不应发送 Origin: %v", image.ToMap())
+	}
+}
+
+// TestGenerateHeadersForBrowsers 测试各浏览器子资源的 Accept，以及不发送 Sec-Fetch-* 的浏览器保持不发送
+func TestGenerateHeadersForBrowsers(t *testing.T) {
+	firefoxUA := "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:135.0) Gecko/20100101 Firefox/135.0"
+	firefox := fingerprint.GenerateHeadersFor(fingerprint.BrowserFirefox, firefoxUA, false, fingerprint.RequestImage, fingerprint.Context{Origin: "https://example.com"})
+	if firefox.Accept != "image/avif,image/webp,image/png,image/svg+xml,image/*;q=0.8,*/*;q=0.5" {
+		t.Errorf("Firefox 图片 Accept 不正确: %s", firefox.Accept)
+	}
+	if navigate := fingerprint.GenerateHeaders(fingerprint.BrowserFirefox, firefoxUA, false); navigate.SecFetchMode == "" && firefox.SecFetchMode != "" {
+		t.Errorf("导航请求不发送 Sec-Fetch-* 时子资源请求也不应发送")
+	}
+
+	safariUA := "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/18.0 Safari/605.1.15"
+	safari := fingerprint.GenerateHeadersFor(fingerprint.BrowserSafari, safariUA, false, fingerprint.RequestStyle, fingerprint.Context{Origin: "https://example.com"})
+	if safari.Accept != "text/css,*/*;q=0.1" || safari.SecFetchDest != "style" {
+		t.Errorf("Safari 样式表请求不正确: %s %s", safari.Accept, safari.SecFetchDest)
+	}
+
+	edgeUA := requestKindChromeUA + " Edg/133.0.0.0"
+	edge := fingerprint.GenerateHeadersFor(fingerprint.BrowserEdge, edgeUA, false, fingerprint.RequestImage, fingerprint.Context{Origin: "https://example.com"})
+	if edge.Accept != "image/avif,image/webp,image/apng,image/svg+xml,image/*,*/*;q=0.8" {
+		t.Errorf("Edge 应使用 Chromium 的图片 Accept: %s", edge.Accept)
+	}
+
+	g1 := fingerprint.NewGenerator(3, fingerprint.WithWeights(fingerprint.DefaultWeights()))
+	g2 := fingerprint.NewGenerator(3, fingerprint.WithWeights(fingerprint.DefaultWeights()))
+	a := g1.GenerateHeadersFor(fingerprint.BrowserChrome, requestKindChromeUA, false, fingerprint.RequestScript, fingerprint.Context{})
+	b := g2.GenerateHeadersFor(fingerprint.BrowserChrome, requestKindChromeUA, false, fingerprint.RequestScript, fingerprint.Context{})
+	if a.AcceptLanguage != b.AcceptLanguage {
+		t.Errorf("相同种子的 Generator 应得到相同的 Accept-Language")
+	}
+}
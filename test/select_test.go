@@ -0,0 +1,158 @@
+package fingerprint_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/vistone/fingerprint"
+	"github.com/vistone/fingerprint/profiles"
+)
+
+// TestSelect 测试按条件筛选 profile
+func TestSelect(t *testing.T) {
+	cases := []struct {
+		name     string
+		query    fingerprint.Query
+		expected []string
+	}{
+		{
+			name:     "Chrome ≥ 130 且支持后量子",
+			query:    fingerprint.Query{Browser: "chrome", MinVersion: 130, Features: []string{"pq"}},
+			expected: []string{"chrome_131", "chrome_131_PSK", "chrome_133", "chrome_133_PSK"},
+		},
+		{
+			name:     "版本区间与多个特性",
+			query:    fingerprint.Query{Browser: "Chrome", MinVersion: 116, MaxVersion: 130, Features: []string{"psk", "ech"}},
+			expected: []string{"chrome_130_PSK"},
+		},
+		{
+			name:     "iOS 上的 Safari 浏览器",
+			query:    fingerprint.Query{Browser: "safari", Platform: "ios", MinVersion: 18, ExcludeApps: true},
+			expected: []string{"safari_ios_18_0", "safari_ios_18_5"},
+		},
+		{
+			name:     "平板",
+			query:    fingerprint.Query{Device: "tablet"},
+			expected: []string{"safari_ipad_15_6"},
+		},
+		{
+			name:     "应用",
+			query:    fingerprint.Query{App: "okhttp4", Platform: "android", Mobile: true},
+			expected: []string{"okhttp4_android_10", "okhttp4_android_11", "okhttp4_android_12", "okhttp4_android_13", "okhttp4_android_7", "okhttp4_android_8", "okhttp4_android_9"},
+		},
+		{
+			name:     "macOS 上的 Gecko 浏览器包含不限定平台的桌面 profile",
+			query:    fingerprint.Query{Engine: "gecko", Platform: "macos", MinVersion: 133},
+			expected: []string{"firefox_133", "firefox_135"},
+		},
+		{
+			name:     "没有满足条件的 profile",
+			query:    fingerprint.Query{Browser: "firefox", Mobile: true},
+			expected: []string{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if names := fingerprint.Select(tc.query); !reflect.DeepEqual(names, tc.expected) {
+				t.Errorf("筛选结果应为 %v，实际为 %v", tc.expected, names)
+			}
+		})
+	}
+}
+
+// TestSelectPlatform 测试移动端 profile 不会匹配桌面平台
+func TestSelectPlatform(t *testing.T) {
+	for _, name := range fingerprint.Select(fingerprint.Query{Platform: "windows"}) {
+		metadata := fingerprint.MappedTLSClients[name].Metadata()
+		if metadata.Mobile() || (metadata.Platform != "" && metadata.Platform != profiles.PlatformWindows) {
+			t.Errorf("%s 不应匹配 windows 平台: %+v", name, metadata)
+		}
+	}
+	if names := fingerprint.Select(fingerprint.Query{Platform: "windows", Browser: "safari"}); len(names) != 0 {
+		t.Errorf("Safari 桌面 profile 仅限 macOS，不应匹配 windows: %v", names)
+	}
+}
+
+// TestSelectRandom 测试随机选择满足条件的 profile
+func TestSelectRandom(t *testing.T) {
+	query := fingerprint.Query{Browser: "chrome", MinVersion: 124, Platform: "linux", Features: []string{"pq"}, ExcludeApps: true}
+	for i := 0; i < 20; i++ {
+		result, err := fingerprint.SelectRandom(query)
+		if err != nil {
+			t.Fatalf("SelectRandom 失败: %v", err)
+		}
+		if !query.Match(result.Profile) {
+			t.Fatalf("返回的 profile 不满足条件: %+v", result.Profile.Metadata())
+		}
+		if !strings.Contains(result.UserAgent, "Linux") {
+			t.Errorf("指定 linux 平台时 User-Agent 应使用 Linux: %s", result.UserAgent)
+		}
+		if result.Headers == nil || result.Headers.SecCHUAPlatform != `"Linux"` {
+			t.Errorf("Sec-CH-UA-Platform 应为 Linux: %+v", result.Headers)
+		}
+	}
+
+	_, err := fingerprint.SelectRandom(fingerprint.Query{Browser: "firefox", Mobile: true})
+	var noMatch *fingerprint.ErrNoProfileMatch
+	if !errors.As(err, &noMatch) || noMatch.Query.Browser != "firefox" {
+		t.Errorf("没有满足条件的 profile 时应返回 *ErrNoProfileMatch，实际为 %v", err)
+	}
+}
+
+// TestSelectInvalidQuery 测试无效的筛选条件
+func TestSelectInvalidQuery(t *testing.T) {
+	for _, query := range []fingerprint.Query{
+		{Features: []string{"quic"}},
+		{MinVersion: 130, MaxVersion: 120},
+		{Device: "watch"},
+		{Platform: "beos"},
+		{Browser: "chorme"},
+		{Engine: "webkt"},
+	} {
+		if err := query.Validate(); err == nil {
+			t.Errorf("条件 %+v 应无效", query)
+		}
+		if names := fingerprint.Select(query); names != nil {
+			t.Errorf("无效条件应返回 nil，实际为 %v", names)
+		}
+		if names, err := fingerprint.SelectNames(query); err == nil || names != nil {
+			t.Errorf("SelectNames 应返回错误，实际为 %v, %v", names, err)
+		}
+		if _, err := fingerprint.SelectRandom(query); err == nil {
+			t.Errorf("无效条件应返回错误")
+		}
+	}
+
+	// 名称不区分大小写
+	for _, query := range []fingerprint.Query{{Browser: "Chrome"}, {Engine: "WebKit"}, {Device: "Mobile"}, {Platform: "iOS"}} {
+		if err := query.Validate(); err != nil {
+			t.Errorf("条件 %+v 应有效: %v", query, err)
+		}
+	}
+
+	// 有效条件没有匹配时不返回错误
+	if names, err := fingerprint.SelectNames(fingerprint.Query{Browser: "firefox", Mobile: true}); err != nil || len(names) != 0 {
+		t.Errorf("没有匹配时应返回空列表，实际为 %v, %v", names, err)
+	}
+}
+
+// TestQueryMatchRegistry 测试在独立注册表中筛选
+func TestQueryMatchRegistry(t *testing.T) {
+	registry := fingerprint.NewRegistry()
+	for _, name := range []string{"chrome_133", "firefox_135", "safari_ios_18_0"} {
+		if err := registry.Register(name, fingerprint.MappedTLSClients[name]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	query := fingerprint.Query{Features: []string{"ech"}}
+	matched := registry.Filter(func(_ string, profile fingerprint.ClientProfile) bool {
+		return query.Match(profile)
+	})
+	if len(matched) != 2 || matched["chrome_133"].GetClientHelloStr() == "" || matched["firefox_135"].GetClientHelloStr() == "" {
+		t.Errorf("筛选结果应为 chrome_133 和 firefox_135，实际为 %v", matched)
+	}
+}
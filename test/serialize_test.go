@@ -0,0 +1,139 @@
+package fingerprint_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/vistone/fingerprint"
+	"gopkg.in/yaml.v3"
+)
+
+// assertSameProfile 比较两个 profile 的 ClientHello 和 HTTP/2 参数
+func assertSameProfile(t *testing.T, expected, actual fingerprint.ClientProfile) {
+	t.Helper()
+
+	if actual.GetClientHelloStr() != expected.GetClientHelloStr() {
+		t.Errorf("ClientHelloID 应为 %s，实际为 %s", expected.GetClientHelloStr(), actual.GetClientHelloStr())
+	}
+
+	expectedJA3, _ := expected.JA3()
+	if got, _ := actual.JA3(); got != expectedJA3 {
+		t.Errorf("JA3 不一致\n期望: %s\n实际: %s", expectedJA3, got)
+	}
+	expectedJA4, _ := expected.JA4R()
+	if got, _ := actual.JA4R(); got != expectedJA4 {
+		t.Errorf("JA4_r 不一致\n期望: %s\n实际: %s", expectedJA4, got)
+	}
+
+	// 再次序列化的结果包含所有扩展参数，一致即说明 ClientHelloSpec 一致
+	expectedJSON, _ := json.Marshal(expected)
+	actualJSON, err := json.Marshal(actual)
+	if err != nil {
+		t.Fatalf("再次序列化失败: %v", err)
+	}
+	if string(actualJSON) != string(expectedJSON) {
+		t.Errorf("再次序列化的结果不一致\n期望: %s\n实际: %s", expectedJSON, actualJSON)
+	}
+
+	if len(actual.GetSettings()) != len(expected.GetSettings()) {
+		t.Errorf("SETTINGS 数量应为 %d，实际为 %d", len(expected.GetSettings()), len(actual.GetSettings()))
+	}
+	for id, value := range expected.GetSettings() {
+		if actual.GetSettings()[id] != value {
+			t.Errorf("SETTINGS %d 应为 %d，实际为 %d", id, value, actual.GetSettings()[id])
+		}
+	}
+	if !reflect.DeepEqual(actual.GetSettingsOrder(), expected.GetSettingsOrder()) {
+		t.Errorf("SETTINGS 顺序不一致: %v != %v", actual.GetSettingsOrder(), expected.GetSettingsOrder())
+	}
+	if actual.GetConnectionFlow() != expected.GetConnectionFlow() {
+		t.Errorf("WINDOW_UPDATE 应为 %d，实际为 %d", expected.GetConnectionFlow(), actual.GetConnectionFlow())
+	}
+	if len(actual.GetPriorities()) != len(expected.GetPriorities()) ||
+		(len(expected.GetPriorities()) > 0 && !reflect.DeepEqual(actual.GetPriorities(), expected.GetPriorities())) {
+		t.Errorf("PRIORITY 帧不一致: %v != %v", actual.GetPriorities(), expected.GetPriorities())
+	}
+	if !reflect.DeepEqual(actual.GetHeaderPriority(), expected.GetHeaderPriority()) {
+		t.Errorf("HEADERS 优先级不一致: %v != %v", actual.GetHeaderPriority(), expected.GetHeaderPriority())
+	}
+	if !reflect.DeepEqual(actual.GetPseudoHeaderOrder(), expected.GetPseudoHeaderOrder()) {
+		t.Errorf("伪头部顺序不一致: %v != %v", actual.GetPseudoHeaderOrder(), expected.GetPseudoHeaderOrder())
+	}
+}
+
+// TestProfileJSONRoundTrip 测试所有 profile 的 JSON 序列化往返
+func TestProfileJSONRoundTrip(t *testing.T) {
+	for name, profile := range fingerprint.MappedTLSClients {
+		t.Run(name, func(t *testing.T) {
+			data, err := json.Marshal(profile)
+			if err != nil {
+				t.Fatalf("序列化失败: %v", err)
+			}
+
+			var decoded fingerprint.ClientProfile
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("反序列化失败: %v", err)
+			}
+			assertSameProfile(t, profile, decoded)
+		})
+	}
+}
+
+// TestProfileYAMLRoundTrip 测试所有 profile 的 YAML 序列化往返
+func TestProfileYAMLRoundTrip(t *testing.T) {
+	for name, profile := range fingerprint.MappedTLSClients {
+		t.Run(name, func(t *testing.T) {
+			data, err := yaml.Marshal(profile)
+			if err != nil {
+				t.Fatalf("序列化失败: %v", err)
+			}
+
+			var decoded fingerprint.ClientProfile
+			if err := yaml.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("反序列化失败: %v\n%s", err, data)
+			}
+			assertSameProfile(t, profile, decoded)
+		})
+	}
+}
+
+// TestProfileJSONFormat 测试 JSON 格式中的关键字段
+func TestProfileJSONFormat(t *testing.T) {
+	data, err := json.Marshal(fingerprint.MappedTLSClients["chrome_133"])
+	if err != nil {
+		t.Fatalf("序列化失败: %v", err)
+	}
+
+	for _, expected := range []string{
+		`"client_hello_id":{"client":"Chrome","version":"133"}`,
+		`"cipher_suites":["GREASE","0x1301","0x1302","0x1303"`,
+		`{"name":"application_settings_new","protocols":["h3","h2"]}`,
+		`{"name":"key_share","key_shares":[{"group":"GREASE","data":"00"},{"group":"0x11ec"},{"group":"0x001d"}]}`,
+		`"settings":[{"id":1,"value":65536},{"id":2,"value":0},{"id":4,"value":6291456},{"id":6,"value":262144}]`,
+		`"pseudo_header_order":[":method",":authority",":scheme",":path"]`,
+	} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("JSON 中缺少 %s\n%s", expected, data)
+		}
+	}
+}
+
+// TestProfileJSONInvalid 测试无效的 JSON 输入
+func TestProfileJSONInvalid(t *testing.T) {
+	cases := map[string]string{
+		"未知扩展":   `{"client_hello_id":{"client":"X","version":"1"},"tls":{"cipher_suites":["0x1301"],"compression_methods":[0],"extensions":[{"name":"unknown"}]},"http2":{}}`,
+		"无效密码套件": `{"client_hello_id":{"client":"X","version":"1"},"tls":{"cipher_suites":["tls13"],"compression_methods":[0],"extensions":[]},"http2":{}}`,
+		"重复设置":   `{"client_hello_id":{"client":"X","version":"1"},"tls":{"cipher_suites":["0x1301"],"compression_methods":[0],"extensions":[]},"http2":{"settings":[{"id":1,"value":1},{"id":1,"value":2}]}}`,
+	}
+
+	for name, input := range cases {
+		t.Run(name, func(t *testing.T) {
+			var profile fingerprint.ClientProfile
+			if err := json.Unmarshal([]byte(input), &profile); err == nil {
+				t.Errorf("应返回错误")
+			}
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package fingerprint_test
+
+import (
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vistone/fingerprint"
+)
+
+// assertShare 检查 counts 中 key 的占比与权重占比的偏差不超过 tolerance
+func assertShare[K comparable](t *testing.T, counts map[K]int, total int, key K, expected, tolerance float64) {
+	t.Helper()
+	share := float64(counts[key]) / float64(total)
+	if math.Abs(share-expected) > tolerance {
+		t.Errorf("%v 的占比应接近 %.4f，实际为 %.4f", key, expected, share)
+	}
+}
+
+// TestWeightedProfileDistribution 测试按权重选择 profile 的分布
+func TestWeightedProfileDistribution(t *testing.T) {
+	weights := fingerprint.DefaultWeights()
+	names := fingerprint.DefaultRegistry.List()
+
+	totalWeight := 0.0
+	for _, name := range names {
+		totalWeight += weights.ProfileWeight(name)
+	}
+
+	const draws = 200000
+	rng := rand.New(rand.NewSource(42))
+	counts := make(map[string]int)
+	for i := 0; i < draws; i++ {
+		name, ok := weights.ChooseProfile(rng, names)
+		if !ok {
+			t.Fatalf("ChooseProfile 应返回 profile")
+		}
+		counts[name]++
+	}
+
+	for _, name := range []string{"chrome_133", "chrome_131", "safari_ios_18_5", "firefox_135", "chrome_103"} {
+		assertShare(t, counts, draws, name, weights.ProfileWeight(name)/totalWeight, 0.005)
+	}
+	if counts["chrome_133"] < 50*counts["chrome_103"] {
+		t.Errorf("chrome_133 应远多于 chrome_103: %d vs %d", counts["chrome_133"], counts["chrome_103"])
+	}
+	for name, profile := range fingerprint.MappedTLSClients {
+		if profile.Metadata().App != "" && counts[name] > 0 {
+			t.Errorf("应用 profile %s 默认不应被选中", name)
+		}
+	}
+}
+
+// TestWeightedChoiceDeterministic 测试相同种子得到相同的选择序列
+func TestWeightedChoiceDeterministic(t *testing.T) {
+	weights := fingerprint.DefaultWeights()
+	names := fingerprint.DefaultRegistry.List()
+
+	sequence := func(seed int64) []string {
+		rng := rand.New(rand.NewSource(seed))
+		result := make([]string, 0, 60)
+		for i := 0; i < 20; i++ {
+			name, _ := weights.ChooseProfile(rng, names)
+			result = append(result, name, weights.ChooseOS(rng).String(), strings.Join(weights.ChooseLanguage(rng), ","))
+		}
+		return result
+	}
+
+	first, second := sequence(7), sequence(7)
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("相同种子的第 %d 次选择不一致: %s != %s", i, first[i], second[i])
+		}
+	}
+
+	// names 的顺序不影响结果
+	reversed := make([]string, len(names))
+	for i, name := range names {
+		reversed[len(names)-1-i] = name
+	}
+	a, _ := weights.ChooseProfile(rand.New(rand.NewSource(3)), names)
+	b, _ := weights.ChooseProfile(rand.New(rand.NewSource(3)), reversed)
+	if a != b {
+		t.Errorf("names 顺序不同时结果应一致: %s != %s", a, b)
+	}
+}
+
+// TestWeightedOSAndLanguageDistribution 测试操作系统和语言的加权分布
+func TestWeightedOSAndLanguageDistribution(t *testing.T) {
+	weights := fingerprint.DefaultWeights()
+
+	const draws = 100000
+	rng := rand.New(rand.NewSource(1))
+	systems := make(map[fingerprint.OperatingSystem]int)
+	languages := make(map[string]int)
+	for i := 0; i < draws; i++ {
+		systems[weights.ChooseOS(rng)]++
+		languages[strings.Join(weights.ChooseLanguage(rng), ",")]++
+	}
+
+	// 默认权重从 OperatingSystems 和 Languages 列表中按市场份额选择，列表中重复的操作系统只计算一次
+	osTotal := 0.0
+	for _, os := range []fingerprint.OperatingSystem{fingerprint.OSWindows10, fingerprint.OSWindows11, fingerprint.OSMacOS12, fingerprint.OSMacOS13, fingerprint.OSMacOS14, fingerprint.OSMacOS15, fingerprint.OSLinux} {
+		osTotal += weights.OSWeight(os)
+	}
+	languageTotal := 0.0
+	for _, language := range fingerprint.Languages {
+		languageTotal += weights.LanguageWeight(strings.Join(language, ","))
+	}
+
+	assertShare(t, systems, draws, fingerprint.OSWindows10, weights.OSWeight(fingerprint.OSWindows10)/osTotal, 0.01)
+	assertShare(t, systems, draws, fingerprint.OSLinux, weights.OSWeight(fingerprint.OSLinux)/osTotal, 0.01)
+	assertShare(t, languages, draws, "en-US", weights.LanguageWeight("en-US")/languageTotal, 0.01)
+	assertShare(t, languages, draws, "pt-PT,en", weights.LanguageWeight("pt-PT,en")/languageTotal, 0.005)
+
+	// 权重都为 0 时均匀选择
+	uniform := &fingerprint.Weights{Languages: map[string]float64{"en-US": 0}}
+	counts := make(map[string]int)
+	for i := 0; i < draws; i++ {
+		counts[strings.Join(uniform.ChooseLanguage(rng), ",")]++
+	}
+	for _, language := range fingerprint.Languages {
+		assertShare(t, counts, draws, strings.Join(language, ","), 1/float64(len(fingerprint.Languages)), 0.005)
+	}
+}
+
+// TestDefaultWeightsFollowLists 测试默认权重使用 OperatingSystems 和 Languages 列表，修改列表会影响随机选择
+func TestDefaultWeightsFollowLists(t *testing.T) {
+	languages, systems := fingerprint.Languages, fingerprint.OperatingSystems
+	t.Cleanup(func() { fingerprint.Languages, fingerprint.OperatingSystems = languages, systems })
+	fingerprint.Languages = [][]string{{"gsw-CH", "de"}}
+	fingerprint.OperatingSystems = []fingerprint.OperatingSystem{fingerprint.OSLinuxARM64}
+
+	weights := fingerprint.DefaultWeights()
+	if weights.LanguageWeight("gsw-CH,de") != 1 || weights.LanguageWeight("en-US") != 0 || weights.OSWeight(fingerprint.OSWindows10) != 0 {
+		t.Errorf("默认权重应只包含列表中的项")
+	}
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		if locales := weights.ChooseLanguage(rng); strings.Join(locales, ",") != "gsw-CH,de" {
+			t.Fatalf("应从 Languages 列表中选择，实际为 %v", locales)
+		}
+		if os := weights.ChooseOS(rng); os != fingerprint.OSLinuxARM64 {
+			t.Fatalf("应从 OperatingSystems 列表中选择，实际为 %v", os)
+		}
+	}
+}
+
+// TestLoadedAppProfileWeight 测试运行时加载的应用 profile 默认不参与加权随机选择
+func TestLoadedAppProfileWeight(t *testing.T) {
+	app := fingerprint.MappedTLSClients["okhttp4_android_13"]
+	if err := fingerprint.DefaultRegistry.Register("loaded_app", app); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { fingerprint.DefaultRegistry.Unregister("loaded_app") })
+
+	weights := fingerprint.DefaultWeights()
+	if weights.ProfileWeight("loaded_app") != 0 || weights.ProfileWeight("chrome_140_unknown") != weights.DefaultProfileWeight {
+		t.Errorf("未列出的应用 profile 权重应为 0: %v", weights.ProfileWeight("loaded_app"))
+	}
+}
+
+// TestSetWeights 测试包级函数使用设置的权重
+func TestSetWeights(t *testing.T) {
+	t.Cleanup(func() { _ = fingerprint.SetWeights(nil) })
+
+	err := fingerprint.SetWeights(&fingerprint.Weights{
+		Profiles:         map[string]float64{"firefox_135": 1},
+		OperatingSystems: map[fingerprint.OperatingSystem]float64{fingerprint.OSMacOS14: 1},
+		Languages:        map[string]float64{"de-DE,de;q=0.9,en;q=0.8": 1},
+	})
+	if err != nil {
+		t.Fatalf("SetWeights 失败: %v", err)
+	}
+
+	for i := 0; i < 20; i++ {
+		result, err := fingerprint.GetWeightedRandomFingerprint()
+		if err != nil {
+			t.Fatalf("GetWeightedRandomFingerprint 失败: %v", err)
+		}
+		if result.HelloClientID != fingerprint.MappedTLSClients["firefox_135"].GetClientHelloStr() {
+			t.Fatalf("只有 firefox_135 的权重大于 0，实际选中 %s", result.HelloClientID)
+		}
+		if result.UserAgent != "Mozilla/5.0 (Macintosh; Intel Mac OS X 10.15; rv:135.0) Gecko/20100101 Firefox/135.0" {
+			t.Errorf("User-Agent 应使用加权选择的操作系统: %s", result.UserAgent)
+		}
+		// Accept-Language 格式的权重键按 Firefox 的规则重新生成
+		if result.Headers.AcceptLanguage != "de-DE,de;q=0.7,en;q=0.3" {
+			t.Errorf("Accept-Language 应使用加权选择的语言: %s", result.Headers.AcceptLanguage)
+		}
+	}
+	if os := fingerprint.RandomOS(); os != fingerprint.OSMacOS14 {
+		t.Errorf("RandomOS 应使用设置的权重: %s", os)
+	}
+
+	if err := fingerprint.SetWeights(&fingerprint.Weights{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fingerprint.GetWeightedRandomFingerprint(); err == nil {
+		t.Errorf("所有 profile 的权重都为 0 时应返回错误")
+	}
+	if err := fingerprint.SetWeights(&fingerprint.Weights{Profiles: map[string]float64{"chrome_133": -1}}); err == nil {
+		t.Errorf("负数权重应返回错误")
+	}
+}
+
+// TestLoadWeights 测试从 JSON 和 YAML 文件加载权重
+func TestLoadWeights(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"weights.json": `{"default_profile_weight": 0.5, "profiles": {"chrome_133": 10, "opera_89": 0},
+			"operating_systems": {"X11; Linux x86_64": 3}, "languages": {"fr-FR,fr;q=0.9,en;q=0.8": 2}}`,
+		"weights.yaml": "default_profile_weight: 0.5\nprofiles:\n  chrome_133: 10\n  opera_89: 0\n" +
+			"operating_systems:\n  linux-x86_64: 3\nlanguages:\n  \"fr-FR,fr;q=0.9,en;q=0.8\": 2\n",
+	}
+
+	for file, data := range files {
+		t.Run(file, func(t *testing.T) {
+			path := filepath.Join(dir, file)
+			if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			weights, err := fingerprint.LoadWeights(path)
+			if err != nil {
+				t.Fatalf("LoadWeights 失败: %v", err)
+			}
+			if weights.ProfileWeight("chrome_133") != 10 || weights.ProfileWeight("opera_89") != 0 || weights.ProfileWeight("firefox_135") != 0.5 {
+				t.Errorf("profile 权重不正确: %+v", weights.Profiles)
+			}
+			if weights.OperatingSystems[fingerprint.OSLinux] != 3 || weights.Languages["fr-FR,fr;q=0.9,en;q=0.8"] != 2 {
+				t.Errorf("操作系统或语言权重不正确: %+v", weights)
+			}
+		})
+	}
+
+	if _, err := fingerprint.ParseWeights([]byte(`{"languages": {"en-US": -2}}`)); err == nil {
+		t.Errorf("负数权重应返回错误")
+	}
+	if _, err := fingerprint.ParseWeights([]byte(`{"operating_systems": {"beos-5": 1}}`)); err == nil {
+		t.Errorf("无法识别的操作系统应返回错误")
+	}
+	if _, err := fingerprint.LoadWeights(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Errorf("文件不存在时应返回错误")
+	}
+}
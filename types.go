@@ -11,24 +11,11 @@ const (
 	BrowserEdge    BrowserType = "edge"
 )
 
-// OperatingSystem 操作系统类型
-type OperatingSystem string
-
-const (
-	OSWindows10   OperatingSystem = "Windows NT 10.0; Win64; x64"
-	OSWindows11   OperatingSystem = "Windows NT 10.0; Win64; x64"
-	OSMacOS13     OperatingSystem = "Macintosh; Intel Mac OS X 13_0_0"
-	OSMacOS14     OperatingSystem = "Macintosh; Intel Mac OS X 14_0_0"
-	OSMacOS15     OperatingSystem = "Macintosh; Intel Mac OS X 15_0_0"
-	OSLinux       OperatingSystem = "X11; Linux x86_64"
-	OSLinuxUbuntu OperatingSystem = "X11; Linux x86_64"
-	OSLinuxDebian OperatingSystem = "X11; Linux x86_64"
-)
-
-// OperatingSystems 操作系统列表（用于随机选择）
+// OperatingSystems 操作系统列表（用于随机选择），权重不含操作系统时按内置的市场份额从中选择
 var OperatingSystems = []OperatingSystem{
 	OSWindows10,
 	OSWindows11,
+	OSMacOS12,
 	OSMacOS13,
 	OSMacOS14,
 	OSMacOS15,
@@ -39,10 +26,14 @@ var OperatingSystems = []OperatingSystem{
 
 // FingerprintResult 指纹结果，包含指纹、User-Agent 和标准 HTTP Headers
 type FingerprintResult struct {
-	Profile       ClientProfile // 指纹配置
-	UserAgent     string        // 对应的 User-Agent
-	HelloClientID string        // Client Hello ID（与 tls-client 保持一致）
-	Headers       *HTTPHeaders  // 标准 HTTP 请求头（包含全球语言支持）
+	Profile       ClientProfile   // 指纹配置
+	UserAgent     string          // 对应的 User-Agent
+	HelloClientID string          // Client Hello ID（与 tls-client 保持一致）
+	Headers       *HTTPHeaders    // 标准 HTTP 请求头（包含全球语言支持）
+	OS            OperatingSystem // 操作系统，User-Agent 不包含操作系统信息（移动端、固定 User-Agent）时为零值
+	Locales       []string        // 按偏好排序的 locale 列表（navigator.languages），Accept-Language 由其生成
+	Country       string          // 使用 Persona 时选中的国家/地区代码（ISO 3166-1 alpha-2），否则为空
+	Timezone      *Timezone       // 使用 Persona 时选中的时区，否则为 nil
 }
 
 // HTTPHeaders 标准的 HTTP 请求头
@@ -58,8 +49,24 @@ type HTTPHeaders struct {
 	SecCHUA                 string            // Sec-CH-UA 头
 	SecCHUAMobile           string            // Sec-CH-UA-Mobile 头
 	SecCHUAPlatform         string            // Sec-CH-UA-Platform 头
+	SecCHUAFullVersionList  string            // Sec-CH-UA-Full-Version-List 头（高熵，见 ApplyAcceptCH）
+	SecCHUAPlatformVersion  string            // Sec-CH-UA-Platform-Version 头（高熵）
+	SecCHUAArch             string            // Sec-CH-UA-Arch 头（高熵）
+	SecCHUABitness          string            // Sec-CH-UA-Bitness 头（高熵）
+	SecCHUAModel            string            // Sec-CH-UA-Model 头（高熵）
+	SecCHUAWoW64            string            // Sec-CH-UA-WoW64 头（高熵）
+	SecCHUAFormFactors      string            // Sec-CH-UA-Form-Factors 头（高熵）
 	UpgradeInsecureRequests string            // Upgrade-Insecure-Requests 头
+	Priority                string            // Priority 头（RFC 9218，只在 HTTP/2 和 HTTP/3 中发送）
+	TE                      string            // TE 头（Firefox 发送 trailers）
 	Custom                  map[string]string // 用户自定义的 headers（如 Cookie、Authorization、X-API-Key 等）
+
+	HeaderOrder       []string // header 发送顺序（小写名称，与浏览器实际顺序一致）
+	PseudoHeaderOrder []string // HTTP/2 伪头部顺序（来自 ClientProfile.GetPseudoHeaderOrder()）
+	CustomPosition    string   // 不在 HeaderOrder 中的自定义 header 插入到该 header 之后，为空时追加到末尾
+
+	customOrder []string          // 自定义 header 的设置顺序
+	clientHints map[string]string // 浏览器可以发送的所有 client hints（小写名称 → 值），由 ApplyAcceptCH 使用
 }
 
 // UserAgentTemplate User-Agent 模板
@@ -4,7 +4,7 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/vistone/fingerprint/internal/utils"
+	"github.com/vistone/fingerprint/profiles"
 )
 
 // UserAgentGenerator User-Agent 生成器
@@ -100,7 +100,8 @@ func (g *UserAgentGenerator) initTemplates() {
 	}
 
 	for key, template := range safariTemplates {
-		mobile := strings.Contains(key, "ios") || strings.Contains(key, "ipad")
+		profile, _ := profiles.DefaultRegistry.Get("safari_" + key)
+		mobile := profile.Metadata().Mobile()
 		g.templates["safari_"+key] = UserAgentTemplate{
 			Browser:    BrowserSafari,
 			Version:    key,
@@ -126,169 +127,144 @@ func (g *UserAgentGenerator) initTemplates() {
 		}
 	}
 
-	// 移动端和自定义指纹的 User-Agent 模板
-	// iOS 应用指纹 - 使用 iOS Safari User-Agent
-	iosAppTemplates := map[string]string{
-		"zalando_ios_mobile": "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile/15E148 Safari/604.1",
-		"nike_ios_mobile":    "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile/15E148 Safari/604.1",
-		"mms_ios":            "Mozilla/5.0 (iPhone; CPU iPhone OS 16_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.0 Mobile/15E148 Safari/604.1",
-		"mms_ios_2":          "Mozilla/5.0 (iPhone; CPU iPhone OS 16_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.0 Mobile/15E148 Safari/604.1",
-		"mms_ios_3":          "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile/15E148 Safari/604.1",
-		"mesh_ios":           "Mozilla/5.0 (iPhone; CPU iPhone OS 16_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.0 Mobile/15E148 Safari/604.1",
-		"mesh_ios_2":         "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile/15E148 Safari/604.1",
-		"confirmed_ios":      "Mozilla/5.0 (iPhone; CPU iPhone OS 16_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.0 Mobile/15E148 Safari/604.1",
+	// Edge User-Agent 模板（Chromium 版本与 Edge 版本相同）
+	edgeTemplates := map[string]string{
+		"131": "Mozilla/5.0 (%s) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Safari/537.36 Edg/131.0.0.0",
+		"133": "Mozilla/5.0 (%s) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/133.0.0.0 Safari/537.36 Edg/133.0.0.0",
 	}
 
-	for key, template := range iosAppTemplates {
-		g.templates[key] = UserAgentTemplate{
-			Browser:    BrowserSafari,
-			Version:    "ios",
-			Template:   template,
-			Mobile:     true,
-			OSRequired: false, // iOS 移动端不需要操作系统占位符
-		}
-	}
-
-	// Android 应用指纹 - 使用 Android Chrome User-Agent
-	androidAppTemplates := map[string]string{
-		"zalando_android_mobile": "Mozilla/5.0 (Linux; Android 13; SM-G991B) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36",
-		"nike_android_mobile":    "Mozilla/5.0 (Linux; Android 13; Pixel 7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36",
-		"mesh_android":           "Mozilla/5.0 (Linux; Android 12; SM-G998B) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36",
-		"mesh_android_2":         "Mozilla/5.0 (Linux; Android 13; Pixel 6) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36",
-		"confirmed_android":      "Mozilla/5.0 (Linux; Android 12; SM-G998B) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36",
-		"confirmed_android_2":    "Mozilla/5.0 (Linux; Android 13; Pixel 7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36",
-	}
-
-	for key, template := range androidAppTemplates {
-		g.templates[key] = UserAgentTemplate{
-			Browser:    BrowserChrome,
-			Version:    "android",
-			Template:   template,
-			Mobile:     true,
-			OSRequired: false, // Android 移动端不需要操作系统占位符
-		}
-	}
-
-	// OkHttp4 Android 指纹 - 使用 Android Chrome User-Agent（不同 Android 版本）
-	okhttpTemplates := map[string]string{
-		"okhttp4_android_7":  "Mozilla/5.0 (Linux; Android 7.0; SM-G930F) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36",
-		"okhttp4_android_8":  "Mozilla/5.0 (Linux; Android 8.0; SM-G950F) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36",
-		"okhttp4_android_9":  "Mozilla/5.0 (Linux; Android 9; SM-G960F) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36",
-		"okhttp4_android_10": "Mozilla/5.0 (Linux; Android 10; SM-G970F) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36",
-		"okhttp4_android_11": "Mozilla/5.0 (Linux; Android 11; SM-G991B) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36",
-		"okhttp4_android_12": "Mozilla/5.0 (Linux; Android 12; SM-G998B) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36",
-		"okhttp4_android_13": "Mozilla/5.0 (Linux; Android 13; Pixel 7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36",
-	}
-
-	for key, template := range okhttpTemplates {
-		g.templates[key] = UserAgentTemplate{
-			Browser:    BrowserChrome,
-			Version:    "okhttp4",
+	for version, template := range edgeTemplates {
+		g.templates["edge_"+version] = UserAgentTemplate{
+			Browser:    BrowserEdge,
+			Version:    version,
 			Template:   template,
-			Mobile:     true,
-			OSRequired: false, // Android 移动端不需要操作系统占位符
+			OSRequired: true,
 		}
 	}
 
-	// Cloudflare Custom - 使用 Chrome User-Agent（通常用于 cloudscraper）
-	g.templates["cloudflare_custom"] = UserAgentTemplate{
-		Browser:    BrowserChrome,
-		Version:    "custom",
-		Template:   "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
-		Mobile:     false,
-		OSRequired: false, // 固定 User-Agent，不需要操作系统占位符
+	// Android 上的 Edge 使用 EdgA 标识
+	g.templates["edge_android_131"] = UserAgentTemplate{
+		Browser:    BrowserEdge,
+		Version:    "131",
+		Template:   "Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Mobile Safari/537.36 EdgA/131.0.0.0",
+		Mobile:     true,
+		OSRequired: false, // Android 移动端不需要操作系统占位符
 	}
 }
 
 // GetUserAgent 根据指纹名称获取 User-Agent
 // 如果指纹需要操作系统信息，会随机选择一个操作系统
 func (g *UserAgentGenerator) GetUserAgent(profileName string) (string, error) {
-	return g.GetUserAgentWithOS(profileName, OperatingSystem(""))
+	return g.GetUserAgentWithOS(profileName, OperatingSystem{})
 }
 
 // GetUserAgentWithOS 根据指纹名称和指定操作系统获取 User-Agent
-// 如果 os 为空，且需要操作系统信息，会随机选择一个操作系统
+// 如果 os 为零值，且需要操作系统信息，会随机选择一个与 profile 兼容的操作系统；
+// User-Agent 中的操作系统部分按浏览器的冻结规则生成（见 OperatingSystem.UserAgentToken）；
+// profile 不能运行在 os 上时（如桌面 Safari 搭配 Windows，见 CompatibleOS）返回 *ErrIncompatibleOS
 func (g *UserAgentGenerator) GetUserAgentWithOS(profileName string, os OperatingSystem) (string, error) {
+	ua, _, err := g.userAgentFor(profiles.DefaultRegistry, profileName, os)
+	return ua, err
+}
+
+// userAgentFor 根据指纹名称和操作系统获取 User-Agent，非模板 profile 从 registry 中查找
+// 同时返回实际使用的操作系统（os 为零值时随机选择的操作系统），User-Agent 不包含操作系统信息时返回零值
+func (g *UserAgentGenerator) userAgentFor(registry *profiles.Registry, profileName string, os OperatingSystem) (string, OperatingSystem, error) {
 	if profileName == "" {
-		return "", fmt.Errorf("profile name cannot be empty")
+		return "", OperatingSystem{}, fmt.Errorf("profile name cannot be empty")
+	}
+	profile, _ := registry.Get(profileName)
+	if err := checkOS(profileName, profile.Metadata(), os); err != nil {
+		return "", OperatingSystem{}, err
 	}
+
 	template, ok := g.templates[profileName]
 	if !ok {
-		// 尝试从 profileName 中提取浏览器类型和版本
-		return g.generateFromProfileName(profileName, os)
+		// 通过 profiles.LoadDir/LoadFS 加载的 profile 使用文件中的 User-Agent 模板
+		if def, loaded := registry.Definition(profileName); loaded && def.UserAgent != "" {
+			template = UserAgentTemplate{
+				Browser:    BrowserType(def.Browser),
+				Template:   def.UserAgent,
+				Mobile:     def.Mobile,
+				OSRequired: strings.Contains(def.UserAgent, "%s"),
+			}
+		} else {
+			// 根据注册表中 profile 的 metadata 生成
+			return g.generateFromMetadata(profile.Metadata(), os)
+		}
 	}
 
 	// 如果不需要操作系统信息，直接返回模板
 	if !template.OSRequired {
-		return template.Template, nil
+		return template.Template, OperatingSystem{}, nil
 	}
 
 	// 如果需要操作系统信息
-	if os == "" {
-		// 随机选择操作系统
-		os = RandomOS()
+	if os.IsZero() {
+		// 按当前权重随机选择与 profile 兼容的操作系统
+		if os = GetWeights().ChooseOSFor(nil, profile.Metadata()); os.IsZero() {
+			return "", OperatingSystem{}, &ErrIncompatibleOS{Profile: profileName}
+		}
 	}
 
-	return fmt.Sprintf(template.Template, string(os)), nil
+	return fmt.Sprintf(template.Template, os.UserAgentToken(template.Browser)), os, nil
 }
 
-// generateFromProfileName 从 profile 名称生成 User-Agent
-func (g *UserAgentGenerator) generateFromProfileName(profileName string, os OperatingSystem) (string, error) {
-	profileName = strings.ToLower(profileName)
-
-	// 解析浏览器类型和版本
-	var browser BrowserType
-	var version string
-
-	if strings.HasPrefix(profileName, "chrome_") {
-		browser = BrowserChrome
-		version = strings.TrimPrefix(profileName, "chrome_")
-		// 处理特殊版本
-		if strings.Contains(version, "_psk") {
-			version = strings.Split(version, "_psk")[0]
-		}
-		if strings.Contains(version, "_pq") {
-			version = strings.Split(version, "_pq")[0]
-		}
-	} else if strings.HasPrefix(profileName, "firefox_") {
-		browser = BrowserFirefox
-		version = strings.TrimPrefix(profileName, "firefox_")
-	} else if strings.HasPrefix(profileName, "safari_") {
-		browser = BrowserSafari
-		version = strings.TrimPrefix(profileName, "safari_")
-	} else if strings.HasPrefix(profileName, "opera_") {
-		browser = BrowserOpera
-		version = strings.TrimPrefix(profileName, "opera_")
-	} else {
-		// 默认使用 Chrome 133
-		return g.GetUserAgentWithOS("chrome_133", os)
+// generateFromMetadata 根据 profile 的 metadata 生成 User-Agent，应用 profile 的 User-Agent 见 appUserAgent
+// 没有 metadata（浏览器类型或版本未知）时使用 Chrome 133；同时返回实际使用的操作系统，移动端返回零值
+func (g *UserAgentGenerator) generateFromMetadata(metadata profiles.Metadata, os OperatingSystem) (string, OperatingSystem, error) {
+	// 应用 profile 使用其网络库的 User-Agent，与 headers 一致
+	if metadata.App != "" {
+		return appUserAgent(metadata), OperatingSystem{}, nil
+	}
+	if metadata.Browser == "" || metadata.MajorVersion == 0 {
+		return g.userAgentFor(profiles.DefaultRegistry, "chrome_133", os)
+	}
+	if !os.IsZero() && !compatibleOS(metadata, os) {
+		return "", OperatingSystem{}, &ErrIncompatibleOS{Browser: string(metadata.Browser), OS: os}
+	}
+	major := metadata.MajorVersion
+
+	// 移动端 User-Agent 的操作系统部分由平台决定
+	switch {
+	case metadata.Platform == profiles.PlatformIOS && metadata.Browser == profiles.BrowserSafari:
+		return fmt.Sprintf("Mozilla/5.0 (iPhone; CPU iPhone OS %d_%d like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/%s Mobile/15E148 Safari/604.1", major, metadata.MinorVersion, metadata.Version()), OperatingSystem{}, nil
+	case metadata.Platform == profiles.PlatformIPadOS && metadata.Browser == profiles.BrowserSafari:
+		return fmt.Sprintf("Mozilla/5.0 (iPad; CPU OS %d_%d like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/%s Mobile/15E148 Safari/604.1", major, metadata.MinorVersion, metadata.Version()), OperatingSystem{}, nil
+	case metadata.Platform == profiles.PlatformAndroid && metadata.Browser == profiles.BrowserChrome:
+		return fmt.Sprintf("Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/%d.0.0.0 Mobile Safari/537.36", major), OperatingSystem{}, nil
+	case metadata.Platform == profiles.PlatformAndroid && metadata.Browser == profiles.BrowserEdge:
+		return fmt.Sprintf("Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/%d.0.0.0 Mobile Safari/537.36 EdgA/%d.0.0.0", major, major), OperatingSystem{}, nil
+	case metadata.Platform == profiles.PlatformAndroid && metadata.Browser == profiles.BrowserFirefox:
+		return fmt.Sprintf("Mozilla/5.0 (Android 10; Mobile; rv:%d.0) Gecko/%d.0 Firefox/%d.0", major, major, major), OperatingSystem{}, nil
 	}
 
-	// 生成 User-Agent
-	if os == "" {
-		os = RandomOS()
+	if os.IsZero() {
+		if os = GetWeights().ChooseOSFor(nil, metadata); os.IsZero() {
+			return "", OperatingSystem{}, &ErrIncompatibleOS{Browser: string(metadata.Browser)}
+		}
 	}
 
-	switch browser {
-	case BrowserChrome:
-		return fmt.Sprintf("Mozilla/5.0 (%s) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/%s.0.0.0 Safari/537.36", string(os), version), nil
-	case BrowserFirefox:
-		return fmt.Sprintf("Mozilla/5.0 (%s; rv:%s.0) Gecko/20100101 Firefox/%s.0", string(os), version, version), nil
-	case BrowserSafari:
-		return fmt.Sprintf("Mozilla/5.0 (%s) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/%s Safari/605.1.15", string(os), version), nil
-	case BrowserOpera:
-		return fmt.Sprintf("Mozilla/5.0 (%s) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/%s.0.0.0 Safari/537.36 OPR/%s.0.0.0", string(os), version, version), nil
+	token := os.UserAgentToken(BrowserType(metadata.Browser))
+	switch metadata.Browser {
+	case profiles.BrowserChrome:
+		return fmt.Sprintf("Mozilla/5.0 (%s) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/%d.0.0.0 Safari/537.36", token, major), os, nil
+	case profiles.BrowserEdge:
+		return fmt.Sprintf("Mozilla/5.0 (%s) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/%d.0.0.0 Safari/537.36 Edg/%d.0.0.0", token, major, major), os, nil
+	case profiles.BrowserFirefox:
+		return fmt.Sprintf("Mozilla/5.0 (%s; rv:%d.0) Gecko/20100101 Firefox/%d.0", token, major, major), os, nil
+	case profiles.BrowserSafari:
+		return fmt.Sprintf("Mozilla/5.0 (%s) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/%s Safari/605.1.15", token, metadata.Version()), os, nil
+	case profiles.BrowserOpera:
+		return fmt.Sprintf("Mozilla/5.0 (%s) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/%d.0.0.0 Safari/537.36 OPR/%d.0.0.0", token, major, major), os, nil
 	default:
-		return "", fmt.Errorf("unsupported browser type: %s", browser)
+		return "", OperatingSystem{}, fmt.Errorf("unsupported browser type: %s", metadata.Browser)
 	}
 }
 
-// RandomOS 随机选择一个操作系统
+// RandomOS 按当前权重（见 SetWeights）随机选择一个操作系统
 func RandomOS() OperatingSystem {
-	if len(OperatingSystems) == 0 {
-		return OSWindows10 // 默认返回 Windows 10
-	}
-	return utils.RandomChoice(OperatingSystems)
+	return defaultFingerprintGenerator.RandomOS()
 }
 
 // GetUserAgentForProfile 为指定的 ClientProfile 获取 User-Agent
@@ -3,6 +3,8 @@ package fingerprint
 import (
 	"fmt"
 	"strings"
+
+	"github.com/vistone/fingerprint/profiles"
 )
 
 // GetUserAgentByProfileName 根据 profile 名称获取 User-Agent
@@ -23,19 +25,21 @@ func GetUserAgentByProfileNameWithOS(profileName string, os OperatingSystem) (st
 }
 
 // GetUserAgentFromProfile 从 ClientProfile 对象获取 User-Agent
-// 通过查找 MappedTLSClients 来匹配对应的 profile 名称
+// 通过查找 DefaultRegistry 来匹配对应的 profile 名称
 func GetUserAgentFromProfile(profile ClientProfile) (string, error) {
 	// 通过 ClientHelloStr 查找对应的 profile 名称
 	helloStr := profile.GetClientHelloStr()
+	if name, ok := findProfileName(helloStr); ok {
+		return GetUserAgentForProfile(name)
+	}
 
-	// 遍历 MappedTLSClients 查找匹配的 profile
-	for name, p := range MappedTLSClients {
-		if p.GetClientHelloStr() == helloStr {
-			return GetUserAgentForProfile(name)
-		}
+	// 未注册的 profile 根据 metadata 生成
+	if metadata := profile.Metadata(); metadata.Browser != "" {
+		ua, _, err := defaultGenerator.generateFromMetadata(metadata, OperatingSystem{})
+		return ua, err
 	}
 
-	// 如果找不到，尝试从 helloStr 中推断浏览器类型
+	// 没有 metadata 时，尝试从 helloStr 中推断浏览器类型
 	helloStrLower := strings.ToLower(helloStr)
 	if strings.Contains(helloStrLower, "chrome") {
 		return GetUserAgentForProfile("chrome_133")
@@ -53,11 +57,12 @@ func GetUserAgentFromProfile(profile ClientProfile) (string, error) {
 // GetUserAgentFromProfileWithOS 从 ClientProfile 对象获取 User-Agent，并指定操作系统
 func GetUserAgentFromProfileWithOS(profile ClientProfile, os OperatingSystem) (string, error) {
 	helloStr := profile.GetClientHelloStr()
-
-	for name, p := range MappedTLSClients {
-		if p.GetClientHelloStr() == helloStr {
-			return GetUserAgentForProfileWithOS(name, os)
-		}
+	if name, ok := findProfileName(helloStr); ok {
+		return GetUserAgentForProfileWithOS(name, os)
+	}
+	if metadata := profile.Metadata(); metadata.Browser != "" {
+		ua, _, err := defaultGenerator.generateFromMetadata(metadata, os)
+		return ua, err
 	}
 
 	helloStrLower := strings.ToLower(helloStr)
@@ -74,25 +79,26 @@ func GetUserAgentFromProfileWithOS(profile ClientProfile, os OperatingSystem) (s
 	return "", fmt.Errorf("unable to infer User-Agent from ClientProfile")
 }
 
-// inferBrowserFromProfileName 从 profile 名称推断浏览器类型
-func inferBrowserFromProfileName(profileName string) (string, string) {
-	profileName = strings.ToLower(profileName)
+// findProfileName 在 DefaultRegistry 中查找 ClientHelloStr 匹配的 profile 名称
+// 按名称顺序查找，内置 profile 优先于通过 LoadDir/LoadFS 加载的 profile
+func findProfileName(helloStr string) (string, bool) {
+	builtin, loaded := "", ""
+	profiles.DefaultRegistry.Range(func(name string, p ClientProfile) bool {
+		if p.GetClientHelloStr() != helloStr {
+			return true
+		}
+		if _, ok := profiles.Definition(name); !ok {
+			builtin = name
+			return false
+		}
+		if loaded == "" {
+			loaded = name
+		}
+		return true
+	})
 
-	if strings.HasPrefix(profileName, "chrome_") {
-		version := strings.TrimPrefix(profileName, "chrome_")
-		// 移除特殊后缀
-		version = strings.Split(version, "_")[0]
-		return string(BrowserChrome), version
-	} else if strings.HasPrefix(profileName, "firefox_") {
-		version := strings.TrimPrefix(profileName, "firefox_")
-		return string(BrowserFirefox), version
-	} else if strings.HasPrefix(profileName, "safari_") {
-		version := strings.TrimPrefix(profileName, "safari_")
-		return string(BrowserSafari), version
-	} else if strings.HasPrefix(profileName, "opera_") {
-		version := strings.TrimPrefix(profileName, "opera_")
-		return string(BrowserOpera), version
+	if builtin != "" {
+		return builtin, true
 	}
-
-	return string(BrowserChrome), "" // 默认返回 Chrome
+	return loaded, loaded != ""
 }
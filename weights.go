@@ -0,0 +1,455 @@
+package fingerprint
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/vistone/fingerprint/internal/utils"
+	"github.com/vistone/fingerprint/profiles"
+	"gopkg.in/yaml.v3"
+)
+
+// Weights 随机选择 profile、操作系统和语言时使用的权重
+// 权重是相对值，不需要归一化；权重为 0 的项不会被选中
+//
+// 配置文件格式（JSON 或 YAML）：
+//
+//	default_profile_weight: 0.1        # 未在 profiles 中列出的 profile 的权重
+//	profiles:
+//	  chrome_133: 30
+//	  safari_ios_18_5: 8
+//	operating_systems:                 # 键为 OperatingSystem 的名称（见 ParseOperatingSystem）
+//	  windows-10-x86_64: 40
+//	  windows-11-x86_64: 30
+//	  macos-15.1.1-arm64: 8
+//	languages:                         # 键为逗号分隔、按偏好排序的 locale 列表（见 ParseLocales）
+//	  "en-US": 40
+//	  "de-CH,fr,en": 0.5
+//
+// operating_systems 为空时从 OperatingSystems 列表中选择，languages 为空时从 Languages 列表中选择，
+// 列表中的项按内置的市场份额加权（见 OSWeight、LanguageWeight）；
+// languages 的键也可以是 Accept-Language 格式，q 值会被忽略，Accept-Language 按浏览器的规则重新生成
+type Weights struct {
+	Profiles             map[string]float64          `json:"profiles,omitempty" yaml:"profiles,omitempty"`
+	DefaultProfileWeight float64                     `json:"default_profile_weight" yaml:"default_profile_weight"`
+	OperatingSystems     map[OperatingSystem]float64 `json:"operating_systems,omitempty" yaml:"operating_systems,omitempty"`
+	Languages            map[string]float64          `json:"languages,omitempty" yaml:"languages,omitempty"`
+}
+
+// osMarketShares 按市场份额估算的操作系统权重，OperatingSystems 权重为空时用于 OperatingSystems 列表中的操作系统
+var osMarketShares = map[OperatingSystem]float64{
+	OSWindows10: 40,
+	OSWindows11: 30,
+	OSMacOS15:   8,
+	OSMacOS14:   5,
+	OSMacOS13:   3,
+	OSMacOS12:   2,
+	OSLinux:     4, // OSLinuxUbuntu、OSLinuxDebian 与 OSLinux 相同
+}
+
+// languageMarketShares 按市场份额估算的语言权重（键为逗号分隔的 locale 列表），Languages 权重为空时用于 Languages 列表中的语言
+var languageMarketShares = map[string]float64{
+	"en-US":          40,
+	"zh-CN,en":       12,
+	"es-ES,en":       5,
+	"es-MX,en":       2,
+	"fr-FR,en":       5,
+	"de-DE,en":       5,
+	"ja-JP,en":       4,
+	"pt-BR,en":       4,
+	"ru-RU,en":       4,
+	"en-GB,en-US":    3,
+	"ar-SA,en":       2,
+	"ko-KR,en":       2,
+	"it-IT,en":       2,
+	"tr-TR,en":       1.5,
+	"pl-PL,en":       1.5,
+	"nl-NL,en":       1,
+	"vi-VN,en":       1,
+	"id-ID,en":       1,
+	"hi-IN,en":       1,
+	"zh-TW,en":       1,
+	"sv-SE,en":       0.5,
+	"th-TH,en":       0.5,
+	"cs-CZ,en":       0.5,
+	"ro-RO,en":       0.5,
+	"hu-HU,en":       0.5,
+	"el-GR,en":       0.5,
+	"da-DK,en":       0.5,
+	"fi-FI,en":       0.5,
+	"no-NO,en":       0.5,
+	"he-IL,en":       0.5,
+	"uk-UA,en":       0.3,
+	"uk-UA,ru,en-US": 0.2,
+	"pt-PT,en":       0.5,
+	"de-CH,fr,en":    0.5,
+	"fr-CA,en-CA":    0.5,
+}
+
+// unlistedShare 列表中没有内置市场份额的操作系统和语言的权重
+const unlistedShare = 1.0
+
+var (
+	weightsMu      sync.RWMutex
+	currentWeights = DefaultWeights()
+)
+
+// DefaultWeights 返回按真实浏览器市场份额估算的默认权重
+// 新版本浏览器权重高，旧版本权重很低；应用 profile（如 okhttp4_android_13）的权重为 0（见 ProfileWeight）
+// 默认权重不包含操作系统和语言权重，从 OperatingSystems 和 Languages 列表中按内置的市场份额选择，
+// 修改这两个列表会影响默认的随机选择
+func DefaultWeights() *Weights {
+	return &Weights{
+		Profiles: map[string]float64{
+			"chrome_133":        30,
+			"chrome_133_PSK":    10,
+			"chrome_131":        12,
+			"chrome_131_PSK":    4,
+			"chrome_130_PSK":    3,
+			"chrome_124":        5,
+			"chrome_120":        4,
+			"chrome_117":        1.5,
+			"chrome_116_PSK":    0.5,
+			"chrome_116_PSK_PQ": 0.5,
+			"chrome_112":        0.2,
+			"chrome_111":        0.2,
+			"chrome_110":        0.2,
+			"chrome_109":        0.2,
+			"chrome_108":        0.2,
+			"chrome_107":        0.2,
+			"chrome_106":        0.2,
+			"chrome_105":        0.2,
+			"chrome_104":        0.2,
+			"chrome_103":        0.2,
+			"safari_ios_18_5":   8,
+			"safari_ios_18_0":   5,
+			"safari_ios_17_0":   3,
+			"safari_ios_16_0":   1,
+			"safari_ios_15_6":   0.3,
+			"safari_ios_15_5":   0.2,
+			"safari_ipad_15_6":  0.3,
+			"safari_16_0":       1.5,
+			"safari_15_6_1":     0.5,
+			"firefox_135":       3,
+			"firefox_133":       1.5,
+			"firefox_132":       1,
+			"firefox_123":       0.3,
+			"firefox_120":       0.3,
+			"firefox_117":       0.2,
+			"firefox_110":       0.05,
+			"firefox_108":       0.05,
+			"firefox_106":       0.05,
+			"firefox_105":       0.05,
+			"firefox_104":       0.05,
+			"firefox_102":       0.05,
+			"opera_91":          0.1,
+			"edge_133":          4,
+			"edge_131":          1.5,
+			"edge_android_131":  0.3,
+			"opera_90":          0.1,
+			"opera_89":          0.1,
+		},
+		DefaultProfileWeight: 0.1,
+	}
+}
+
+// LoadWeights 从 JSON 或 YAML 文件加载权重
+func LoadWeights(path string) (*Weights, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	w, err := ParseWeights(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return w, nil
+}
+
+// ParseWeights 解析 JSON 或 YAML 格式的权重配置，格式见 Weights
+func ParseWeights(data []byte) (*Weights, error) {
+	// JSON 是 YAML 的子集，统一使用 YAML 解析
+	var w Weights
+	if err := yaml.Unmarshal(data, &w); err != nil {
+		return nil, err
+	}
+	if err := w.Validate(); err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// Validate 检查权重是否有效（不能为负数）
+func (w *Weights) Validate() error {
+	if w.DefaultProfileWeight < 0 {
+		return fmt.Errorf("negative default profile weight %v", w.DefaultProfileWeight)
+	}
+	for name, weight := range w.Profiles {
+		if weight < 0 {
+			return fmt.Errorf("negative weight %v for profile %q", weight, name)
+		}
+	}
+	for os, weight := range w.OperatingSystems {
+		if weight < 0 {
+			return fmt.Errorf("negative weight %v for operating system %q", weight, os)
+		}
+	}
+	for language, weight := range w.Languages {
+		if weight < 0 {
+			return fmt.Errorf("negative weight %v for language %q", weight, language)
+		}
+		if len(ParseLocales(language)) == 0 {
+			return fmt.Errorf("empty locale list %q", language)
+		}
+	}
+	return nil
+}
+
+// SetWeights 设置 GetWeightedRandomFingerprint、RandomOS 和 RandomLanguage 使用的权重
+// w 为 nil 时恢复为 DefaultWeights；设置后不应再修改 w
+func SetWeights(w *Weights) error {
+	if w == nil {
+		w = DefaultWeights()
+	}
+	if err := w.Validate(); err != nil {
+		return err
+	}
+	weightsMu.Lock()
+	currentWeights = w
+	weightsMu.Unlock()
+	return nil
+}
+
+// GetWeights 返回当前使用的权重，返回值应视为只读
+func GetWeights() *Weights {
+	weightsMu.RLock()
+	defer weightsMu.RUnlock()
+	return currentWeights
+}
+
+// ProfileWeight 返回 DefaultRegistry 中 profile 的权重，未列出的 profile 使用 DefaultProfileWeight
+// 应用 profile（Metadata().App 不为空，包括运行时加载的）不是浏览器流量，未列出时权重为 0
+func (w *Weights) ProfileWeight(name string) float64 {
+	profile, _ := profiles.DefaultRegistry.Get(name)
+	return w.profileWeight(name, profile)
+}
+
+// profileWeight 返回 profile 的权重，规则见 ProfileWeight
+func (w *Weights) profileWeight(name string, profile ClientProfile) float64 {
+	if weight, ok := w.Profiles[name]; ok {
+		return weight
+	}
+	if profile.Metadata().App != "" {
+		return 0
+	}
+	return w.DefaultProfileWeight
+}
+
+// ChooseProfile 按权重从 DefaultRegistry 的 names 中选择一个 profile，所有权重都为 0 时返回 false
+// rng 为 nil 时使用全局随机数生成器；相同种子的 rng 和相同的 names 总是得到相同的结果
+func (w *Weights) ChooseProfile(rng *rand.Rand, names []string) (string, bool) {
+	return w.chooseProfile(rng, names, profiles.DefaultRegistry.Get)
+}
+
+// chooseProfile 按权重从 names 中选择一个 profile，lookup 返回名称对应的 profile（用于识别应用 profile）
+func (w *Weights) chooseProfile(rng *rand.Rand, names []string, lookup func(name string) (ClientProfile, bool)) (string, bool) {
+	sorted := append([]string(nil), names...)
+	sort.Strings(sorted)
+
+	weights := make([]float64, len(sorted))
+	for i, name := range sorted {
+		profile, _ := lookup(name)
+		weights[i] = w.profileWeight(name, profile)
+	}
+	i := utils.WeightedIndex(rng, weights)
+	if i < 0 {
+		return "", false
+	}
+	return sorted[i], true
+}
+
+// OSWeight 返回操作系统的权重
+// OperatingSystems 权重为空时，OperatingSystems 列表中的操作系统使用内置的市场份额（没有份额的为 1），其他为 0
+func (w *Weights) OSWeight(os OperatingSystem) float64 {
+	if len(w.OperatingSystems) > 0 {
+		return w.OperatingSystems[os]
+	}
+	for _, listed := range OperatingSystems {
+		if listed == os {
+			return marketShare(osMarketShares, os)
+		}
+	}
+	return 0
+}
+
+// LanguageWeight 返回逗号分隔、按偏好排序的 locale 列表（如 "zh-CN,en"）的权重
+// Languages 权重为空时，Languages 列表中的语言使用内置的市场份额（没有份额的为 1），其他为 0
+func (w *Weights) LanguageWeight(language string) float64 {
+	if len(w.Languages) > 0 {
+		return w.Languages[language]
+	}
+	for _, listed := range Languages {
+		if strings.Join(listed, ",") == language {
+			return marketShare(languageMarketShares, language)
+		}
+	}
+	return 0
+}
+
+// marketShare 返回列表中一项的内置市场份额，没有份额的为 unlistedShare
+func marketShare[K comparable](shares map[K]float64, key K) float64 {
+	if share, ok := shares[key]; ok {
+		return share
+	}
+	return unlistedShare
+}
+
+// ChooseOS 按权重选择操作系统（见 OSWeight），rng 为 nil 时使用全局随机数生成器
+func (w *Weights) ChooseOS(rng *rand.Rand) OperatingSystem {
+	if os := w.chooseOS(rng, nil); !os.IsZero() {
+		return os
+	}
+	return OSWindows10 // 默认返回 Windows 10
+}
+
+// ChooseOSFor 按权重选择与 metadata 描述的浏览器兼容的操作系统（见 CompatibleOS），如桌面 Safari 只会得到 macOS
+// 兼容的操作系统都没有权重时从 OperatingSystems 列表中兼容的操作系统中均匀选择；
+// 移动端 profile 不使用操作系统，返回零值；没有兼容的操作系统时也返回零值
+func (w *Weights) ChooseOSFor(rng *rand.Rand, metadata Metadata) OperatingSystem {
+	if metadata.Mobile() {
+		return OperatingSystem{}
+	}
+	return w.chooseOS(rng, func(os OperatingSystem) bool { return compatibleOS(metadata, os) })
+}
+
+// osCandidates 返回可以按权重选择的操作系统：OperatingSystems 权重中的操作系统，为空时为 OperatingSystems 列表（去重）
+func (w *Weights) osCandidates() []OperatingSystem {
+	seen := make(map[OperatingSystem]bool)
+	systems := make([]OperatingSystem, 0, len(OperatingSystems))
+	if len(w.OperatingSystems) > 0 {
+		for os := range w.OperatingSystems {
+			systems = append(systems, os)
+		}
+	} else {
+		for _, os := range OperatingSystems {
+			if !seen[os] {
+				seen[os] = true
+				systems = append(systems, os)
+			}
+		}
+	}
+	sort.Slice(systems, func(i, j int) bool { return systems[i].String() < systems[j].String() })
+	return systems
+}
+
+// chooseOS 按权重选择 accept 接受的操作系统（accept 为 nil 时接受所有操作系统），都没有权重时从 OperatingSystems 列表中均匀选择
+// 没有可选的操作系统时返回零值
+func (w *Weights) chooseOS(rng *rand.Rand, accept func(OperatingSystem) bool) OperatingSystem {
+	systems := make([]OperatingSystem, 0, len(OperatingSystems))
+	weights := make([]float64, 0, len(OperatingSystems))
+	for _, os := range w.osCandidates() {
+		if accept != nil && !accept(os) {
+			continue
+		}
+		systems = append(systems, os)
+		// 候选来自 OperatingSystems 列表时直接使用市场份额，与 OSWeight 相同
+		if len(w.OperatingSystems) > 0 {
+			weights = append(weights, w.OperatingSystems[os])
+		} else {
+			weights = append(weights, marketShare(osMarketShares, os))
+		}
+	}
+	if i := utils.WeightedIndex(rng, weights); i >= 0 {
+		return systems[i]
+	}
+
+	systems = systems[:0]
+	for _, os := range OperatingSystems {
+		if accept == nil || accept(os) {
+			systems = append(systems, os)
+		}
+	}
+	if len(systems) == 0 {
+		return OperatingSystem{}
+	}
+	return systems[randomIndex(rng, len(systems))]
+}
+
+// ChooseLanguage 按权重选择按偏好排序的 locale 列表（见 LanguageWeight），都没有权重时从 Languages 列表中均匀选择
+// rng 为 nil 时使用全局随机数生成器
+func (w *Weights) ChooseLanguage(rng *rand.Rand) []string {
+	languages := make([]string, 0, len(Languages))
+	weights := make([]float64, 0, len(Languages))
+	if len(w.Languages) > 0 {
+		for language := range w.Languages {
+			languages = append(languages, language)
+		}
+		sort.Strings(languages)
+		for _, language := range languages {
+			weights = append(weights, w.Languages[language])
+		}
+	} else {
+		// 与 LanguageWeight 相同，Languages 列表中的语言使用市场份额
+		for _, listed := range Languages {
+			language := strings.Join(listed, ",")
+			languages = append(languages, language)
+			weights = append(weights, marketShare(languageMarketShares, language))
+		}
+	}
+	if i := utils.WeightedIndex(rng, weights); i >= 0 {
+		return ParseLocales(languages[i])
+	}
+
+	if len(Languages) == 0 {
+		return append([]string(nil), defaultLocales...) // 默认返回英语
+	}
+	return append([]string(nil), Languages[randomIndex(rng, len(Languages))]...)
+}
+
+// randomIndex 返回 [0, n) 范围内的随机下标，rng 为 nil 时使用全局随机数生成器
+func randomIndex(rng *rand.Rand, n int) int {
+	if rng != nil {
+		return rng.Intn(n)
+	}
+	return utils.GetGlobalRandGenerator().Intn(n)
+}
+
+// GetWeightedRandomFingerprint 按当前权重（见 SetWeights）随机获取一个指纹和对应的 User-Agent
+// profile 和操作系统都按权重选择
+func GetWeightedRandomFingerprint() (*FingerprintResult, error) {
+	return defaultFingerprintGenerator.GetWeightedRandomFingerprint()
+}
+
+// GetWeightedRandomFingerprint 按 Generator 的权重随机获取一个指纹和对应的 User-Agent
+// profile 和操作系统都按权重选择
+func (g *Generator) GetWeightedRandomFingerprint() (*FingerprintResult, error) {
+	defer g.lock()()
+
+	w := g.activeWeights()
+	snapshot := g.registry.Snapshot()
+	names := make([]string, 0, len(snapshot))
+	for name := range snapshot {
+		names = append(names, name)
+	}
+
+	if g.persona != nil {
+		return g.personaFingerprint(snapshot, names, OperatingSystem{}, "", true)
+	}
+	name, ok := w.chooseProfile(g.rng, names, snapshotLookup(snapshot))
+	if !ok {
+		return nil, fmt.Errorf("no TLS client profiles with positive weight available")
+	}
+	return g.newFingerprintResult(name, snapshot[name], OperatingSystem{})
+}
+
+// snapshotLookup 返回在注册表快照中查找 profile 的函数
+func snapshotLookup(snapshot map[string]ClientProfile) func(name string) (ClientProfile, bool) {
+	return func(name string) (ClientProfile, bool) {
+		profile, ok := snapshot[name]
+		return profile, ok
+	}
+}